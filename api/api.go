@@ -0,0 +1,10 @@
+// Package api holds the contracts of the public APIs.
+package api
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document of the REST API. It is maintained by hand next to the handlers,
+// which validate incoming requests against it and whose tests check their routes and responses against it.
+//
+//go:embed openapi.json
+var OpenAPI []byte
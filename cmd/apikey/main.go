@@ -0,0 +1,144 @@
+// Command apikey issues, lists and revokes the API keys clients authenticate with.
+//
+// Usage:
+//
+//	apikey issue -name <name> -scopes wallets:read,wallets:write
+//	apikey list
+//	apikey revoke -id <key id>
+//
+// It reads the database connection settings from config.env like the server does.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"cmd/app/main.go/internal/app"
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/config"
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var scopes = []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite, auth.ScopeAdmin}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	cfg := config.GetConfig()
+	pool := app.ConnectToDB(cfg)
+	defer pool.Close()
+
+	storage := db.New(pool)
+	ctx := context.Background()
+
+	var err error
+	switch os.Args[1] {
+	case "issue":
+		err = issue(ctx, storage, os.Args[2:])
+	case "list":
+		err = list(ctx, storage)
+	case "revoke":
+		err = revoke(ctx, storage, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatalln("apikey err:", err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: apikey issue -name <name> -scopes <scope,...> | list | revoke -id <key id>")
+	fmt.Fprintln(os.Stderr, "scopes:", strings.Join(scopes, ", "))
+	os.Exit(2)
+}
+
+// issue stores a new key and prints it. The key cannot be retrieved later.
+func issue(ctx context.Context, storage db.Storage, args []string) error {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	name := fs.String("name", "", "name of the client the key is issued to")
+	list := fs.String("scopes", auth.ScopeWalletsRead, "comma-separated scopes granted to the key")
+	fs.Parse(args)
+
+	if *name == "" {
+		return errors.New("name is required")
+	}
+	granted := strings.Split(*list, ",")
+	for _, s := range granted {
+		if !slices.Contains(scopes, s) {
+			return fmt.Errorf("unknown scope %q", s)
+		}
+	}
+
+	key, err := auth.GenerateKey()
+	if err != nil {
+		return err
+	}
+	k := model.APIKey{
+		ID:     uuid.New(),
+		Name:   *name,
+		Hash:   auth.HashKey(key),
+		Scopes: granted,
+	}
+	err = storage.CreateAPIKey(ctx, k)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("id: ", k.ID)
+	fmt.Println("key:", key)
+	fmt.Println("store the key now, it cannot be shown again")
+	return nil
+}
+
+func list(ctx context.Context, storage db.Storage) error {
+	keys, err := storage.APIKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED\tREVOKED")
+	for _, k := range keys {
+		revoked := "-"
+		if k.RevokedAt != nil {
+			revoked = k.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", k.ID, k.Name, strings.Join(k.Scopes, ","), k.CreatedAt.Format(time.RFC3339), revoked)
+	}
+	return w.Flush()
+}
+
+func revoke(ctx context.Context, storage db.Storage, args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := fs.String("id", "", "id of the key to revoke")
+	fs.Parse(args)
+
+	parsed, err := uuid.Parse(*id)
+	if err != nil {
+		return fmt.Errorf("invalid id: %w", err)
+	}
+	err = storage.RevokeAPIKey(ctx, parsed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("no active key with this id")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("revoked", parsed)
+	return nil
+}
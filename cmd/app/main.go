@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
+
 	"cmd/app/main.go/internal/app"
 	"cmd/app/main.go/internal/config"
 	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/handler"
 	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/internal/webhook"
 )
 
 func main() {
@@ -13,15 +17,47 @@ func main() {
 	pool := app.ConnectToDB(cfg)
 	defer pool.Close()
 
-	storage := db.New(pool)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := db.New(pool, db.WithFrozenCredits(cfg.Wallet.FrozenAcceptsCredits))
+
+	opts := []service.Option{
+		service.WithIdempotencyRetention(cfg.Idempotency.Retention),
+		service.WithDefaultCurrency(cfg.Wallet.DefaultCurrency),
+		service.WithHoldTTL(cfg.Holds.DefaultTTL),
+		service.WithFrozenCredits(cfg.Wallet.FrozenAcceptsCredits),
+		service.WithUniqueOwnerCurrency(cfg.Wallet.UniqueOwnerCurrency),
+		service.WithAudit(),
+	}
+	opts = append(opts, app.SetupRates(ctx, cfg)...)
+
+	ws := service.New(storage, opts...)
+
+	app.StartHoldSweeper(ctx, ws, cfg.Holds.SweepInterval)
+	app.StartIdempotencyPurger(ctx, ws, cfg.Idempotency.PurgeInterval)
+	app.StartOutboxRelay(ctx, cfg, storage, webhook.NewPublisher(storage))
+	webhooks := app.StartWebhookWorkers(ctx, cfg, storage)
+	broker := app.StartStreamBroker(ctx, cfg, storage)
+
+	authenticators := app.SetupAuthenticators(cfg, storage)
+
+	hopts := app.SetupAuth(authenticators)
+	hopts = append(hopts, app.SetupSigning(cfg)...)
+	limiter := app.NewRateLimiter(cfg, storage)
+	hopts = append(hopts, app.SetupRateLimits(cfg, limiter)...)
+	hopts = append(hopts, app.SetupStream(cfg, broker)...)
 
-	ws := service.New(storage)
+	sockets := app.SetupSocket(cfg, ws, broker, limiter)
+	hopts = append(hopts, handler.WithSocket(sockets))
 
-	router := app.SetupRouter(ws)
+	router := app.SetupRouter(cfg, ws, hopts...)
 
 	srv := app.SetupServer(cfg, router)
+	srv.RegisterOnShutdown(broker.Close)
 
 	app.StartServer(srv)
+	grpcSrv := app.StartGRPCServer(cfg, ws, authenticators, limiter)
 
-	app.HandleQuit(srv)
+	app.HandleQuit(srv, grpcSrv, sockets, webhooks)
 }
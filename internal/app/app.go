@@ -1,12 +1,22 @@
 package app
 
 import (
+	"cmd/app/main.go/internal/auth"
 	"cmd/app/main.go/internal/config"
 	"cmd/app/main.go/internal/handler"
+	"cmd/app/main.go/internal/outbox"
+	"cmd/app/main.go/internal/ratelimit"
+	"cmd/app/main.go/internal/rates"
+	"cmd/app/main.go/internal/rpc"
 	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/internal/socket"
+	"cmd/app/main.go/internal/stream"
+	"cmd/app/main.go/internal/webhook"
+	"cmd/app/main.go/pkg/money"
 	"cmd/app/main.go/pkg/postgres"
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -17,10 +27,14 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// SetupRouter configures and returns a gin.Engine instance with registered wallet handlers.
-func SetupRouter(ws service.Wallet) *gin.Engine {
+// SetupRouter configures and returns a gin.Engine instance with registered wallet handlers. Client addresses are
+// taken from X-Forwarded-For only on requests coming from the proxies set by TRUSTED_PROXIES.
+func SetupRouter(cfg *config.Config, ws service.Wallet, opts ...handler.Option) *gin.Engine {
 	r := gin.Default()
-	h := handler.New(r, ws)
+	if err := r.SetTrustedProxies(cfg.Listen.TrustedProxies); err != nil {
+		log.Fatalf("Trusted proxies err: %v", err)
+	}
+	h := handler.New(r, ws, opts...)
 	h.Register()
 	return r
 }
@@ -46,8 +60,47 @@ func StartServer(s *http.Server) {
 	}()
 }
 
-// HandleQuit gracefully shuts down the server when receiving SIGINT or SIGTERM signals.
-func HandleQuit(s *http.Server) {
+// StartGRPCServer starts the gRPC server exposing ws on the port set by GRPC_PORT, authenticating calls
+// with the authenticators of the REST API. Like on the REST API, partners sign their transactions and calls are
+// throttled per client and per wallet in the buckets of limiter. The server is shut down by HandleQuit.
+func StartGRPCServer(cfg *config.Config, ws service.Wallet, authenticators []auth.Authenticator, limiter ratelimit.Limiter) *rpc.Server {
+	var opts []rpc.Option
+	for _, a := range authenticators {
+		opts = append(opts, rpc.WithAuthenticator(a))
+	}
+	if len(cfg.Signing.Secrets) > 0 {
+		opts = append(opts, rpc.WithSignatures(auth.NewSignatures(cfg.Signing.Secrets, cfg.Signing.Window)))
+	}
+	if rl := cfg.RateLimit; rl.ClientRate > 0 || rl.WalletRate > 0 {
+		opts = append(opts, rpc.WithRateLimits(limiter,
+			ratelimit.Limit{Rate: rl.ClientRate, Burst: rl.ClientBurst},
+			ratelimit.Limit{Rate: rl.WalletRate, Burst: rl.WalletBurst},
+		))
+	}
+	s := rpc.New(ws, opts...)
+
+	l, err := net.Listen("tcp", cfg.Listen.GRPCAddr)
+	if err != nil {
+		log.Fatalf("gRPC server listen err: %v", err)
+	}
+	go func() {
+		log.Println("gRPC server is listening: ", cfg.Listen.GRPCAddr)
+		err := s.Serve(l)
+		if err != nil {
+			log.Fatalf("gRPC server err: %v", err)
+		}
+	}()
+	return s
+}
+
+// Worker is a background process shut down along with the server.
+type Worker interface {
+	Shutdown(ctx context.Context) error
+}
+
+// HandleQuit gracefully shuts down the server when receiving SIGINT or SIGTERM signals,
+// then the workers, so that work started by the last requests is finished.
+func HandleQuit(s *http.Server, workers ...Worker) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -60,6 +113,12 @@ func HandleQuit(s *http.Server) {
 	if err != nil {
 		log.Fatalf("Server shutdown err: %v", err)
 	}
+	for _, w := range workers {
+		err = w.Shutdown(ctx)
+		if err != nil {
+			log.Println("Worker shutdown err: ", err)
+		}
+	}
 	log.Println("Application shutdown complete")
 }
 
@@ -78,3 +137,255 @@ func ConnectToDB(cfg *config.Config) *pgxpool.Pool {
 	log.Println("Ping to database OK")
 	return pgxPool
 }
+
+// SetupRates returns the service options enabling transfers between currencies.
+// Rates are read from the file set in configuration and reloaded on change until ctx is done.
+// No options are returned if no rates file is configured.
+func SetupRates(ctx context.Context, cfg *config.Config) []service.Option {
+	if cfg.Rates.File == "" {
+		return nil
+	}
+	mode, err := money.ParseRoundingMode(cfg.Rates.RoundingMode)
+	if err != nil {
+		log.Fatalln("rates rounding mode err:", err)
+	}
+	p, err := rates.NewFileProvider(cfg.Rates.File)
+	if err != nil {
+		log.Fatalln("cant load rates, err:", err)
+	}
+	log.Println("Rates loaded from: ", cfg.Rates.File)
+
+	go p.Watch(ctx, cfg.Rates.ReloadInterval)
+
+	return []service.Option{
+		service.WithRateProvider(p),
+		service.WithRoundingMode(mode),
+	}
+}
+
+// SetupAuth returns the handler options authenticating requests with authenticators.
+// No options are returned without authenticators, leaving the API open.
+func SetupAuth(authenticators []auth.Authenticator) []handler.Option {
+	var opts []handler.Option
+	for _, a := range authenticators {
+		opts = append(opts, handler.WithAuthenticator(a))
+	}
+	return opts
+}
+
+// SetupAuthenticators returns the authenticators recognizing the API keys kept in storage and,
+// when verification keys are configured, JWT bearer tokens of end users.
+// None are returned if authentication is disabled in configuration.
+func SetupAuthenticators(cfg *config.Config, keys auth.KeyStore) []auth.Authenticator {
+	if !cfg.Auth.Enabled {
+		log.Println("Authentication is disabled")
+		return nil
+	}
+	res := []auth.Authenticator{auth.NewAPIKeys(keys)}
+
+	jwtCfg := cfg.Auth.JWT
+	var publicKeys []auth.PublicKey
+	if jwtCfg.JWKSFile != "" {
+		k, err := auth.LoadJWKS(jwtCfg.JWKSFile)
+		if err != nil {
+			log.Fatalln("cant load jwks, err:", err)
+		}
+		publicKeys = append(publicKeys, k...)
+	}
+	if len(jwtCfg.PublicKeys) > 0 {
+		k, err := auth.LoadPublicKeys(jwtCfg.PublicKeys)
+		if err != nil {
+			log.Fatalln("cant load jwt public keys, err:", err)
+		}
+		publicKeys = append(publicKeys, k...)
+	}
+	if len(publicKeys) > 0 {
+		log.Println("JWT verification keys loaded: ", len(publicKeys))
+		res = append(res, auth.NewJWT(publicKeys,
+			auth.WithIssuer(jwtCfg.Issuer),
+			auth.WithAudience(jwtCfg.Audience),
+			auth.WithLeeway(jwtCfg.Leeway),
+		))
+	}
+	return res
+}
+
+// SetupSigning returns the handler options requiring partners to sign transactions
+// with the secrets set in configuration. No options are returned if no secrets are configured.
+func SetupSigning(cfg *config.Config) []handler.Option {
+	if len(cfg.Signing.Secrets) == 0 {
+		return nil
+	}
+	log.Println("Request signing enabled for partners: ", len(cfg.Signing.Secrets))
+	return []handler.Option{
+		handler.WithSignatures(auth.NewSignatures(cfg.Signing.Secrets, cfg.Signing.Window)),
+	}
+}
+
+// SetupRateLimits returns the handler options throttling clients and wallets as set in configuration
+// with the limiter shared by all the APIs.
+func SetupRateLimits(cfg *config.Config, l ratelimit.Limiter) []handler.Option {
+	rl := cfg.RateLimit
+	if rl.ClientRate <= 0 && rl.WalletRate <= 0 {
+		log.Println("Rate limiting is disabled")
+		return nil
+	}
+
+	return []handler.Option{
+		handler.WithRateLimits(l,
+			ratelimit.Limit{Rate: rl.ClientRate, Burst: rl.ClientBurst},
+			ratelimit.Limit{Rate: rl.WalletRate, Burst: rl.WalletBurst},
+		),
+	}
+}
+
+// StartHoldSweeper periodically releases expired holds until ctx is done.
+func StartHoldSweeper(ctx context.Context, ws service.Wallet, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			n, err := ws.ExpireHolds(ctx)
+			if err != nil {
+				log.Println("hold sweeper err: ", err)
+				continue
+			}
+			if n > 0 {
+				log.Println("hold sweeper released expired holds: ", n)
+			}
+		}
+	}()
+}
+
+// StartIdempotencyPurger periodically deletes expired idempotency keys until ctx is done.
+func StartIdempotencyPurger(ctx context.Context, ws service.Wallet, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			n, err := ws.PurgeIdempotencyKeys(ctx)
+			if err != nil {
+				log.Println("idempotency purger err: ", err)
+				continue
+			}
+			if n > 0 {
+				log.Println("idempotency purger deleted expired keys: ", n)
+			}
+		}
+	}()
+}
+
+// NewRateLimiter returns the limiter counting requests in the store set by RATE_LIMIT_STORE. Buckets are kept
+// in memory, or in storage when RATE_LIMIT_STORE is postgres so that limits hold across replicas. A single limiter
+// is shared by the REST, gRPC and WebSocket APIs so that a client has the same buckets on all of them.
+func NewRateLimiter(cfg *config.Config, store ratelimit.Store) ratelimit.Limiter {
+	switch cfg.RateLimit.Store {
+	case "memory":
+		return ratelimit.NewMemory()
+	case "postgres":
+		return ratelimit.NewPostgres(store)
+	default:
+		log.Fatalln("unknown rate limit store:", cfg.RateLimit.Store)
+		return nil
+	}
+}
+
+// StartOutboxRelay publishes the events of the outbox with the publisher set in configuration,
+// followed by the extra publishers, until ctx is done. Events are written as JSON lines
+// to the standard output, or appended to a file when OUTBOX_PUBLISHER is file.
+func StartOutboxRelay(ctx context.Context, cfg *config.Config, store outbox.Store, extra ...outbox.Publisher) {
+	var p *outbox.Writer
+	switch cfg.Outbox.Publisher {
+	case "stdout":
+		p = outbox.NewWriter(os.Stdout)
+	case "file":
+		var err error
+		p, err = outbox.OpenFile(cfg.Outbox.File)
+		if err != nil {
+			log.Fatalln("cant open outbox file, err:", err)
+		}
+		log.Println("Outbox events are written to: ", cfg.Outbox.File)
+	default:
+		log.Fatalln("unknown outbox publisher:", cfg.Outbox.Publisher)
+	}
+
+	r := outbox.NewRelay(store, append(outbox.Publishers{p}, extra...),
+		outbox.WithInterval(cfg.Outbox.PollInterval),
+		outbox.WithBatchSize(cfg.Outbox.BatchSize),
+	)
+	go func() {
+		defer p.Close()
+		r.Run(ctx)
+	}()
+}
+
+// StartWebhookWorkers starts the workers delivering webhooks as set in configuration.
+// The returned dispatcher is shut down by HandleQuit.
+func StartWebhookWorkers(ctx context.Context, cfg *config.Config, store webhook.Store) *webhook.Dispatcher {
+	wh := cfg.Webhooks
+	d := webhook.NewDispatcher(store,
+		webhook.WithWorkers(wh.Workers),
+		webhook.WithInterval(wh.PollInterval),
+		webhook.WithTimeout(wh.Timeout),
+		webhook.WithMaxAttempts(wh.MaxAttempts),
+		webhook.WithBackoff(wh.Backoff, wh.MaxBackoff),
+	)
+	d.Start(ctx)
+	log.Println("Webhook workers started: ", wh.Workers)
+	return d
+}
+
+// StartStreamBroker starts the broker delivering the events announced by storage to the streams of wallets
+// until ctx is done. The broker must be closed when the server shuts down for the streams to end.
+func StartStreamBroker(ctx context.Context, cfg *config.Config, store stream.Store) *stream.Broker {
+	sc := cfg.Stream
+	b := stream.NewBroker(store,
+		stream.WithBuffer(sc.Buffer),
+		stream.WithMaxStreams(sc.MaxStreams, sc.MaxClientStreams),
+	)
+	go b.Run(ctx)
+	return b
+}
+
+// SetupStream returns the handler options serving the events of wallets delivered by b as set in configuration.
+func SetupStream(cfg *config.Config, b *stream.Broker) []handler.Option {
+	return []handler.Option{
+		handler.WithStream(b, cfg.Stream.Heartbeat, cfg.Stream.MaxDuration),
+	}
+}
+
+// SetupSocket returns the server of the WebSocket API performing operations with ws and pushing the events
+// delivered by b, as set in configuration. Operations are throttled per wallet in the buckets of l shared with
+// the REST API, and refused to partners required to sign them. The server is shut down by HandleQuit.
+func SetupSocket(cfg *config.Config, ws service.Wallet, b *stream.Broker, l ratelimit.Limiter) *socket.Server {
+	sc := cfg.Socket
+	opts := []socket.Option{
+		socket.WithBuffer(sc.Buffer),
+		socket.WithMaxInFlight(sc.MaxInFlight),
+		socket.WithMaxSubscriptions(sc.MaxSubscriptions),
+		socket.WithWriteTimeout(sc.WriteTimeout),
+	}
+	if len(cfg.Signing.Secrets) > 0 {
+		opts = append(opts, socket.WithSignedOperations())
+	}
+	if rl := cfg.RateLimit; rl.WalletRate > 0 {
+		opts = append(opts, socket.WithRateLimit(l,
+			ratelimit.Limit{Rate: rl.WalletRate, Burst: rl.WalletBurst},
+		))
+	}
+	return socket.New(ws, b, opts...)
+}
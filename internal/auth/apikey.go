@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// APIKeyHeader is the request header carrying the API key.
+const APIKeyHeader = "X-API-Key"
+
+// keyPrefix marks API keys issued by this service.
+const keyPrefix = "wk_"
+
+// KeyStore looks up active API keys by their hash.
+type KeyStore interface {
+	APIKeyByHash(ctx context.Context, hash string) (model.APIKey, error)
+}
+
+// APIKeys authenticates requests by the API key sent in the X-API-Key header.
+type APIKeys struct {
+	store KeyStore
+}
+
+func NewAPIKeys(store KeyStore) *APIKeys {
+	return &APIKeys{
+		store: store,
+	}
+}
+
+// Authenticate looks up the key sent with the request and returns its principal.
+func (a *APIKeys) Authenticate(ctx context.Context, r *http.Request) (Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+	if !strings.HasPrefix(key, keyPrefix) {
+		return Principal{}, ErrInvalidCredentials
+	}
+
+	k, err := a.store.APIKeyByHash(ctx, HashKey(key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Principal{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+	return Principal{
+		ID:     k.ID.String(),
+		Name:   k.Name,
+		Scopes: k.Scopes,
+	}, nil
+}
+
+// GenerateKey returns a new random API key.
+func GenerateKey() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashKey returns the hash under which an API key is stored.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,63 @@
+// Package auth authenticates API clients and describes what they are allowed to do.
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+)
+
+// Scopes granted to clients.
+const (
+	ScopeWalletsRead  = "wallets:read"
+	ScopeWalletsWrite = "wallets:write"
+	ScopeAdmin        = "admin"
+)
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request carries none of its credentials.
+	ErrNoCredentials = errors.New("missing credentials")
+	// ErrInvalidCredentials is returned when the credentials are unknown, revoked or malformed.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is the authenticated client a request is made on behalf of.
+type Principal struct {
+	ID     string
+	Name   string
+	Scopes []string
+	// Owner restricts the principal to the wallets of this owner.
+	// It is empty for service clients, which may access every wallet.
+	Owner string
+}
+
+// HasScope reports whether the principal was granted scope. The admin scope grants every scope.
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// CanAccess reports whether the principal may access a wallet of the given owner.
+func (p Principal) CanAccess(owner *string) bool {
+	return p.Owner == "" || (owner != nil && *owner == p.Owner)
+}
+
+// Authenticator identifies the principal of a request.
+// It returns ErrNoCredentials if the request carries no credentials it understands,
+// so that other authenticators can be tried.
+type Authenticator interface {
+	Authenticate(ctx context.Context, r *http.Request) (Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal carried by ctx, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
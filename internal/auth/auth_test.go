@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHasScope(t *testing.T) {
+	t.Run("TestHasScope_Granted", func(t *testing.T) {
+		p := Principal{Scopes: []string{ScopeWalletsRead}}
+		if !p.HasScope(ScopeWalletsRead) {
+			t.Errorf("Expected: %v, recieved: %v", true, false)
+		}
+		if p.HasScope(ScopeWalletsWrite) {
+			t.Errorf("Expected: %v, recieved: %v", false, true)
+		}
+	})
+
+	t.Run("TestHasScope_Admin", func(t *testing.T) {
+		p := Principal{Scopes: []string{ScopeAdmin}}
+		for _, scope := range []string{ScopeWalletsRead, ScopeWalletsWrite, ScopeAdmin} {
+			if !p.HasScope(scope) {
+				t.Errorf("Expected admin to have %v", scope)
+			}
+		}
+	})
+}
+
+func TestGenerateKey(t *testing.T) {
+	a, err := GenerateKey()
+	if err != nil {
+		t.Error("generate key err: ", err)
+	}
+	b, err := GenerateKey()
+	if err != nil {
+		t.Error("generate key err: ", err)
+	}
+
+	if !strings.HasPrefix(a, keyPrefix) {
+		t.Errorf("Expected prefix: %v, recieved: %v", keyPrefix, a)
+	}
+	if a == b {
+		t.Errorf("Expected distinct keys, recieved: %v twice", a)
+	}
+	if HashKey(a) == HashKey(b) || len(HashKey(a)) != 64 {
+		t.Errorf("Expected distinct 64 character hashes, recieved: %v, %v", HashKey(a), HashKey(b))
+	}
+}
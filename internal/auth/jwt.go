@@ -0,0 +1,380 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PublicKey is a key JWT signatures are verified with.
+// ID is matched against the kid header of tokens; keys without an id are tried for every token.
+type PublicKey struct {
+	ID  string
+	Key crypto.PublicKey
+}
+
+// JWTOption configures the JWT authenticator.
+type JWTOption func(*JWT)
+
+// WithIssuer only accepts tokens whose iss claim equals iss.
+func WithIssuer(iss string) JWTOption {
+	return func(j *JWT) {
+		j.issuer = iss
+	}
+}
+
+// WithAudience only accepts tokens whose aud claim contains aud.
+func WithAudience(aud string) JWTOption {
+	return func(j *JWT) {
+		j.audience = aud
+	}
+}
+
+// WithLeeway tolerates clock skew of d when checking the exp and nbf claims.
+func WithLeeway(d time.Duration) JWTOption {
+	return func(j *JWT) {
+		j.leeway = d
+	}
+}
+
+// JWT authenticates end users by the bearer token sent in the Authorization header.
+// Tokens must be signed with one of the configured public keys (RS256, RS384, RS512,
+// ES256, ES384, ES512 or EdDSA) and carry the sub and exp claims.
+// The subject becomes the owner the principal is restricted to.
+type JWT struct {
+	keys     []PublicKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+func NewJWT(keys []PublicKey, opts ...JWTOption) *JWT {
+	j := &JWT{
+		keys: keys,
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *int64   `json:"exp"`
+	NotBefore *int64   `json:"nbf"`
+	Scope     *string  `json:"scope"`
+}
+
+// audience is the aud claim, which may be a single string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*a = audience{s}
+		return nil
+	}
+	var l []string
+	err := json.Unmarshal(b, &l)
+	*a = l
+	return err
+}
+
+// Authenticate verifies the bearer token sent with the request and returns its principal.
+// Tokens without a scope claim are granted wallets:read and wallets:write. The admin scope is
+// dropped from the scope claim, as token holders act as end users restricted to their own wallets.
+func (j *JWT) Authenticate(ctx context.Context, r *http.Request) (Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return Principal{}, ErrNoCredentials
+	}
+
+	claims, err := j.verify(token, time.Now())
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	scopes := []string{ScopeWalletsRead, ScopeWalletsWrite}
+	if claims.Scope != nil {
+		scopes = slices.DeleteFunc(strings.Fields(*claims.Scope), func(s string) bool {
+			return s == ScopeAdmin
+		})
+	}
+	return Principal{
+		ID:     claims.Subject,
+		Name:   claims.Subject,
+		Scopes: scopes,
+		Owner:  claims.Subject,
+	}, nil
+}
+
+// verify checks the signature and the claims of a compact serialized token.
+func (j *JWT) verify(token string, now time.Time) (jwtClaims, error) {
+	var claims jwtClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return claims, fmt.Errorf("header: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, fmt.Errorf("signature: %w", err)
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, k := range j.keys {
+		if header.Kid != "" && k.ID != "" && k.ID != header.Kid {
+			continue
+		}
+		err = verifySignature(header.Alg, k.Key, signed, sig)
+		if err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return claims, errors.New("signature verification failed")
+	}
+
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return claims, fmt.Errorf("claims: %w", err)
+	}
+	if claims.Subject == "" {
+		return claims, errors.New("missing sub claim")
+	}
+	if claims.ExpiresAt == nil {
+		return claims, errors.New("missing exp claim")
+	}
+	if now.After(time.Unix(*claims.ExpiresAt, 0).Add(j.leeway)) {
+		return claims, errors.New("token expired")
+	}
+	if claims.NotBefore != nil && now.Before(time.Unix(*claims.NotBefore, 0).Add(-j.leeway)) {
+		return claims, errors.New("token not valid yet")
+	}
+	if j.issuer != "" && claims.Issuer != j.issuer {
+		return claims, errors.New("unexpected issuer")
+	}
+	if j.audience != "" && !slices.Contains(claims.Audience, j.audience) {
+		return claims, errors.New("unexpected audience")
+	}
+	return claims, nil
+}
+
+func decodeSegment(s string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// verifySignature checks sig of signed with key using the JWS algorithm alg.
+// The key type must match the algorithm, so that tokens cannot pick a weaker verification.
+func verifySignature(alg string, key crypto.PublicKey, signed, sig []byte) error {
+	switch alg {
+	case "RS256", "RS384", "RS512":
+		k, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type mismatch")
+		}
+		h, digest := hashFor(alg)
+		return rsa.VerifyPKCS1v15(k, h, digest(signed), sig)
+	case "ES256", "ES384", "ES512":
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key type mismatch")
+		}
+		_, digest := hashFor(alg)
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size || curveFor(alg) != k.Curve {
+			return errors.New("invalid signature")
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest(signed), r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case "EdDSA":
+		k, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("key type mismatch")
+		}
+		if !ed25519.Verify(k, signed, sig) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+func hashFor(alg string) (crypto.Hash, func([]byte) []byte) {
+	var h func() hash.Hash
+	var id crypto.Hash
+	switch alg[2:] {
+	case "384":
+		h, id = sha512.New384, crypto.SHA384
+	case "512":
+		h, id = sha512.New, crypto.SHA512
+	default:
+		h, id = sha256.New, crypto.SHA256
+	}
+	return id, func(b []byte) []byte {
+		d := h()
+		d.Write(b)
+		return d.Sum(nil)
+	}
+}
+
+func curveFor(alg string) elliptic.Curve {
+	switch alg {
+	case "ES384":
+		return elliptic.P384()
+	case "ES512":
+		return elliptic.P521()
+	}
+	return elliptic.P256()
+}
+
+// LoadJWKS reads the public keys of a JSON Web Key Set file.
+// RSA, EC (P-256, P-384, P-521) and Ed25519 keys are supported; keys used for encryption are skipped.
+func LoadJWKS(path string) ([]PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err = json.Unmarshal(b, &set)
+	if err != nil {
+		return nil, fmt.Errorf("jwks %s: %w", path, err)
+	}
+
+	var keys []PublicKey
+	for _, k := range set.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		pk, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks %s: key %q: %w", path, k.Kid, err)
+		}
+		keys = append(keys, PublicKey{ID: k.Kid, Key: pk})
+	}
+	return keys, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// LoadPublicKeys reads PEM encoded public keys (PKIX "PUBLIC KEY" blocks) from files.
+// The keys have no id and are tried for every token.
+func LoadPublicKeys(paths []string) ([]PublicKey, error) {
+	var keys []PublicKey
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		block, _ := pem.Decode(b)
+		if block == nil || block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("%s: no PEM public key found", path)
+		}
+		pk, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, PublicKey{Key: pk})
+	}
+	return keys, nil
+}
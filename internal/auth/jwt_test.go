@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// signToken builds a compact serialized token signed by sign.
+func signToken(t *testing.T, header, claims map[string]any, sign func([]byte) []byte) string {
+	h, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal("marshal header err: ", err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal("marshal claims err: ", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func es256(t *testing.T, key *ecdsa.PrivateKey) func([]byte) []byte {
+	return func(b []byte) []byte {
+		digest := sha256.Sum256(b)
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal("sign err: ", err)
+		}
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig
+	}
+}
+
+func bearer(token string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestJWT(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("generate key err: ", err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("generate key err: ", err)
+	}
+
+	j := NewJWT([]PublicKey{{ID: "ec", Key: &ecKey.PublicKey}},
+		WithIssuer("https://id.example.com"),
+		WithAudience("wallet"),
+	)
+	header := map[string]any{"alg": "ES256", "kid": "ec"}
+	claims := func() map[string]any {
+		return map[string]any{
+			"sub": "customer-1842",
+			"iss": "https://id.example.com",
+			"aud": []string{"wallet", "other"},
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+	}
+
+	t.Run("TestJWT_Valid", func(t *testing.T) {
+		token := signToken(t, header, claims(), es256(t, ecKey))
+
+		p, err := j.Authenticate(context.Background(), bearer(token))
+		if err != nil {
+			t.Error("authenticate err: ", err)
+		}
+		correct := Principal{
+			ID:     "customer-1842",
+			Name:   "customer-1842",
+			Scopes: []string{ScopeWalletsRead, ScopeWalletsWrite},
+			Owner:  "customer-1842",
+		}
+		if !reflect.DeepEqual(correct, p) {
+			t.Errorf("Expected: %v, recieved: %v", correct, p)
+		}
+	})
+
+	t.Run("TestJWT_Scope", func(t *testing.T) {
+		c := claims()
+		c["scope"] = "wallets:read"
+		token := signToken(t, header, c, es256(t, ecKey))
+
+		p, err := j.Authenticate(context.Background(), bearer(token))
+		if err != nil {
+			t.Error("authenticate err: ", err)
+		}
+		if p.HasScope(ScopeWalletsWrite) {
+			t.Errorf("Expected: %v, recieved: %v", []string{ScopeWalletsRead}, p.Scopes)
+		}
+	})
+
+	t.Run("TestJWT_AdminScope", func(t *testing.T) {
+		c := claims()
+		c["scope"] = "wallets:read admin"
+		token := signToken(t, header, c, es256(t, ecKey))
+
+		p, err := j.Authenticate(context.Background(), bearer(token))
+		if err != nil {
+			t.Error("authenticate err: ", err)
+		}
+		if p.HasScope(ScopeAdmin) || !p.HasScope(ScopeWalletsRead) {
+			t.Errorf("Expected: %v, recieved: %v", []string{ScopeWalletsRead}, p.Scopes)
+		}
+	})
+
+	t.Run("TestJWT_NoToken", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		_, err := j.Authenticate(context.Background(), r)
+		if !errors.Is(err, ErrNoCredentials) {
+			t.Errorf("Expected: %v, recieved: %v", ErrNoCredentials, err)
+		}
+	})
+
+	invalid := map[string]func() string{
+		"Expired": func() string {
+			c := claims()
+			c["exp"] = time.Now().Add(-time.Minute).Unix()
+			return signToken(t, header, c, es256(t, ecKey))
+		},
+		"NotYetValid": func() string {
+			c := claims()
+			c["nbf"] = time.Now().Add(time.Minute).Unix()
+			return signToken(t, header, c, es256(t, ecKey))
+		},
+		"NoExpiry": func() string {
+			c := claims()
+			delete(c, "exp")
+			return signToken(t, header, c, es256(t, ecKey))
+		},
+		"NoSubject": func() string {
+			c := claims()
+			delete(c, "sub")
+			return signToken(t, header, c, es256(t, ecKey))
+		},
+		"WrongIssuer": func() string {
+			c := claims()
+			c["iss"] = "https://evil.example.com"
+			return signToken(t, header, c, es256(t, ecKey))
+		},
+		"WrongAudience": func() string {
+			c := claims()
+			c["aud"] = "other"
+			return signToken(t, header, c, es256(t, ecKey))
+		},
+		"WrongKey": func() string {
+			return signToken(t, header, claims(), es256(t, otherKey))
+		},
+		"UnknownKid": func() string {
+			return signToken(t, map[string]any{"alg": "ES256", "kid": "other"}, claims(), es256(t, ecKey))
+		},
+		"AlgNone": func() string {
+			return signToken(t, map[string]any{"alg": "none"}, claims(), func([]byte) []byte { return nil })
+		},
+		"Malformed": func() string {
+			return "not.a-token"
+		},
+	}
+	for name, token := range invalid {
+		t.Run("TestJWT_"+name, func(t *testing.T) {
+			_, err := j.Authenticate(context.Background(), bearer(token()))
+			if !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("Expected: %v, recieved: %v", ErrInvalidCredentials, err)
+			}
+		})
+	}
+}
+
+func TestLoadJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal("generate key err: ", err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal("generate key err: ", err)
+	}
+
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": %q},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"}
+	]}`,
+		base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(edPub),
+	)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	err = os.WriteFile(path, []byte(jwks), 0o600)
+	if err != nil {
+		t.Fatal("write jwks err: ", err)
+	}
+
+	keys, err := LoadJWKS(path)
+	if err != nil {
+		t.Fatal("load jwks err: ", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected: %v keys, recieved: %v", 2, len(keys))
+	}
+	j := NewJWT(keys)
+	claims := map[string]any{"sub": "customer-1842", "exp": time.Now().Add(time.Minute).Unix()}
+
+	t.Run("TestLoadJWKS_RS256", func(t *testing.T) {
+		token := signToken(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims, func(b []byte) []byte {
+			digest := sha256.Sum256(b)
+			sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+			if err != nil {
+				t.Fatal("sign err: ", err)
+			}
+			return sig
+		})
+		_, err := j.Authenticate(context.Background(), bearer(token))
+		if err != nil {
+			t.Error("authenticate err: ", err)
+		}
+	})
+
+	t.Run("TestLoadJWKS_EdDSA", func(t *testing.T) {
+		token := signToken(t, map[string]any{"alg": "EdDSA", "kid": "ed"}, claims, func(b []byte) []byte {
+			return ed25519.Sign(edKey, b)
+		})
+		_, err := j.Authenticate(context.Background(), bearer(token))
+		if err != nil {
+			t.Error("authenticate err: ", err)
+		}
+	})
+
+	t.Run("TestLoadJWKS_AlgKeyMismatch", func(t *testing.T) {
+		token := signToken(t, map[string]any{"alg": "EdDSA", "kid": "rsa"}, claims, func(b []byte) []byte {
+			return ed25519.Sign(edKey, b)
+		})
+		_, err := j.Authenticate(context.Background(), bearer(token))
+		if !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Expected: %v, recieved: %v", ErrInvalidCredentials, err)
+		}
+	})
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal("generate key err: ", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatal("marshal key err: ", err)
+	}
+	path := filepath.Join(t.TempDir(), "key.pem")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatal("write key err: ", err)
+	}
+
+	keys, err := LoadPublicKeys([]string{path})
+	if err != nil {
+		t.Fatal("load keys err: ", err)
+	}
+
+	token := signToken(t, map[string]any{"alg": "ES256", "kid": "any"},
+		map[string]any{"sub": "customer-1842", "exp": time.Now().Add(time.Minute).Unix()}, es256(t, ecKey))
+	_, err = NewJWT(keys).Authenticate(context.Background(), bearer(token))
+	if err != nil {
+		t.Error("authenticate err: ", err)
+	}
+}
@@ -4,15 +4,20 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
 	Listen struct {
-		Addr   string
-		BindIP string `env:"BIND_IP"`
-		Port   string `env:"LISTEN_PORT"`
+		Addr     string
+		GRPCAddr string
+		BindIP   string `env:"BIND_IP"`
+		Port     string `env:"LISTEN_PORT"`
+		GRPCPort string `env:"GRPC_PORT" env-default:"9090"`
+		// TrustedProxies lists the addresses or CIDR ranges allowed to set X-Forwarded-For; none by default.
+		TrustedProxies []string `env:"TRUSTED_PROXIES"`
 	}
 	Postgresql struct {
 		DSN      string
@@ -22,6 +27,72 @@ type Config struct {
 		Username string `env:"PSQL_USER"`
 		Password string `env:"PSQL_PASSWORD"`
 	}
+	Auth struct {
+		Enabled bool `env:"AUTH_ENABLED" env-default:"true"`
+		JWT     struct {
+			JWKSFile   string        `env:"AUTH_JWT_JWKS_FILE"`
+			PublicKeys []string      `env:"AUTH_JWT_PUBLIC_KEYS"`
+			Issuer     string        `env:"AUTH_JWT_ISSUER"`
+			Audience   string        `env:"AUTH_JWT_AUDIENCE"`
+			Leeway     time.Duration `env:"AUTH_JWT_LEEWAY" env-default:"30s"`
+		}
+	}
+	Signing struct {
+		Secrets map[string]string `env:"SIGNING_SECRETS"`
+		Window  time.Duration     `env:"SIGNING_WINDOW" env-default:"5m"`
+	}
+	RateLimit struct {
+		Store       string  `env:"RATE_LIMIT_STORE" env-default:"memory"`
+		ClientRate  float64 `env:"RATE_LIMIT_CLIENT_RPS" env-default:"100"`
+		ClientBurst int     `env:"RATE_LIMIT_CLIENT_BURST" env-default:"200"`
+		WalletRate  float64 `env:"RATE_LIMIT_WALLET_RPS" env-default:"10"`
+		WalletBurst int     `env:"RATE_LIMIT_WALLET_BURST" env-default:"20"`
+	}
+	Idempotency struct {
+		Retention     time.Duration `env:"IDEMPOTENCY_RETENTION" env-default:"24h"`
+		PurgeInterval time.Duration `env:"IDEMPOTENCY_PURGE_INTERVAL" env-default:"1h"`
+	}
+	Wallet struct {
+		DefaultCurrency      string `env:"DEFAULT_CURRENCY" env-default:"USD"`
+		FrozenAcceptsCredits bool   `env:"FROZEN_WALLETS_ACCEPT_CREDITS" env-default:"true"`
+		UniqueOwnerCurrency  bool   `env:"UNIQUE_OWNER_CURRENCY" env-default:"false"`
+	}
+	Holds struct {
+		DefaultTTL    time.Duration `env:"HOLD_DEFAULT_TTL" env-default:"15m"`
+		SweepInterval time.Duration `env:"HOLD_SWEEP_INTERVAL" env-default:"1m"`
+	}
+	Outbox struct {
+		Publisher    string        `env:"OUTBOX_PUBLISHER" env-default:"stdout"`
+		File         string        `env:"OUTBOX_FILE" env-default:"events.jsonl"`
+		PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" env-default:"1s"`
+		BatchSize    int           `env:"OUTBOX_BATCH_SIZE" env-default:"100"`
+	}
+	Webhooks struct {
+		Workers      int           `env:"WEBHOOK_WORKERS" env-default:"4"`
+		PollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" env-default:"1s"`
+		Timeout      time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+		MaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+		Backoff      time.Duration `env:"WEBHOOK_BACKOFF" env-default:"10s"`
+		MaxBackoff   time.Duration `env:"WEBHOOK_MAX_BACKOFF" env-default:"1h"`
+	}
+	Stream struct {
+		Heartbeat        time.Duration `env:"SSE_HEARTBEAT" env-default:"15s"`
+		MaxDuration      time.Duration `env:"SSE_MAX_DURATION" env-default:"1h"`
+		MaxStreams       int           `env:"SSE_MAX_STREAMS" env-default:"1000"`
+		MaxClientStreams int           `env:"SSE_MAX_CLIENT_STREAMS" env-default:"5"`
+		Buffer           int           `env:"SSE_BUFFER" env-default:"64"`
+	}
+	Socket struct {
+		Buffer           int           `env:"WS_BUFFER" env-default:"64"`
+		MaxInFlight      int           `env:"WS_MAX_IN_FLIGHT" env-default:"16"`
+		MaxSubscriptions int           `env:"WS_MAX_SUBSCRIPTIONS" env-default:"50"`
+		WriteTimeout     time.Duration `env:"WS_WRITE_TIMEOUT" env-default:"10s"`
+	}
+	Rates struct {
+		File           string        `env:"RATES_FILE"`
+		ReloadInterval time.Duration `env:"RATES_RELOAD_INTERVAL" env-default:"30s"`
+		RoundingMode   string        `env:"RATES_ROUNDING_MODE" env-default:"HALF_EVEN"`
+	}
 }
 
 var instance *Config
@@ -40,6 +111,7 @@ func GetConfig() *Config {
 			log.Fatalln("read app configuration error")
 		}
 		instance.Listen.Addr = instance.Listen.BindIP + ":" + instance.Listen.Port
+		instance.Listen.GRPCAddr = instance.Listen.BindIP + ":" + instance.Listen.GRPCPort
 		instance.Postgresql.DSN = fmt.Sprintf("postgresql://%s:%s@%s:%s/%s",
 			instance.Postgresql.Username, instance.Postgresql.Password, instance.Postgresql.Host,
 			instance.Postgresql.Port, instance.Postgresql.Database)
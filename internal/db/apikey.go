@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateAPIKey stores a new API key.
+func (s *storage) CreateAPIKey(ctx context.Context, k model.APIKey) error {
+	query := `
+		INSERT INTO
+			api_keys (id, name, key_hash, scopes)
+		VALUES
+			(@id, @name, @hash, @scopes)
+	`
+	args := pgx.NamedArgs{
+		"id":     k.ID,
+		"name":   k.Name,
+		"hash":   k.Hash,
+		"scopes": k.Scopes,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
+
+// APIKeyByHash retrieves the API key with the given hash unless it was revoked.
+func (s *storage) APIKeyByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			key_hash,
+			scopes,
+			created_at,
+			revoked_at
+		FROM
+			api_keys
+		WHERE
+			key_hash = @hash
+			AND revoked_at IS NULL
+	`
+	args := pgx.NamedArgs{
+		"hash": hash,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.APIKey])
+}
+
+// APIKeys returns all API keys, including revoked ones, oldest first.
+func (s *storage) APIKeys(ctx context.Context) ([]model.APIKey, error) {
+	query := `
+		SELECT
+			id,
+			name,
+			key_hash,
+			scopes,
+			created_at,
+			revoked_at
+		FROM
+			api_keys
+		ORDER BY
+			created_at
+	`
+	rows, err := s.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.APIKey])
+}
+
+// RevokeAPIKey revokes an API key. It returns pgx.ErrNoRows if there is no such active key.
+func (s *storage) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE
+			api_keys
+		SET
+			revoked_at = now()
+		WHERE
+			id = @id
+			AND revoked_at IS NULL
+	`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	tag, err := s.conn(ctx).Exec(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AuditFilter narrows down the records returned by Storage.AuditLog.
+// Zero values mean the corresponding filter is not applied.
+type AuditFilter struct {
+	UUID      uuid.UUID
+	Action    string
+	Principal string
+	BeforeID  int64
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+// RecordAudit appends a record to the audit log. Called with the context of a transaction,
+// the record is committed or rolled back together with the change it describes.
+func (s *storage) RecordAudit(ctx context.Context, r model.AuditRecord) error {
+	query := `
+		INSERT INTO
+			audit_log (action, wallet_uuid, principal, principal_name, source_ip, request_id, before, after)
+		VALUES
+			(@action, @uuid, @principal, @principalName, @sourceIP, @requestID, @before, @after)
+	`
+	args := pgx.NamedArgs{
+		"action":        r.Action,
+		"uuid":          r.UUID,
+		"principal":     r.Principal,
+		"principalName": r.PrincipalName,
+		"sourceIP":      r.SourceIP,
+		"requestID":     r.RequestID,
+		"before":        r.Before,
+		"after":         r.After,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
+
+// AuditLog returns the audit records matching the filter, newest first.
+func (s *storage) AuditLog(ctx context.Context, filter AuditFilter) ([]model.AuditRecord, error) {
+	query := `
+		SELECT
+			id,
+			action,
+			wallet_uuid,
+			principal,
+			principal_name,
+			source_ip,
+			request_id,
+			before,
+			after,
+			created_at
+		FROM
+			audit_log
+		WHERE
+			(@uuid::UUID IS NULL OR wallet_uuid = @uuid)
+			AND (@action::TEXT IS NULL OR action = @action)
+			AND (@principal::TEXT IS NULL OR principal = @principal)
+			AND (@before::BIGINT IS NULL OR id < @before)
+			AND (@from::TIMESTAMPTZ IS NULL OR created_at >= @from)
+			AND (@to::TIMESTAMPTZ IS NULL OR created_at < @to)
+		ORDER BY
+			id DESC
+		LIMIT
+			@limit
+	`
+	args := pgx.NamedArgs{
+		"uuid":      nil,
+		"action":    nil,
+		"principal": nil,
+		"before":    nil,
+		"from":      nil,
+		"to":        nil,
+		"limit":     filter.Limit,
+	}
+	if filter.UUID != uuid.Nil {
+		args["uuid"] = filter.UUID
+	}
+	if filter.Action != "" {
+		args["action"] = filter.Action
+	}
+	if filter.Principal != "" {
+		args["principal"] = filter.Principal
+	}
+	if filter.BeforeID != 0 {
+		args["before"] = filter.BeforeID
+	}
+	if !filter.From.IsZero() {
+		args["from"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		args["to"] = filter.To
+	}
+
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.AuditRecord])
+}
@@ -0,0 +1,325 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrHoldNotActive is returned when capturing or voiding a hold that was already
+// captured, voided or has expired.
+var ErrHoldNotActive = errors.New("hold is not active")
+
+// holdColumns selects a hold joined with its wallet as model.Hold.
+// Active holds past their expiry are reported as expired even before the sweeper releases them.
+const holdColumns = `
+	h.id,
+	h.wallet_uuid,
+	w.currency,
+	h.amount,
+	h.captured,
+	CASE
+		WHEN h.status = 'ACTIVE' AND h.expires_at <= now() THEN 'EXPIRED'
+		ELSE h.status
+	END AS status,
+	h.operation_id,
+	h.expires_at,
+	h.created_at
+`
+
+// CreateHold reserves amount on the wallet for ttl and emits a hold.created event. The reservation
+// is only made if the available balance covers it, otherwise ErrInsufficientFunds is returned,
+// and if the wallet status allows debits, otherwise ErrWalletFrozen or ErrWalletClosed is returned.
+func (s *storage) CreateHold(ctx context.Context, id uuid.UUID, wallet uuid.UUID, amount money.Amount, ttl time.Duration) (model.Hold, error) {
+	var res model.Hold
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		query := `
+			UPDATE
+				wallets
+			SET
+				held = held + @amount
+			WHERE
+				uuid = @uuid
+				AND status = ANY(@statuses)
+				AND balance - held >= @amount
+			RETURNING
+				uuid
+		`
+		statuses := s.statuses(true)
+		args := pgx.NamedArgs{
+			"id":       id,
+			"uuid":     wallet,
+			"amount":   amount,
+			"ttl":      ttl.Seconds(),
+			"statuses": statuses,
+		}
+		err := s.conn(ctx).QueryRow(ctx, query, args).Scan(&wallet)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return s.refusal(ctx, wallet, statuses)
+		}
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO
+				holds (id, wallet_uuid, amount, expires_at)
+			VALUES
+				(@id, @uuid, @amount, now() + make_interval(secs => @ttl))
+		`
+		_, err = s.conn(ctx).Exec(ctx, query, args)
+		if err != nil {
+			return err
+		}
+
+		res, err = s.Hold(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.emitHold(ctx, model.EventHoldCreated, res)
+	})
+	return res, err
+}
+
+// Hold retrieves a hold by its id.
+func (s *storage) Hold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	query := `
+		SELECT` + holdColumns + `
+		FROM
+			holds h
+			JOIN wallets w ON w.uuid = h.wallet_uuid
+		WHERE
+			h.id = @id
+	`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.Hold])
+}
+
+// CaptureHold takes amount, which must not exceed the held amount, from the wallet
+// and releases the rest of the hold. The withdrawal is recorded in the ledger as a CAPTURE.
+func (s *storage) CaptureHold(ctx context.Context, id uuid.UUID, amount money.Amount) (model.Hold, error) {
+	var res model.Hold
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = s.release(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		op := uuid.New()
+		err = s.record(ctx, model.Operation{
+			ID:     op,
+			Type:   "CAPTURE",
+			UUID:   res.UUID,
+			Amount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = s.change(ctx, entry{
+			op:     op,
+			wallet: res.UUID,
+			opType: "CAPTURE",
+			delta:  -amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		res.Status = model.HoldCaptured
+		res.Captured = amount
+		res.OperationID = &op
+		return s.closeHold(ctx, res)
+	})
+	return res, err
+}
+
+// VoidHold releases the whole hold without taking anything from the wallet and emits a hold.voided event.
+func (s *storage) VoidHold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	var res model.Hold
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = s.release(ctx, id)
+		if err != nil {
+			return err
+		}
+		res.Status = model.HoldVoided
+		err = s.closeHold(ctx, res)
+		if err != nil {
+			return err
+		}
+		return s.emitHold(ctx, model.EventHoldVoided, res)
+	})
+	return res, err
+}
+
+// release locks an active hold and returns its amount to the wallet's available balance.
+// It must run inside a transaction started by inTx, which is expected to close the hold.
+func (s *storage) release(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	query := `
+		SELECT` + holdColumns + `
+		FROM
+			holds h
+			JOIN wallets w ON w.uuid = h.wallet_uuid
+		WHERE
+			h.id = @id
+		FOR UPDATE OF h
+	`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	res, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.Hold])
+	if err != nil {
+		return res, err
+	}
+	if res.Status != model.HoldActive {
+		return res, ErrHoldNotActive
+	}
+
+	query = `
+		UPDATE
+			wallets
+		SET
+			held = held - @amount
+		WHERE
+			uuid = @uuid
+	`
+	args["uuid"] = res.UUID
+	args["amount"] = res.Amount
+	_, err = s.conn(ctx).Exec(ctx, query, args)
+	return res, err
+}
+
+// closeHold stores the final status of a hold released by release.
+func (s *storage) closeHold(ctx context.Context, h model.Hold) error {
+	query := `
+		UPDATE
+			holds
+		SET
+			status = @status,
+			captured = @captured,
+			operation_id = @operation,
+			updated_at = now()
+		WHERE
+			id = @id
+	`
+	args := pgx.NamedArgs{
+		"id":        h.ID,
+		"status":    h.Status,
+		"captured":  h.Captured,
+		"operation": h.OperationID,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
+
+// emitHold emits an event of eventType for a hold reserved or released on its wallet, carrying the
+// balances of the wallet after the change. It must run inside the transaction of the change.
+func (s *storage) emitHold(ctx context.Context, eventType string, h model.Hold) error {
+	query := `
+		SELECT
+			balance,
+			balance - held,
+			currency
+		FROM
+			wallets
+		WHERE
+			uuid = @uuid
+	`
+	args := pgx.NamedArgs{
+		"uuid": h.UUID,
+	}
+	event := model.HoldChange{
+		HoldID: h.ID,
+		UUID:   h.UUID,
+		Amount: h.Amount,
+	}
+	err := s.conn(ctx).QueryRow(ctx, query, args).Scan(&event.Balance, &event.Available, &event.Currency)
+	if err != nil {
+		return err
+	}
+	return s.emit(ctx, eventType, h.UUID, event)
+}
+
+// ExpireHolds marks active holds past their expiry as expired, releases their amounts,
+// emits a hold.expired event for each of them and returns how many holds expired.
+// Wallets are released in the order of their ids, so that concurrent sweeps do not deadlock.
+func (s *storage) ExpireHolds(ctx context.Context) (int64, error) {
+	var n int64
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		query := `
+			UPDATE
+				holds
+			SET
+				status = 'EXPIRED',
+				updated_at = now()
+			WHERE
+				status = 'ACTIVE'
+				AND expires_at <= now()
+			RETURNING
+				id,
+				wallet_uuid,
+				amount
+		`
+		rows, err := s.conn(ctx).Query(ctx, query)
+		if err != nil {
+			return err
+		}
+		expired, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Hold, error) {
+			var h model.Hold
+			err := row.Scan(&h.ID, &h.UUID, &h.Amount)
+			return h, err
+		})
+		if err != nil {
+			return err
+		}
+		slices.SortFunc(expired, func(a, b model.Hold) int {
+			return bytes.Compare(a.UUID[:], b.UUID[:])
+		})
+
+		query = `
+			UPDATE
+				wallets
+			SET
+				held = held - @amount
+			WHERE
+				uuid = @uuid
+		`
+		for _, h := range expired {
+			args := pgx.NamedArgs{
+				"uuid":   h.UUID,
+				"amount": h.Amount,
+			}
+			_, err = s.conn(ctx).Exec(ctx, query, args)
+			if err != nil {
+				return err
+			}
+			err = s.emitHold(ctx, model.EventHoldExpired, h)
+			if err != nil {
+				return err
+			}
+		}
+		n = int64(len(expired))
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ClaimIdempotencyKey reserves the key for the current request and reports whether it succeeded.
+// Keys older than retention are considered expired and are claimed again.
+// A concurrent claim of the same key waits until the transaction holding it finishes.
+func (s *storage) ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string, retention time.Duration) (bool, error) {
+	query := `
+		INSERT INTO
+			idempotency_keys (key, fingerprint)
+		VALUES
+			(@key, @fingerprint)
+		ON CONFLICT (key) DO UPDATE
+		SET
+			fingerprint = EXCLUDED.fingerprint,
+			status = NULL,
+			body = NULL,
+			created_at = now()
+		WHERE
+			idempotency_keys.created_at < now() - make_interval(secs => @retention)
+		RETURNING
+			key
+	`
+	args := pgx.NamedArgs{
+		"key":         key,
+		"fingerprint": fingerprint,
+		"retention":   retention.Seconds(),
+	}
+	err := s.conn(ctx).QueryRow(ctx, query, args).Scan(&key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PurgeIdempotencyKeys deletes the idempotency keys older than retention and returns how many were deleted.
+func (s *storage) PurgeIdempotencyKeys(ctx context.Context, retention time.Duration) (int64, error) {
+	query := `
+		DELETE FROM
+			idempotency_keys
+		WHERE
+			created_at < now() - make_interval(secs => @retention)
+	`
+	args := pgx.NamedArgs{
+		"retention": retention.Seconds(),
+	}
+	tag, err := s.conn(ctx).Exec(ctx, query, args)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// IdempotencyRecord retrieves the stored response of an idempotency key.
+func (s *storage) IdempotencyRecord(ctx context.Context, key string) (model.IdempotencyRecord, error) {
+	query := `
+		SELECT
+			key,
+			fingerprint,
+			status,
+			body,
+			created_at
+		FROM
+			idempotency_keys
+		WHERE
+			key = @key
+	`
+	args := pgx.NamedArgs{
+		"key": key,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.IdempotencyRecord{}, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.IdempotencyRecord])
+}
+
+// SaveIdempotencyResponse stores the response sent for a claimed idempotency key.
+func (s *storage) SaveIdempotencyResponse(ctx context.Context, key string, status int, body []byte) error {
+	query := `
+		UPDATE
+			idempotency_keys
+		SET
+			status = @status,
+			body = @body
+		WHERE
+			key = @key
+	`
+	args := pgx.NamedArgs{
+		"key":    key,
+		"status": status,
+		"body":   body,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
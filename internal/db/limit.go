@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// foreignKeyViolation is the Postgres error code reported when a referenced row does not exist.
+const foreignKeyViolation = "23503"
+
+// LimitError is returned when an operation would exceed a spending limit of a wallet.
+// Limit is one of the model.Limit constants.
+type LimitError struct {
+	Wallet uuid.UUID
+	Limit  string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("wallet %s limit %s exceeded", e.Wallet, e.Limit)
+}
+
+// limitedDebits are the ledger entry types counted towards debit limits.
+// Reversals are exempt so that refunds are never blocked by limits.
+var limitedDebits = []string{"WITHDRAW", "TRANSFER", "CAPTURE"}
+
+// Limits retrieves the spending limits of a wallet. Wallets without limits get nil ones.
+func (s *storage) Limits(ctx context.Context, wallet uuid.UUID) (model.WalletLimits, error) {
+	query := `
+		SELECT
+			w.uuid,
+			w.currency,
+			l.max_withdrawal,
+			l.daily_debit,
+			l.monthly_debit,
+			l.max_balance
+		FROM
+			wallets w
+			LEFT JOIN wallet_limits l ON l.wallet_uuid = w.uuid
+		WHERE
+			w.uuid = @uuid
+	`
+	args := pgx.NamedArgs{
+		"uuid": wallet,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.WalletLimits{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.WalletLimits])
+}
+
+// SetLimits replaces the spending limits of a wallet. It returns pgx.ErrNoRows if the wallet does not exist.
+func (s *storage) SetLimits(ctx context.Context, l model.WalletLimits) (model.WalletLimits, error) {
+	query := `
+		INSERT INTO
+			wallet_limits (wallet_uuid, max_withdrawal, daily_debit, monthly_debit, max_balance)
+		VALUES
+			(@uuid, @maxWithdrawal, @dailyDebit, @monthlyDebit, @maxBalance)
+		ON CONFLICT (wallet_uuid) DO UPDATE
+		SET
+			max_withdrawal = EXCLUDED.max_withdrawal,
+			daily_debit = EXCLUDED.daily_debit,
+			monthly_debit = EXCLUDED.monthly_debit,
+			max_balance = EXCLUDED.max_balance,
+			updated_at = now()
+	`
+	args := pgx.NamedArgs{
+		"uuid":          l.UUID,
+		"maxWithdrawal": l.MaxWithdrawal,
+		"dailyDebit":    l.DailyDebit,
+		"monthlyDebit":  l.MonthlyDebit,
+		"maxBalance":    l.MaxBalance,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return model.WalletLimits{}, pgx.ErrNoRows
+	}
+	if err != nil {
+		return model.WalletLimits{}, err
+	}
+	return s.Limits(ctx, l.UUID)
+}
+
+// checkLimits fails with a LimitError if the entry just written by change exceeds a limit of its wallet.
+// Debits are checked against the single debit, rolling 24 hours and calendar month limits, with the
+// entry already counted in the totals, and credits against the maximum balance. It runs in the
+// transaction of the change, which holds the wallet row lock, so concurrent operations on the wallet
+// are checked one after another.
+func (s *storage) checkLimits(ctx context.Context, e entry, balance money.Amount) error {
+	query := `
+		SELECT
+			l.max_withdrawal,
+			l.daily_debit,
+			l.monthly_debit,
+			l.max_balance,
+			COALESCE(SUM(-t.amount) FILTER (WHERE t.created_at > now() - INTERVAL '24 hours'), 0),
+			COALESCE(SUM(-t.amount) FILTER (WHERE t.created_at >= date_trunc('month', now(), 'UTC')), 0)
+		FROM
+			wallet_limits l
+			LEFT JOIN wallet_transactions t ON t.wallet_uuid = l.wallet_uuid
+				AND @debit
+				AND t.amount < 0
+				AND t.type = ANY(@types)
+				AND t.created_at >= LEAST(now() - INTERVAL '24 hours', date_trunc('month', now(), 'UTC'))
+		WHERE
+			l.wallet_uuid = @uuid
+		GROUP BY
+			l.wallet_uuid
+	`
+	args := pgx.NamedArgs{
+		"uuid":  e.wallet,
+		"debit": e.delta < 0,
+		"types": limitedDebits,
+	}
+	var l model.WalletLimits
+	var daily, monthly money.Amount
+	err := s.conn(ctx).QueryRow(ctx, query, args).Scan(&l.MaxWithdrawal, &l.DailyDebit, &l.MonthlyDebit, &l.MaxBalance, &daily, &monthly)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	exceeded := func(limit *money.Amount, value money.Amount) bool {
+		return limit != nil && value > *limit
+	}
+	limit := ""
+	switch {
+	case e.delta > 0 && exceeded(l.MaxBalance, balance):
+		limit = model.LimitMaxBalance
+	case e.delta < 0 && exceeded(l.MaxWithdrawal, -e.delta):
+		limit = model.LimitMaxWithdrawal
+	case e.delta < 0 && exceeded(l.DailyDebit, daily):
+		limit = model.LimitDailyDebit
+	case e.delta < 0 && exceeded(l.MonthlyDebit, monthly):
+		limit = model.LimitMonthlyDebit
+	}
+	if limit != "" {
+		return &LimitError{Wallet: e.wallet, Limit: limit}
+	}
+	return nil
+}
@@ -5,9 +5,12 @@
 package mocks
 
 import (
+	db "cmd/app/main.go/internal/db"
 	model "cmd/app/main.go/internal/model"
+	money "cmd/app/main.go/pkg/money"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,6 +39,51 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// APIKeyByHash mocks base method.
+func (m *MockStorage) APIKeyByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// APIKeyByHash indicates an expected call of APIKeyByHash.
+func (mr *MockStorageMockRecorder) APIKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKeyByHash", reflect.TypeOf((*MockStorage)(nil).APIKeyByHash), ctx, hash)
+}
+
+// APIKeys mocks base method.
+func (m *MockStorage) APIKeys(ctx context.Context) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKeys", ctx)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// APIKeys indicates an expected call of APIKeys.
+func (mr *MockStorageMockRecorder) APIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKeys", reflect.TypeOf((*MockStorage)(nil).APIKeys), ctx)
+}
+
+// AuditLog mocks base method.
+func (m *MockStorage) AuditLog(ctx context.Context, filter db.AuditFilter) ([]model.AuditRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLog", ctx, filter)
+	ret0, _ := ret[0].([]model.AuditRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditLog indicates an expected call of AuditLog.
+func (mr *MockStorageMockRecorder) AuditLog(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLog", reflect.TypeOf((*MockStorage)(nil).AuditLog), ctx, filter)
+}
+
 // Balance mocks base method.
 func (m *MockStorage) Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, error) {
 	m.ctrl.T.Helper()
@@ -51,22 +99,126 @@ func (mr *MockStorageMockRecorder) Balance(ctx, uuid interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balance", reflect.TypeOf((*MockStorage)(nil).Balance), ctx, uuid)
 }
 
+// CaptureHold mocks base method.
+func (m *MockStorage) CaptureHold(ctx context.Context, id uuid.UUID, amount money.Amount) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, id, amount)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockStorageMockRecorder) CaptureHold(ctx, id, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockStorage)(nil).CaptureHold), ctx, id, amount)
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockStorage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, limit, lease)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockStorageMockRecorder) ClaimDeliveries(ctx, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockStorage)(nil).ClaimDeliveries), ctx, limit, lease)
+}
+
+// ClaimIdempotencyKey mocks base method.
+func (m *MockStorage) ClaimIdempotencyKey(ctx context.Context, key, fingerprint string, retention time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimIdempotencyKey", ctx, key, fingerprint, retention)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimIdempotencyKey indicates an expected call of ClaimIdempotencyKey.
+func (mr *MockStorageMockRecorder) ClaimIdempotencyKey(ctx, key, fingerprint, retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimIdempotencyKey", reflect.TypeOf((*MockStorage)(nil).ClaimIdempotencyKey), ctx, key, fingerprint, retention)
+}
+
 // Create mocks base method.
-func (m *MockStorage) Create(ctx context.Context, uuid uuid.UUID) error {
+func (m *MockStorage) Create(ctx context.Context, w model.Wallet, uniqueOwnerCurrency bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, uuid)
+	ret := m.ctrl.Call(m, "Create", ctx, w, uniqueOwnerCurrency)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockStorageMockRecorder) Create(ctx, uuid interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) Create(ctx, w, uniqueOwnerCurrency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorage)(nil).Create), ctx, w, uniqueOwnerCurrency)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockStorage) CreateAPIKey(ctx context.Context, k model.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, k)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockStorageMockRecorder) CreateAPIKey(ctx, k interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockStorage)(nil).CreateAPIKey), ctx, k)
+}
+
+// CreateHold mocks base method.
+func (m *MockStorage) CreateHold(ctx context.Context, id, wallet uuid.UUID, amount money.Amount, ttl time.Duration) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, id, wallet, amount, ttl)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockStorageMockRecorder) CreateHold(ctx, id, wallet, amount, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockStorage)(nil).CreateHold), ctx, id, wallet, amount, ttl)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStorage) CreateWebhook(ctx context.Context, w model.Webhook) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, w)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStorageMockRecorder) CreateWebhook(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStorage)(nil).CreateWebhook), ctx, w)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStorage) DeleteWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStorageMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStorage)(nil).Create), ctx, uuid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStorage)(nil).DeleteWebhook), ctx, id)
 }
 
 // Deposit mocks base method.
-func (m *MockStorage) Deposit(ctx context.Context, uuid uuid.UUID, amount float64) (model.Wallet, error) {
+func (m *MockStorage) Deposit(ctx context.Context, uuid uuid.UUID, amount money.Amount) (model.Wallet, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Deposit", ctx, uuid, amount)
 	ret0, _ := ret[0].(model.Wallet)
@@ -80,8 +232,406 @@ func (mr *MockStorageMockRecorder) Deposit(ctx, uuid, amount interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockStorage)(nil).Deposit), ctx, uuid, amount)
 }
 
+// EnqueueDeliveries mocks base method.
+func (m *MockStorage) EnqueueDeliveries(ctx context.Context, e model.Event) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockStorageMockRecorder) EnqueueDeliveries(ctx, e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockStorage)(nil).EnqueueDeliveries), ctx, e)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockStorage) ExpireHolds(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockStorageMockRecorder) ExpireHolds(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockStorage)(nil).ExpireHolds), ctx)
+}
+
+// Hold mocks base method.
+func (m *MockStorage) Hold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold", ctx, id)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockStorageMockRecorder) Hold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockStorage)(nil).Hold), ctx, id)
+}
+
+// IdempotencyRecord mocks base method.
+func (m *MockStorage) IdempotencyRecord(ctx context.Context, key string) (model.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IdempotencyRecord", ctx, key)
+	ret0, _ := ret[0].(model.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IdempotencyRecord indicates an expected call of IdempotencyRecord.
+func (mr *MockStorageMockRecorder) IdempotencyRecord(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IdempotencyRecord", reflect.TypeOf((*MockStorage)(nil).IdempotencyRecord), ctx, key)
+}
+
+// Limits mocks base method.
+func (m *MockStorage) Limits(ctx context.Context, wallet uuid.UUID) (model.WalletLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limits", ctx, wallet)
+	ret0, _ := ret[0].(model.WalletLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Limits indicates an expected call of Limits.
+func (mr *MockStorageMockRecorder) Limits(ctx, wallet interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limits", reflect.TypeOf((*MockStorage)(nil).Limits), ctx, wallet)
+}
+
+// ListenEvents mocks base method.
+func (m *MockStorage) ListenEvents(ctx context.Context, fn func(model.Event)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenEvents", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenEvents indicates an expected call of ListenEvents.
+func (mr *MockStorageMockRecorder) ListenEvents(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenEvents", reflect.TypeOf((*MockStorage)(nil).ListenEvents), ctx, fn)
+}
+
+// MarkPublished mocks base method.
+func (m *MockStorage) MarkPublished(ctx context.Context, seqs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, seqs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockStorageMockRecorder) MarkPublished(ctx, seqs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockStorage)(nil).MarkPublished), ctx, seqs)
+}
+
+// Operation mocks base method.
+func (m *MockStorage) Operation(ctx context.Context, id uuid.UUID) (model.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Operation", ctx, id)
+	ret0, _ := ret[0].(model.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Operation indicates an expected call of Operation.
+func (mr *MockStorageMockRecorder) Operation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Operation", reflect.TypeOf((*MockStorage)(nil).Operation), ctx, id)
+}
+
+// OwnerWallets mocks base method.
+func (m *MockStorage) OwnerWallets(ctx context.Context, ownerID string) ([]model.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnerWallets", ctx, ownerID)
+	ret0, _ := ret[0].([]model.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OwnerWallets indicates an expected call of OwnerWallets.
+func (mr *MockStorageMockRecorder) OwnerWallets(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnerWallets", reflect.TypeOf((*MockStorage)(nil).OwnerWallets), ctx, ownerID)
+}
+
+// PendingEvents mocks base method.
+func (m *MockStorage) PendingEvents(ctx context.Context, limit int) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingEvents", ctx, limit)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingEvents indicates an expected call of PendingEvents.
+func (mr *MockStorageMockRecorder) PendingEvents(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingEvents", reflect.TypeOf((*MockStorage)(nil).PendingEvents), ctx, limit)
+}
+
+// PurgeIdempotencyKeys mocks base method.
+func (m *MockStorage) PurgeIdempotencyKeys(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeIdempotencyKeys", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeIdempotencyKeys indicates an expected call of PurgeIdempotencyKeys.
+func (mr *MockStorageMockRecorder) PurgeIdempotencyKeys(ctx, retention interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeIdempotencyKeys", reflect.TypeOf((*MockStorage)(nil).PurgeIdempotencyKeys), ctx, retention)
+}
+
+// RecordAudit mocks base method.
+func (m *MockStorage) RecordAudit(ctx context.Context, r model.AuditRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAudit", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAudit indicates an expected call of RecordAudit.
+func (mr *MockStorageMockRecorder) RecordAudit(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAudit", reflect.TypeOf((*MockStorage)(nil).RecordAudit), ctx, r)
+}
+
+// Redeliver mocks base method.
+func (m *MockStorage) Redeliver(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, id)
+	ret0, _ := ret[0].(model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockStorageMockRecorder) Redeliver(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockStorage)(nil).Redeliver), ctx, id)
+}
+
+// Reverse mocks base method.
+func (m *MockStorage) Reverse(ctx context.Context, r db.Reversal) (model.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, r)
+	ret0, _ := ret[0].(model.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockStorageMockRecorder) Reverse(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockStorage)(nil).Reverse), ctx, r)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockStorage) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockStorageMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockStorage)(nil).RevokeAPIKey), ctx, id)
+}
+
+// SaveDelivery mocks base method.
+func (m *MockStorage) SaveDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDelivery", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDelivery indicates an expected call of SaveDelivery.
+func (mr *MockStorageMockRecorder) SaveDelivery(ctx, d interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDelivery", reflect.TypeOf((*MockStorage)(nil).SaveDelivery), ctx, d)
+}
+
+// SaveIdempotencyResponse mocks base method.
+func (m *MockStorage) SaveIdempotencyResponse(ctx context.Context, key string, status int, body []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyResponse", ctx, key, status, body)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyResponse indicates an expected call of SaveIdempotencyResponse.
+func (mr *MockStorageMockRecorder) SaveIdempotencyResponse(ctx, key, status, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyResponse", reflect.TypeOf((*MockStorage)(nil).SaveIdempotencyResponse), ctx, key, status, body)
+}
+
+// SetLimits mocks base method.
+func (m *MockStorage) SetLimits(ctx context.Context, l model.WalletLimits) (model.WalletLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimits", ctx, l)
+	ret0, _ := ret[0].(model.WalletLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimits indicates an expected call of SetLimits.
+func (mr *MockStorageMockRecorder) SetLimits(ctx, l interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimits", reflect.TypeOf((*MockStorage)(nil).SetLimits), ctx, l)
+}
+
+// SetStatus mocks base method.
+func (m *MockStorage) SetStatus(ctx context.Context, uuid uuid.UUID, from []string, to, reason string) (model.Wallet, model.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, uuid, from, to, reason)
+	ret0, _ := ret[0].(model.Wallet)
+	ret1, _ := ret[1].(model.Wallet)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockStorageMockRecorder) SetStatus(ctx, uuid, from, to, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockStorage)(nil).SetStatus), ctx, uuid, from, to, reason)
+}
+
+// TakeToken mocks base method.
+func (m *MockStorage) TakeToken(ctx context.Context, key string, interval time.Duration, burst int) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeToken", ctx, key, interval, burst)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeToken indicates an expected call of TakeToken.
+func (mr *MockStorageMockRecorder) TakeToken(ctx, key, interval, burst interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeToken", reflect.TypeOf((*MockStorage)(nil).TakeToken), ctx, key, interval, burst)
+}
+
+// Transactions mocks base method.
+func (m *MockStorage) Transactions(ctx context.Context, filter db.TransactionFilter) ([]model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transactions", ctx, filter)
+	ret0, _ := ret[0].([]model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transactions indicates an expected call of Transactions.
+func (mr *MockStorageMockRecorder) Transactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transactions", reflect.TypeOf((*MockStorage)(nil).Transactions), ctx, filter)
+}
+
+// Transfer mocks base method.
+func (m *MockStorage) Transfer(ctx context.Context, t db.Transfer) (model.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, t)
+	ret0, _ := ret[0].(model.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockStorageMockRecorder) Transfer(ctx, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockStorage)(nil).Transfer), ctx, t)
+}
+
+// VoidHold mocks base method.
+func (m *MockStorage) VoidHold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, id)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockStorageMockRecorder) VoidHold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockStorage)(nil).VoidHold), ctx, id)
+}
+
+// WalletEvents mocks base method.
+func (m *MockStorage) WalletEvents(ctx context.Context, wallet uuid.UUID, after int64, overlap, limit int) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletEvents", ctx, wallet, after, overlap, limit)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalletEvents indicates an expected call of WalletEvents.
+func (mr *MockStorageMockRecorder) WalletEvents(ctx, wallet, after, overlap, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletEvents", reflect.TypeOf((*MockStorage)(nil).WalletEvents), ctx, wallet, after, overlap, limit)
+}
+
+// WebhookDeliveries mocks base method.
+func (m *MockStorage) WebhookDeliveries(ctx context.Context, filter db.DeliveryFilter) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDeliveries", ctx, filter)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookDeliveries indicates an expected call of WebhookDeliveries.
+func (mr *MockStorageMockRecorder) WebhookDeliveries(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDeliveries", reflect.TypeOf((*MockStorage)(nil).WebhookDeliveries), ctx, filter)
+}
+
+// Webhooks mocks base method.
+func (m *MockStorage) Webhooks(ctx context.Context) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Webhooks", ctx)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Webhooks indicates an expected call of Webhooks.
+func (mr *MockStorageMockRecorder) Webhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Webhooks", reflect.TypeOf((*MockStorage)(nil).Webhooks), ctx)
+}
+
+// WithTx mocks base method.
+func (m *MockStorage) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockStorageMockRecorder) WithTx(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockStorage)(nil).WithTx), ctx, fn)
+}
+
 // Withdraw mocks base method.
-func (m *MockStorage) Withdraw(ctx context.Context, uuid uuid.UUID, amount float64) (model.Wallet, error) {
+func (m *MockStorage) Withdraw(ctx context.Context, uuid uuid.UUID, amount money.Amount) (model.Wallet, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Withdraw", ctx, uuid, amount)
 	ret0, _ := ret[0].(model.Wallet)
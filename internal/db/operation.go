@@ -0,0 +1,242 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrAlreadyReversed is returned when reversing an operation that was already fully reversed.
+	ErrAlreadyReversed = errors.New("operation already reversed")
+	// ErrReversalExceeds is returned when a reversal would exceed the not yet reversed amount of the operation.
+	ErrReversalExceeds = errors.New("reversal exceeds the operation amount")
+)
+
+// Reversal describes a compensating operation of the operation Of.
+// Amount is returned in the currency of the operation, and for transfers
+// CounterAmount is taken back from the credited wallet.
+type Reversal struct {
+	ID            uuid.UUID
+	Of            uuid.UUID
+	Amount        money.Amount
+	CounterAmount money.Amount
+}
+
+// operationColumns selects an operation joined with its wallets as model.Operation.
+const operationColumns = `
+	o.id,
+	o.type,
+	o.wallet_uuid,
+	w.currency,
+	o.amount,
+	o.counterparty_uuid,
+	c.currency AS counter_currency,
+	o.counter_amount,
+	o.reversed,
+	o.counter_reversed,
+	o.reversal_of,
+	o.created_at
+`
+
+// Operation retrieves an operation by its id.
+func (s *storage) Operation(ctx context.Context, id uuid.UUID) (model.Operation, error) {
+	return s.operation(ctx, id, "")
+}
+
+// operation selects an operation by its id, appending lock to the query.
+func (s *storage) operation(ctx context.Context, id uuid.UUID, lock string) (model.Operation, error) {
+	query := `
+		SELECT` + operationColumns + `
+		FROM
+			operations o
+			JOIN wallets w ON w.uuid = o.wallet_uuid
+			LEFT JOIN wallets c ON c.uuid = o.counterparty_uuid
+		WHERE
+			o.id = @id
+	` + lock
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.Operation{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.Operation])
+}
+
+// Reverse records a reversal of an operation and applies its compensating ledger entries:
+// a deposit is taken back from the wallet, a withdrawal or capture is returned to it and
+// a transfer is moved back from the credited wallet to the debited one.
+// The original operation is locked while the reversal is checked against its not yet reversed amount.
+func (s *storage) Reverse(ctx context.Context, r Reversal) (model.Operation, error) {
+	var res model.Operation
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		orig, err := s.operation(ctx, r.Of, "FOR UPDATE OF o")
+		if err != nil {
+			return err
+		}
+		if orig.Reversed == orig.Amount {
+			return ErrAlreadyReversed
+		}
+		if orig.Reversed+r.Amount > orig.Amount {
+			return ErrReversalExceeds
+		}
+		if orig.CounterAmount != nil && orig.CounterReversed+r.CounterAmount > *orig.CounterAmount {
+			return ErrReversalExceeds
+		}
+
+		rev := model.Operation{
+			ID:           r.ID,
+			Type:         "REVERSAL",
+			UUID:         orig.UUID,
+			Amount:       r.Amount,
+			Counterparty: orig.Counterparty,
+			ReversalOf:   &orig.ID,
+		}
+		if orig.CounterAmount != nil {
+			rev.CounterAmount = &r.CounterAmount
+		}
+		err = s.record(ctx, rev)
+		if err != nil {
+			return err
+		}
+
+		switch orig.Type {
+		case "DEPOSIT":
+			_, err = s.change(ctx, entry{op: rev.ID, wallet: orig.UUID, opType: rev.Type, delta: -r.Amount})
+
+		case "WITHDRAW", "CAPTURE":
+			_, err = s.change(ctx, entry{op: rev.ID, wallet: orig.UUID, opType: rev.Type, delta: r.Amount})
+
+		case "TRANSFER":
+			err = s.reverseTransfer(ctx, orig, rev)
+		}
+		if err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE
+				operations
+			SET
+				reversed = reversed + @amount,
+				counter_reversed = counter_reversed + @counterAmount
+			WHERE
+				id = @id
+		`
+		args := pgx.NamedArgs{
+			"id":            orig.ID,
+			"amount":        r.Amount,
+			"counterAmount": r.CounterAmount,
+		}
+		_, err = s.conn(ctx).Exec(ctx, query, args)
+		if err != nil {
+			return err
+		}
+
+		res, err = s.Operation(ctx, rev.ID)
+		return err
+	})
+	return res, err
+}
+
+// reverseTransfer moves the reversed amounts of a transfer back from the credited wallet
+// to the debited one. It must run inside a transaction started by inTx.
+func (s *storage) reverseTransfer(ctx context.Context, orig model.Operation, rev model.Operation) error {
+	currencies, err := s.lockWallets(ctx, orig.UUID, *orig.Counterparty)
+	if err != nil {
+		return err
+	}
+
+	debit := entry{
+		op:           rev.ID,
+		wallet:       *orig.Counterparty,
+		opType:       rev.Type,
+		delta:        -*rev.CounterAmount,
+		counterparty: &orig.UUID,
+	}
+	credit := entry{
+		op:           rev.ID,
+		wallet:       orig.UUID,
+		opType:       rev.Type,
+		delta:        rev.Amount,
+		counterparty: orig.Counterparty,
+	}
+	if currencies[orig.UUID] != currencies[*orig.Counterparty] {
+		debit.counterAmount, debit.counterCurrency = &rev.Amount, currencies[orig.UUID]
+		credit.counterAmount, credit.counterCurrency = rev.CounterAmount, currencies[*orig.Counterparty]
+	}
+
+	_, err = s.change(ctx, debit)
+	if err != nil {
+		return err
+	}
+	_, err = s.change(ctx, credit)
+	return err
+}
+
+// record inserts the operation row its ledger entries refer to.
+// It must run inside a transaction started by inTx.
+func (s *storage) record(ctx context.Context, op model.Operation) error {
+	query := `
+		INSERT INTO
+			operations (id, type, wallet_uuid, counterparty_uuid, amount, counter_amount, reversal_of)
+		VALUES
+			(@id, @type, @uuid, @counterparty, @amount, @counterAmount, @reversalOf)
+	`
+	args := pgx.NamedArgs{
+		"id":            op.ID,
+		"type":          op.Type,
+		"uuid":          op.UUID,
+		"counterparty":  op.Counterparty,
+		"amount":        op.Amount,
+		"counterAmount": op.CounterAmount,
+		"reversalOf":    op.ReversalOf,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
+
+// lockWallets locks the wallet rows in UUID order, so that concurrent operations on the same
+// wallets cannot deadlock, and returns their currencies. It fails with pgx.ErrNoRows
+// if any of the wallets does not exist. It must run inside a transaction started by inTx.
+func (s *storage) lockWallets(ctx context.Context, uuids ...uuid.UUID) (map[uuid.UUID]string, error) {
+	query := `
+		SELECT
+			uuid,
+			currency
+		FROM
+			wallets
+		WHERE
+			uuid = ANY(@uuids)
+		ORDER BY
+			uuid
+		FOR UPDATE
+	`
+	args := pgx.NamedArgs{
+		"uuids": uuids,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[model.Wallet])
+	if err != nil {
+		return nil, err
+	}
+	if len(locked) != len(uuids) {
+		return nil, pgx.ErrNoRows
+	}
+
+	res := make(map[uuid.UUID]string, len(locked))
+	for _, w := range locked {
+		res[w.UUID] = w.Currency
+	}
+	return res, nil
+}
@@ -0,0 +1,200 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// eventsChannel is the notification channel announcing the events written to the outbox.
+const eventsChannel = "wallet_events"
+
+// emit writes an event with the payload to the outbox and announces it on eventsChannel. It must run
+// inside the transaction of the change the event describes, so that the event is published and
+// announced if and only if the change is committed.
+func (s *storage) emit(ctx context.Context, eventType string, wallet uuid.UUID, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("db emit event error: %w", err)
+	}
+	query := `
+		WITH e AS (
+			INSERT INTO
+				outbox (id, type, wallet_uuid, payload)
+			VALUES
+				(@id, @type, @uuid, @payload)
+			RETURNING
+				seq, id, type, wallet_uuid, payload, created_at
+		)
+		SELECT
+			pg_notify(@channel, json_build_object(
+				'seq', seq,
+				'id', id,
+				'type', type,
+				'walletId', wallet_uuid,
+				'data', payload,
+				'createdAt', created_at
+			)::TEXT)
+		FROM
+			e
+	`
+	args := pgx.NamedArgs{
+		"id":      uuid.New(),
+		"type":    eventType,
+		"uuid":    wallet,
+		"payload": json.RawMessage(data),
+		"channel": eventsChannel,
+	}
+	_, err = s.conn(ctx).Exec(ctx, query, args)
+	if err != nil {
+		return fmt.Errorf("db emit event error: %w", err)
+	}
+	return nil
+}
+
+// PendingEvents returns up to limit events not published yet, oldest first. Called inside a transaction,
+// the events stay locked until it ends and are skipped by concurrent relays.
+func (s *storage) PendingEvents(ctx context.Context, limit int) ([]model.Event, error) {
+	query := `
+		SELECT
+			seq,
+			id,
+			type,
+			wallet_uuid,
+			payload,
+			created_at
+		FROM
+			outbox
+		WHERE
+			published_at IS NULL
+		ORDER BY
+			seq
+		LIMIT
+			@limit
+		FOR UPDATE SKIP LOCKED
+	`
+	args := pgx.NamedArgs{
+		"limit": limit,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.Event])
+}
+
+// MarkPublished marks the events with the sequence numbers as published.
+func (s *storage) MarkPublished(ctx context.Context, seqs []int64) error {
+	query := `
+		UPDATE
+			outbox
+		SET
+			published_at = now()
+		WHERE
+			seq = ANY(@seqs)
+	`
+	args := pgx.NamedArgs{
+		"seqs": seqs,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
+
+// WalletEvents returns up to limit events of a wallet following the one with sequence number after, oldest first,
+// starting with the last overlap events of the wallet up to that one. Sequence numbers are assigned when events
+// are written, not when they are committed, so the overlap also returns events committed after later ones.
+func (s *storage) WalletEvents(ctx context.Context, wallet uuid.UUID, after int64, overlap int, limit int) ([]model.Event, error) {
+	query := `
+		SELECT
+			seq,
+			id,
+			type,
+			wallet_uuid,
+			payload,
+			created_at
+		FROM
+			outbox
+		WHERE
+			wallet_uuid = @uuid
+			AND seq > COALESCE((
+				SELECT
+					seq
+				FROM
+					outbox
+				WHERE
+					wallet_uuid = @uuid
+					AND seq <= @after
+				ORDER BY
+					seq DESC
+				OFFSET
+					@overlap
+				LIMIT
+					1
+			), 0)
+		ORDER BY
+			seq
+		LIMIT
+			@limit
+	`
+	args := pgx.NamedArgs{
+		"uuid":    wallet,
+		"after":   after,
+		"overlap": overlap,
+		"limit":   limit,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.Event])
+}
+
+// notification is an event as announced on eventsChannel.
+type notification struct {
+	model.Event
+	Seq int64 `json:"seq"`
+}
+
+// ListenEvents calls fn with every event announced on eventsChannel by any instance until ctx is done,
+// in which case it returns nil. It holds a connection of the pool for as long as it listens and returns
+// the error that broke the connection otherwise; events announced until it listens again are missed.
+func (s *storage) ListenEvents(ctx context.Context, fn func(e model.Event)) error {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+eventsChannel)
+	if err != nil {
+		return err
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "UNLISTEN "+eventsChannel)
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var e notification
+		err = json.Unmarshal([]byte(n.Payload), &e)
+		if err != nil {
+			return fmt.Errorf("db decode event notification error: %w", err)
+		}
+		e.Event.Seq = e.Seq
+		fn(e.Event)
+	}
+}
@@ -2,60 +2,185 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Storage interface {
-	Create(ctx context.Context, uuid uuid.UUID) error
+	Create(ctx context.Context, w model.Wallet, uniqueOwnerCurrency bool) error
 	Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, error)
-	Deposit(ctx context.Context, uuid uuid.UUID, amount float64) (model.Wallet, error)
-	Withdraw(ctx context.Context, uuid uuid.UUID, amount float64) (model.Wallet, error)
+	OwnerWallets(ctx context.Context, ownerID string) ([]model.Wallet, error)
+	Deposit(ctx context.Context, uuid uuid.UUID, amount money.Amount) (model.Wallet, error)
+	Withdraw(ctx context.Context, uuid uuid.UUID, amount money.Amount) (model.Wallet, error)
+	Transfer(ctx context.Context, t Transfer) (model.Wallet, error)
+	Transactions(ctx context.Context, filter TransactionFilter) ([]model.Transaction, error)
+	ClaimIdempotencyKey(ctx context.Context, key string, fingerprint string, retention time.Duration) (bool, error)
+	IdempotencyRecord(ctx context.Context, key string) (model.IdempotencyRecord, error)
+	SaveIdempotencyResponse(ctx context.Context, key string, status int, body []byte) error
+	PurgeIdempotencyKeys(ctx context.Context, retention time.Duration) (int64, error)
+	CreateHold(ctx context.Context, id uuid.UUID, wallet uuid.UUID, amount money.Amount, ttl time.Duration) (model.Hold, error)
+	Hold(ctx context.Context, id uuid.UUID) (model.Hold, error)
+	CaptureHold(ctx context.Context, id uuid.UUID, amount money.Amount) (model.Hold, error)
+	VoidHold(ctx context.Context, id uuid.UUID) (model.Hold, error)
+	SetStatus(ctx context.Context, uuid uuid.UUID, from []string, to string, reason string) (model.Wallet, model.Wallet, error)
+	Operation(ctx context.Context, id uuid.UUID) (model.Operation, error)
+	Reverse(ctx context.Context, r Reversal) (model.Operation, error)
+	ExpireHolds(ctx context.Context) (int64, error)
+	CreateAPIKey(ctx context.Context, k model.APIKey) error
+	APIKeyByHash(ctx context.Context, hash string) (model.APIKey, error)
+	APIKeys(ctx context.Context) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	TakeToken(ctx context.Context, key string, interval time.Duration, burst int) (time.Duration, error)
+	Limits(ctx context.Context, wallet uuid.UUID) (model.WalletLimits, error)
+	SetLimits(ctx context.Context, l model.WalletLimits) (model.WalletLimits, error)
+	RecordAudit(ctx context.Context, r model.AuditRecord) error
+	AuditLog(ctx context.Context, filter AuditFilter) ([]model.AuditRecord, error)
+	PendingEvents(ctx context.Context, limit int) ([]model.Event, error)
+	MarkPublished(ctx context.Context, seqs []int64) error
+	WalletEvents(ctx context.Context, wallet uuid.UUID, after int64, overlap int, limit int) ([]model.Event, error)
+	ListenEvents(ctx context.Context, fn func(e model.Event)) error
+	CreateWebhook(ctx context.Context, w model.Webhook) (model.Webhook, error)
+	Webhooks(ctx context.Context) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error)
+	EnqueueDeliveries(ctx context.Context, e model.Event) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	SaveDelivery(ctx context.Context, d model.WebhookDelivery) error
+	WebhookDeliveries(ctx context.Context, filter DeliveryFilter) ([]model.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, error)
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Transfer describes a movement of money between two wallets.
+// Debit is taken from the source wallet and Credit is added to the destination one.
+// They differ for transfers between currencies, in which case Rate holds
+// the applied exchange rate as a decimal string.
+type Transfer struct {
+	From   uuid.UUID
+	To     uuid.UUID
+	Debit  money.Amount
+	Credit money.Amount
+	Rate   string
+}
+
+var (
+	// ErrInsufficientFunds is returned when an operation would drive a wallet balance below zero.
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrWalletExists is returned when the owner already has a wallet in the currency
+	// and only one is allowed.
+	ErrWalletExists = errors.New("owner already has a wallet in the currency")
+)
+
+// walletColumns selects a wallet as model.Wallet.
+const walletColumns = `
+	uuid,
+	balance,
+	balance - held AS available,
+	currency,
+	status,
+	owner_id
+`
+
+// checkViolation is the Postgres error code reported when a CHECK constraint fails.
+const checkViolation = "23514"
+
+// TransactionFilter narrows down the ledger entries returned by Storage.Transactions.
+// Zero values mean the corresponding filter is not applied.
+type TransactionFilter struct {
+	UUID     uuid.UUID
+	BeforeID int64
+	Type     string
+	From     time.Time
+	To       time.Time
+	Limit    int
 }
 
 type storage struct {
-	db *pgxpool.Pool
+	db            *pgxpool.Pool
+	frozenCredits bool
 }
 
-func New(p *pgxpool.Pool) Storage {
-	return &storage{
-		db: p,
+// Option configures the storage.
+type Option func(s *storage)
+
+func New(p *pgxpool.Pool, opts ...Option) Storage {
+	s := &storage{
+		db:            p,
+		frozenCredits: true,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Create inserts a new wallet record into the database and returns any encountered errors.
-func (s *storage) Create(ctx context.Context, uuid uuid.UUID) error {
-	query := `
-		INSERT INTO
-			wallets (uuid)
-		VALUES
-			(@uuid)
-		RETURNING
-			uuid
-	`
-	args := pgx.NamedArgs{
-		"uuid": uuid,
-	}
-	row := s.db.QueryRow(ctx, query, args)
-	err := row.Scan(&uuid)
-	if err != nil {
-		return fmt.Errorf("db create wallet error: %v", err)
-	}
-	return nil
+// With uniqueOwnerCurrency set, a wallet of an owner is only created if the owner has no wallet
+// in the same currency yet, otherwise ErrWalletExists is returned. Concurrent creations for
+// the same owner and currency are serialized with a transaction-level advisory lock.
+// A wallet.created event is emitted along with the wallet.
+func (s *storage) Create(ctx context.Context, w model.Wallet, uniqueOwnerCurrency bool) error {
+	return s.inTx(ctx, func(ctx context.Context) error {
+		args := pgx.NamedArgs{
+			"uuid":     w.UUID,
+			"currency": w.Currency,
+			"owner":    w.OwnerID,
+		}
+		if uniqueOwnerCurrency && w.OwnerID != nil {
+			query := `
+				SELECT
+					pg_advisory_xact_lock(hashtextextended(@owner || '/' || @currency, 0))
+			`
+			_, err := s.conn(ctx).Exec(ctx, query, args)
+			if err != nil {
+				return fmt.Errorf("db create wallet error: %v", err)
+			}
+
+			query = `
+				SELECT
+					EXISTS (SELECT 1 FROM wallets WHERE owner_id = @owner AND currency = @currency)
+			`
+			var exists bool
+			err = s.conn(ctx).QueryRow(ctx, query, args).Scan(&exists)
+			if err != nil {
+				return fmt.Errorf("db create wallet error: %v", err)
+			}
+			if exists {
+				return ErrWalletExists
+			}
+		}
+
+		query := `
+			INSERT INTO
+				wallets (uuid, currency, owner_id)
+			VALUES
+				(@uuid, @currency, @owner)
+			RETURNING
+				uuid
+		`
+		row := s.conn(ctx).QueryRow(ctx, query, args)
+		err := row.Scan(&w.UUID)
+		if err != nil {
+			return fmt.Errorf("db create wallet error: %v", err)
+		}
+		return s.emit(ctx, model.EventWalletCreated, w.UUID, w)
+	})
 }
 
 // Balance retrieves balance information from the database for a specified wallet UUID.
 func (s *storage) Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, error) {
 	var res model.Wallet
 	query := `
-		SELECT 
-			uuid,
-			balance
+		SELECT` + walletColumns + `
 		FROM
 			wallets
 		WHERE
@@ -64,7 +189,7 @@ func (s *storage) Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, er
 	args := pgx.NamedArgs{
 		"uuid": uuid,
 	}
-	rows, err := s.db.Query(ctx, query, args)
+	rows, err := s.conn(ctx).Query(ctx, query, args)
 	defer rows.Close()
 
 	if err != nil {
@@ -80,60 +205,321 @@ func (s *storage) Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, er
 	return res, nil
 }
 
+// OwnerWallets returns the wallets of an owner, oldest first.
+func (s *storage) OwnerWallets(ctx context.Context, ownerID string) ([]model.Wallet, error) {
+	query := `
+		SELECT` + walletColumns + `
+		FROM
+			wallets
+		WHERE
+			owner_id = @owner
+		ORDER BY
+			created_at, uuid
+	`
+	args := pgx.NamedArgs{
+		"owner": ownerID,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.Wallet])
+}
+
 // Deposit updates the balance of a wallet by adding a specified amount and returns updated wallet data.
-func (s *storage) Deposit(ctx context.Context, uuid uuid.UUID, amount float64) (model.Wallet, error) {
+func (s *storage) Deposit(ctx context.Context, uuid uuid.UUID, amount money.Amount) (model.Wallet, error) {
+	return s.apply(ctx, uuid, "DEPOSIT", amount)
+}
+
+// Withdraw subtracts a specified amount from the wallet's balance and returns updated wallet data.
+func (s *storage) Withdraw(ctx context.Context, uuid uuid.UUID, amount money.Amount) (model.Wallet, error) {
+	return s.apply(ctx, uuid, "WITHDRAW", -amount)
+}
+
+// Transfer moves money from one wallet to another and returns updated data of the debited wallet.
+// Both wallet rows are locked in UUID order before any change, so concurrent transfers
+// between the same pair of wallets in opposite directions cannot deadlock.
+// A transfer.completed event is emitted after the events of both legs.
+func (s *storage) Transfer(ctx context.Context, t Transfer) (model.Wallet, error) {
+	var res model.Wallet
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		currencies, err := s.lockWallets(ctx, t.From, t.To)
+		if err != nil {
+			return err
+		}
+
+		op := uuid.New()
+		debit := entry{
+			op:           op,
+			wallet:       t.From,
+			opType:       "TRANSFER",
+			delta:        -t.Debit,
+			counterparty: &t.To,
+		}
+		credit := entry{
+			op:           op,
+			wallet:       t.To,
+			opType:       "TRANSFER",
+			delta:        t.Credit,
+			counterparty: &t.From,
+		}
+		if t.Rate != "" {
+			debit.counterAmount, debit.counterCurrency = &t.Credit, currencies[t.To]
+			credit.counterAmount, credit.counterCurrency = &t.Debit, currencies[t.From]
+			debit.rate, credit.rate = t.Rate, t.Rate
+		}
+
+		err = s.record(ctx, model.Operation{
+			ID:            op,
+			Type:          "TRANSFER",
+			UUID:          t.From,
+			Amount:        t.Debit,
+			Counterparty:  &t.To,
+			CounterAmount: &t.Credit,
+		})
+		if err != nil {
+			return err
+		}
+
+		res, err = s.change(ctx, debit)
+		if err != nil {
+			return err
+		}
+		credited, err := s.change(ctx, credit)
+		if err != nil {
+			return err
+		}
+
+		return s.emit(ctx, model.EventTransferCompleted, t.From, model.TransferCompleted{
+			OperationID:    op,
+			From:           t.From,
+			To:             t.To,
+			Debit:          t.Debit,
+			DebitCurrency:  res.Currency,
+			Credit:         t.Credit,
+			CreditCurrency: credited.Currency,
+			Rate:           t.Rate,
+		})
+	})
+	return res, err
+}
+
+// apply records an operation changing the wallet's balance by delta and appends the matching
+// ledger entry to wallet_transactions within a single database transaction.
+func (s *storage) apply(ctx context.Context, wallet uuid.UUID, opType string, delta money.Amount) (model.Wallet, error) {
+	var res model.Wallet
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		op := model.Operation{
+			ID:     uuid.New(),
+			Type:   opType,
+			UUID:   wallet,
+			Amount: delta,
+		}
+		if delta < 0 {
+			op.Amount = -delta
+		}
+		err := s.record(ctx, op)
+		if err != nil {
+			return err
+		}
+
+		res, err = s.change(ctx, entry{
+			op:     op.ID,
+			wallet: wallet,
+			opType: opType,
+			delta:  delta,
+		})
+		return err
+	})
+	return res, err
+}
+
+// entry is a ledger entry written by change.
+// The counter fields and rate are only set for transfers between currencies.
+type entry struct {
+	op              uuid.UUID
+	wallet          uuid.UUID
+	opType          string
+	delta           money.Amount
+	counterparty    *uuid.UUID
+	counterAmount   *money.Amount
+	counterCurrency string
+	rate            string
+}
+
+// change updates the wallet's balance by the entry delta, records the ledger entry and emits
+// a wallet.credited or wallet.debited event. The update is skipped when the wallet status does not allow the change,
+// in which case ErrWalletFrozen or ErrWalletClosed is returned, or when a debit would leave the available balance
+// negative, in which case ErrInsufficientFunds is returned. Credits are applied even when the available balance
+// is negative, which they can only improve. Operations other than reversals must also stay within
+// the limits of the wallet, otherwise a LimitError is returned. It must run inside a transaction started by inTx.
+func (s *storage) change(ctx context.Context, e entry) (model.Wallet, error) {
 	var res model.Wallet
 	query := `
 		UPDATE 
 			wallets
 		SET
-			balance = balance + @amount
+			balance = balance + @delta
 		WHERE
 			uuid = @uuid
-		RETURNING balance
+			AND status = ANY(@statuses)
+			AND (@delta >= 0 OR balance - held + @delta >= 0)
+		RETURNING balance, balance - held, currency, status, owner_id
 	`
+	statuses := s.statuses(e.delta < 0)
 	args := pgx.NamedArgs{
-		"uuid":   uuid,
-		"amount": amount,
+		"uuid":     e.wallet,
+		"delta":    e.delta,
+		"statuses": statuses,
+	}
+	err := s.conn(ctx).QueryRow(ctx, query, args).Scan(&res.Balance, &res.Available, &res.Currency, &res.Status, &res.OwnerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return res, s.refusal(ctx, e.wallet, statuses)
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == checkViolation {
+		return res, ErrInsufficientFunds
+	}
+	if err != nil {
+		return res, err
+	}
+
+	query = `
+		INSERT INTO
+			wallet_transactions (
+				operation_id, wallet_uuid, type, amount, balance, counterparty_uuid,
+				counter_amount, counter_currency, rate
+			)
+		VALUES
+			(
+				@operation, @uuid, @type, @delta, @balance, @counterparty,
+				@counterAmount, NULLIF(@counterCurrency, ''), NULLIF(@rate, '')::NUMERIC
+			)
+	`
+	args["operation"] = e.op
+	args["type"] = e.opType
+	args["balance"] = res.Balance
+	args["counterparty"] = e.counterparty
+	args["counterAmount"] = e.counterAmount
+	args["counterCurrency"] = e.counterCurrency
+	args["rate"] = e.rate
+	_, err = s.conn(ctx).Exec(ctx, query, args)
+	if err != nil {
+		return res, fmt.Errorf("db insert wallet transaction error: %w", err)
 	}
-	row := s.db.QueryRow(ctx, query, args)
 
-	err := row.Scan(&res.Balance)
+	if e.opType != "REVERSAL" {
+		err = s.checkLimits(ctx, e, res.Balance)
+		if err != nil {
+			return res, err
+		}
+	}
 
+	res.UUID = e.wallet
+
+	event := model.BalanceChange{
+		UUID:         e.wallet,
+		OperationID:  e.op,
+		Type:         e.opType,
+		Amount:       e.delta,
+		Currency:     res.Currency,
+		Balance:      res.Balance,
+		Available:    res.Available,
+		Counterparty: e.counterparty,
+	}
+	eventType := model.EventWalletCredited
+	if e.delta < 0 {
+		eventType = model.EventWalletDebited
+		event.Amount = -e.delta
+	}
+	err = s.emit(ctx, eventType, e.wallet, event)
 	if err != nil {
 		return res, err
 	}
 
-	res.UUID = uuid
-
 	return res, nil
 }
 
-// Withdraw subtracts a specified amount from the wallet's balance and returns updated wallet data.
-func (s *storage) Withdraw(ctx context.Context, uuid uuid.UUID, amount float64) (model.Wallet, error) {
-	var res model.Wallet
+// refusal tells why a guarded balance update of a wallet in one of statuses matched no rows:
+// pgx.ErrNoRows if the wallet does not exist, ErrWalletClosed or ErrWalletFrozen if its status
+// does not allow the update and ErrInsufficientFunds otherwise.
+func (s *storage) refusal(ctx context.Context, wallet uuid.UUID, statuses []string) error {
 	query := `
-		UPDATE 
+		SELECT
+			status
+		FROM
 			wallets
-		SET
-			balance = balance - @amount
 		WHERE
 			uuid = @uuid
-		RETURNING balance
 	`
 	args := pgx.NamedArgs{
-		"uuid":   uuid,
-		"amount": amount,
+		"uuid": wallet,
+	}
+	var status string
+	err := s.conn(ctx).QueryRow(ctx, query, args).Scan(&status)
+	if err != nil {
+		return err
 	}
-	row := s.db.QueryRow(ctx, query, args)
+	if !slices.Contains(statuses, status) {
+		return statusErr(status)
+	}
+	return ErrInsufficientFunds
+}
 
-	err := row.Scan(&res.Balance)
+// Transactions returns ledger entries of a wallet matching the filter, newest first.
+func (s *storage) Transactions(ctx context.Context, filter TransactionFilter) ([]model.Transaction, error) {
+	query := `
+		SELECT
+			id,
+			operation_id,
+			wallet_uuid,
+			type,
+			amount,
+			balance,
+			counterparty_uuid,
+			counter_amount,
+			counter_currency,
+			rate::TEXT AS rate,
+			created_at
+		FROM
+			wallet_transactions
+		WHERE
+			wallet_uuid = @uuid
+			AND (@before::BIGINT IS NULL OR id < @before)
+			AND (@type::TEXT IS NULL OR type = @type)
+			AND (@from::TIMESTAMPTZ IS NULL OR created_at >= @from)
+			AND (@to::TIMESTAMPTZ IS NULL OR created_at < @to)
+		ORDER BY
+			id DESC
+		LIMIT
+			@limit
+	`
+	args := pgx.NamedArgs{
+		"uuid":   filter.UUID,
+		"before": nil,
+		"type":   nil,
+		"from":   nil,
+		"to":     nil,
+		"limit":  filter.Limit,
+	}
+	if filter.BeforeID != 0 {
+		args["before"] = filter.BeforeID
+	}
+	if filter.Type != "" {
+		args["type"] = filter.Type
+	}
+	if !filter.From.IsZero() {
+		args["from"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		args["to"] = filter.To
+	}
 
+	rows, err := s.conn(ctx).Query(ctx, query, args)
 	if err != nil {
-		return res, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	res.UUID = uuid
-
-	return res, nil
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.Transaction])
 }
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TakeToken takes a token from the bucket of key, which is refilled every interval and holds
+// up to burst tokens. The bucket is stored as the theoretical arrival time (tat) of the next
+// request: a request is allowed unless tat is more than burst-1 intervals ahead, and each
+// allowed request moves tat one interval further. It returns zero if the token was taken and
+// otherwise how long until one is available.
+func (s *storage) TakeToken(ctx context.Context, key string, interval time.Duration, burst int) (time.Duration, error) {
+	query := `
+		INSERT INTO
+			rate_limits AS r (key, tat)
+		VALUES
+			(@key, now() + make_interval(secs => @interval))
+		ON CONFLICT (key) DO UPDATE
+		SET
+			tat = GREATEST(r.tat, now()) + make_interval(secs => @interval)
+		WHERE
+			r.tat <= now() + make_interval(secs => @tolerance)
+		RETURNING
+			tat
+	`
+	args := pgx.NamedArgs{
+		"key":       key,
+		"interval":  interval.Seconds(),
+		"tolerance": interval.Seconds() * float64(burst-1),
+	}
+	var tat time.Time
+	err := s.conn(ctx).QueryRow(ctx, query, args).Scan(&tat)
+	if err == nil {
+		return 0, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	query = `
+		SELECT
+			EXTRACT(EPOCH FROM tat - now()) - @tolerance
+		FROM
+			rate_limits
+		WHERE
+			key = @key
+	`
+	var wait float64
+	err = s.conn(ctx).QueryRow(ctx, query, args).Scan(&wait)
+	if err != nil {
+		return 0, err
+	}
+	return max(time.Duration(wait*float64(time.Second)), time.Millisecond), nil
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrStatusConflict is returned when the wallet status does not allow the requested change.
+	ErrStatusConflict = errors.New("wallet status conflict")
+	// ErrBalanceNotZero is returned when closing a wallet that still holds funds.
+	ErrBalanceNotZero = errors.New("wallet balance is not zero")
+	// ErrWalletFrozen is returned when debiting a frozen wallet, or crediting it
+	// while frozen wallets do not accept credits.
+	ErrWalletFrozen = errors.New("wallet is frozen")
+	// ErrWalletClosed is returned when debiting or crediting a closed wallet.
+	ErrWalletClosed = errors.New("wallet is closed")
+)
+
+// WithFrozenCredits sets whether frozen wallets accept credits. Debits are always rejected.
+func WithFrozenCredits(allow bool) Option {
+	return func(s *storage) {
+		s.frozenCredits = allow
+	}
+}
+
+// statuses returns the wallet statuses allowing a debit or a credit. Balance updates check them
+// in the statement taking the row lock, so that they cannot race with a status change.
+func (s *storage) statuses(debit bool) []string {
+	if debit || !s.frozenCredits {
+		return []string{model.WalletActive}
+	}
+	return []string{model.WalletActive, model.WalletFrozen}
+}
+
+// statusErr returns the error reporting that a wallet in status refused a balance update.
+func statusErr(status string) error {
+	if status == model.WalletClosed {
+		return ErrWalletClosed
+	}
+	return ErrWalletFrozen
+}
+
+// SetStatus changes the wallet status to to, records the change with its reason and returns
+// the wallet before and after the change. The wallet row is locked while its current status
+// is checked against from, so that concurrent changes are applied one after another. Wallets
+// are only closed with a zero balance and no active holds, otherwise ErrBalanceNotZero is returned.
+func (s *storage) SetStatus(ctx context.Context, uuid uuid.UUID, from []string, to string, reason string) (model.Wallet, model.Wallet, error) {
+	var before, res model.Wallet
+	err := s.inTx(ctx, func(ctx context.Context) error {
+		query := `
+			SELECT` + walletColumns + `
+			FROM
+				wallets
+			WHERE
+				uuid = @uuid
+			FOR UPDATE
+		`
+		args := pgx.NamedArgs{
+			"uuid":   uuid,
+			"to":     to,
+			"reason": reason,
+		}
+		rows, err := s.conn(ctx).Query(ctx, query, args)
+		if err != nil {
+			return err
+		}
+		before, err = pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.Wallet])
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(from, before.Status) {
+			return ErrStatusConflict
+		}
+		if to == model.WalletClosed && (before.Balance != 0 || before.Available != 0) {
+			return ErrBalanceNotZero
+		}
+
+		query = `
+			UPDATE
+				wallets
+			SET
+				status = @to
+			WHERE
+				uuid = @uuid
+		`
+		_, err = s.conn(ctx).Exec(ctx, query, args)
+		if err != nil {
+			return err
+		}
+
+		query = `
+			INSERT INTO
+				wallet_status_changes (wallet_uuid, from_status, to_status, reason)
+			VALUES
+				(@uuid, @from, @to, @reason)
+		`
+		args["from"] = before.Status
+		_, err = s.conn(ctx).Exec(ctx, query, args)
+		if err != nil {
+			return err
+		}
+
+		res = before
+		res.Status = to
+		return nil
+	})
+	return before, res, err
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type txKey struct{}
+
+// querier is the part of the pgx API shared by the pool and transactions.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction bound to ctx, or the pool when there is none.
+func (s *storage) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return s.db
+}
+
+// WithTx runs fn inside a database transaction. Storage methods called with
+// the context passed to fn take part in that transaction.
+func (s *storage) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.inTx(ctx, fn)
+}
+
+// inTx runs fn inside a database transaction bound to the context passed to fn.
+// When ctx already carries a transaction, a savepoint is used instead so that
+// a failing fn rolls back only its own changes.
+// The transaction is committed if fn returns nil and rolled back otherwise.
+func (s *storage) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	var tx pgx.Tx
+	var err error
+
+	if parent, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		tx, err = parent.Begin(ctx)
+	} else {
+		tx, err = s.db.Begin(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
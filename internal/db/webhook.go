@@ -0,0 +1,272 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// deliveryColumns selects a delivery joined with its webhook as model.WebhookDelivery.
+const deliveryColumns = `
+	d.id,
+	d.webhook_id,
+	w.url,
+	w.secret,
+	d.event_id,
+	d.event_type,
+	d.payload,
+	d.status,
+	d.attempts,
+	d.last_status,
+	d.last_error,
+	d.next_attempt_at,
+	d.created_at,
+	d.delivered_at
+`
+
+// DeliveryFilter narrows down the deliveries returned by Storage.WebhookDeliveries.
+// An empty status means deliveries in any status.
+type DeliveryFilter struct {
+	WebhookID uuid.UUID
+	Status    string
+	Limit     int
+}
+
+// CreateWebhook stores a new webhook.
+func (s *storage) CreateWebhook(ctx context.Context, w model.Webhook) (model.Webhook, error) {
+	query := `
+		INSERT INTO
+			webhooks (id, url, secret, event_types)
+		VALUES
+			(@id, @url, @secret, @eventTypes)
+		RETURNING
+			id, url, secret, event_types, created_at
+	`
+	args := pgx.NamedArgs{
+		"id":         w.ID,
+		"url":        w.URL,
+		"secret":     w.Secret,
+		"eventTypes": w.EventTypes,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.Webhook])
+}
+
+// Webhooks returns all webhooks, oldest first.
+func (s *storage) Webhooks(ctx context.Context) ([]model.Webhook, error) {
+	query := `
+		SELECT
+			id,
+			url,
+			secret,
+			event_types,
+			created_at
+		FROM
+			webhooks
+		ORDER BY
+			created_at, id
+	`
+	rows, err := s.conn(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.Webhook])
+}
+
+// DeleteWebhook removes a webhook along with its deliveries and returns it. It returns pgx.ErrNoRows if there is no such webhook.
+func (s *storage) DeleteWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error) {
+	query := `
+		DELETE FROM
+			webhooks
+		WHERE
+			id = @id
+		RETURNING
+			id, url, secret, event_types, created_at
+	`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.Webhook])
+}
+
+// EnqueueDeliveries schedules the delivery of an event to every webhook subscribed to its type.
+// An event already scheduled for a webhook is not scheduled again, so republished events are delivered once.
+func (s *storage) EnqueueDeliveries(ctx context.Context, e model.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO
+			webhook_deliveries (id, webhook_id, event_id, event_type, payload)
+		SELECT
+			gen_random_uuid(), id, @eventID, @eventType, @payload
+		FROM
+			webhooks
+		WHERE
+			@eventType = ANY(event_types)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+	args := pgx.NamedArgs{
+		"eventID":   e.ID,
+		"eventType": e.Type,
+		"payload":   json.RawMessage(payload),
+	}
+	_, err = s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
+
+// ClaimDeliveries returns up to limit pending deliveries that are due, oldest first, counting an attempt
+// for each of them. Claimed deliveries are not due again for the lease, so that concurrent workers do not
+// pick them up while they are being delivered; if the worker stops before saving the outcome, the delivery
+// is retried once the lease expires.
+func (s *storage) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE
+				webhook_deliveries
+			SET
+				attempts = attempts + 1,
+				next_attempt_at = now() + make_interval(secs => @lease)
+			WHERE
+				id IN (
+					SELECT
+						id
+					FROM
+						webhook_deliveries
+					WHERE
+						status = 'PENDING'
+						AND next_attempt_at <= now()
+					ORDER BY
+						next_attempt_at
+					LIMIT
+						@limit
+					FOR UPDATE SKIP LOCKED
+				)
+			RETURNING
+				*
+		)
+		SELECT` + deliveryColumns + `
+		FROM
+			claimed d
+			JOIN webhooks w ON w.id = d.webhook_id
+		ORDER BY
+			d.created_at, d.id
+	`
+	args := pgx.NamedArgs{
+		"limit": limit,
+		"lease": lease.Seconds(),
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookDelivery])
+}
+
+// SaveDelivery stores the outcome of a delivery attempt: its status, the next attempt time
+// and the details of the last failure.
+func (s *storage) SaveDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	query := `
+		UPDATE
+			webhook_deliveries
+		SET
+			status = @status,
+			last_status = @lastStatus,
+			last_error = @lastError,
+			next_attempt_at = @nextAttemptAt,
+			delivered_at = @deliveredAt
+		WHERE
+			id = @id
+	`
+	args := pgx.NamedArgs{
+		"id":            d.ID,
+		"status":        d.Status,
+		"lastStatus":    d.LastStatus,
+		"lastError":     d.LastError,
+		"nextAttemptAt": d.NextAttemptAt,
+		"deliveredAt":   d.DeliveredAt,
+	}
+	_, err := s.conn(ctx).Exec(ctx, query, args)
+	return err
+}
+
+// WebhookDeliveries returns the deliveries of a webhook matching the filter, newest first.
+func (s *storage) WebhookDeliveries(ctx context.Context, filter DeliveryFilter) ([]model.WebhookDelivery, error) {
+	query := `
+		SELECT` + deliveryColumns + `
+		FROM
+			webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+		WHERE
+			d.webhook_id = @webhook
+			AND (@status::TEXT IS NULL OR d.status = @status)
+		ORDER BY
+			d.created_at DESC, d.id
+		LIMIT
+			@limit
+	`
+	args := pgx.NamedArgs{
+		"webhook": filter.WebhookID,
+		"status":  nil,
+		"limit":   filter.Limit,
+	}
+	if filter.Status != "" {
+		args["status"] = filter.Status
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return pgx.CollectRows(rows, pgx.RowToStructByName[model.WebhookDelivery])
+}
+
+// Redeliver schedules a delivery, whatever its status, for a new series of attempts starting right away.
+// It returns pgx.ErrNoRows if there is no such delivery.
+func (s *storage) Redeliver(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, error) {
+	query := `
+		WITH updated AS (
+			UPDATE
+				webhook_deliveries
+			SET
+				status = 'PENDING',
+				attempts = 0,
+				next_attempt_at = now(),
+				delivered_at = NULL
+			WHERE
+				id = @id
+			RETURNING
+				*
+		)
+		SELECT` + deliveryColumns + `
+		FROM
+			updated d
+			JOIN webhooks w ON w.id = d.webhook_id
+	`
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+	rows, err := s.conn(ctx).Query(ctx, query, args)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[model.WebhookDelivery])
+}
@@ -1,9 +1,119 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WalletCreateRequest struct {
+	Currency string `json:"currency" validate:"omitempty,iso4217"`
+	OwnerID  string `json:"ownerId" validate:"omitempty,max=255"`
+}
 
 type WalletTransactionRequest struct {
-	UUID   uuid.UUID `json:"valletId" validate:"required,uuid"`
-	Type   string    `json:"operationType" validate:"required,oneof=DEPOSIT WITHDRAW"`
-	Amount float64   `json:"amount" validate:"required,gte=0.01"`
+	UUID     uuid.UUID    `json:"walletId" validate:"required,uuid"`
+	Type     string       `json:"operationType" validate:"required,oneof=DEPOSIT WITHDRAW TRANSFER"`
+	Amount   money.Amount `json:"amount" validate:"required,gt=0"`
+	ToUUID   uuid.UUID    `json:"toWalletId" validate:"required_if=Type TRANSFER,excluded_unless=Type TRANSFER"`
+	Currency string       `json:"currency,omitempty" validate:"omitempty,iso4217"`
+}
+
+// ErrWalletIDMismatch is returned for transaction requests carrying different walletId and valletId.
+var ErrWalletIDMismatch = errors.New("walletId and valletId differ")
+
+// UnmarshalJSON decodes the request, accepting the deprecated valletId as an alias of walletId.
+func (r *WalletTransactionRequest) UnmarshalJSON(data []byte) error {
+	type request WalletTransactionRequest
+	aux := struct {
+		*request
+		LegacyUUID *uuid.UUID `json:"valletId"`
+	}{request: (*request)(r)}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	if aux.LegacyUUID != nil {
+		if r.UUID != uuid.Nil && r.UUID != *aux.LegacyUUID {
+			return ErrWalletIDMismatch
+		}
+		r.UUID = *aux.LegacyUUID
+	}
+	return nil
+}
+
+type TransactionHistoryRequest struct {
+	UUID   uuid.UUID `form:"-" validate:"required"`
+	Cursor string    `form:"cursor"`
+	Limit  int       `form:"limit" validate:"omitempty,min=1,max=100"`
+	Type   string    `form:"operationType" validate:"omitempty,oneof=OPENING DEPOSIT WITHDRAW TRANSFER CAPTURE REVERSAL"`
+	From   time.Time `form:"from"`
+	To     time.Time `form:"to" validate:"omitempty,gtfield=From"`
+}
+
+type TransactionHistoryResponse struct {
+	Transactions []model.Transaction `json:"transactions"`
+	NextCursor   string              `json:"nextCursor,omitempty"`
+}
+
+type HoldCreateRequest struct {
+	UUID     uuid.UUID    `json:"-" validate:"required"`
+	Amount   money.Amount `json:"amount" validate:"required,gt=0"`
+	Currency string       `json:"currency,omitempty" validate:"omitempty,iso4217"`
+	TTL      int          `json:"ttlSeconds,omitempty" validate:"omitempty,min=1,max=604800"`
+}
+
+type HoldCaptureRequest struct {
+	ID     uuid.UUID    `json:"-" validate:"required"`
+	Amount money.Amount `json:"amount,omitempty" validate:"omitempty,gt=0"`
+}
+
+type ReversalRequest struct {
+	ID     uuid.UUID    `json:"-" validate:"required"`
+	Amount money.Amount `json:"amount,omitempty" validate:"omitempty,gt=0"`
+}
+
+type WalletStatusRequest struct {
+	UUID   uuid.UUID `json:"-" validate:"required"`
+	Status string    `json:"-" validate:"required,oneof=ACTIVE FROZEN CLOSED"`
+	Reason string    `json:"reason" validate:"required,max=1000"`
+}
+
+type WalletLimitsRequest struct {
+	UUID          uuid.UUID     `json:"-" validate:"required"`
+	MaxWithdrawal *money.Amount `json:"maxWithdrawal" validate:"omitempty,gt=0"`
+	DailyDebit    *money.Amount `json:"dailyDebit" validate:"omitempty,gt=0"`
+	MonthlyDebit  *money.Amount `json:"monthlyDebit" validate:"omitempty,gt=0"`
+	MaxBalance    *money.Amount `json:"maxBalance" validate:"omitempty,gt=0"`
+}
+
+type AuditLogRequest struct {
+	UUID      uuid.UUID `form:"-"`
+	Action    string    `form:"action" validate:"omitempty,max=64"`
+	Principal string    `form:"principal" validate:"omitempty,max=256"`
+	Cursor    string    `form:"cursor"`
+	Limit     int       `form:"limit" validate:"omitempty,min=1,max=100"`
+	From      time.Time `form:"from"`
+	To        time.Time `form:"to" validate:"omitempty,gtfield=From"`
+}
+
+type AuditLogResponse struct {
+	Records    []model.AuditRecord `json:"records"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+}
+
+type WebhookCreateRequest struct {
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	Secret     string   `json:"secret" validate:"required,min=16,max=256"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=wallet.created wallet.credited wallet.debited transfer.completed hold.created hold.voided hold.expired"`
+}
+
+type WebhookDeliveriesRequest struct {
+	WebhookID uuid.UUID `form:"-" validate:"required"`
+	Status    string    `form:"status" validate:"omitempty,oneof=PENDING DELIVERED DEAD"`
+	Limit     int       `form:"limit" validate:"omitempty,min=1,max=100"`
 }
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestIDHeader carries the id of a request, chosen by the client or generated, and is echoed in the response.
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestID limits the length of request ids accepted from clients.
+const maxRequestID = 128
+
+// requestInfo attaches the source IP and the request id to the request context for the audit log.
+// Client-supplied ids that are too long or not printable ASCII are replaced with a generated one.
+func (h *handler) requestInfo(c *gin.Context) {
+	id := c.GetHeader(requestIDHeader)
+	if !validRequestID(id) {
+		id = uuid.NewString()
+	}
+	c.Header(requestIDHeader, id)
+
+	info := service.RequestInfo{
+		SourceIP:  c.ClientIP(),
+		RequestID: id,
+	}
+	c.Request = c.Request.WithContext(service.WithRequestInfo(c.Request.Context(), info))
+	c.Next()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestID {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// AuditLog returns a page of the audit log, newest first, optionally filtered by wallet, action,
+// principal and time range.
+func (h *handler) AuditLog(c *gin.Context) {
+	req := dto.AuditLogRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	if walletID := c.Query("walletId"); walletID != "" {
+		req.UUID, err = uuid.Parse(walletID)
+		if err != nil {
+			h.invalidParam(c, "walletId", "incorrect wallet uuid")
+			return
+		}
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	res, err := h.walletService.AuditLog(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	mocks "cmd/app/main.go/internal/service/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestAuditLog_Filters", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		fakeReq := dto.AuditLogRequest{
+			UUID:      fakeUUID,
+			Action:    model.AuditWalletFreeze,
+			Principal: "key-1",
+			Limit:     10,
+			From:      from,
+			To:        to,
+		}
+		fakeService.EXPECT().AuditLog(gomock.Any(), fakeReq).Return(dto.AuditLogResponse{Records: []model.AuditRecord{}}, nil)
+
+		query := url.Values{
+			"walletId":  {fakeUUID.String()},
+			"action":    {model.AuditWalletFreeze},
+			"principal": {"key-1"},
+			"limit":     {"10"},
+			"from":      {from.Format(time.RFC3339)},
+			"to":        {to.Format(time.RFC3339)},
+		}
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/admin/audit?"+query.Encode(), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestAuditLog_InvalidWallet", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/admin/audit?walletId=123", nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+}
+
+func TestRequestInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	balance := func(t *testing.T, requestID string) *httptest.ResponseRecorder {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		if requestID != "" {
+			req.Header.Set(requestIDHeader, requestID)
+		}
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		return recoder
+	}
+
+	t.Run("TestRequestInfo_ClientID", func(t *testing.T) {
+		recoder := balance(t, "req-42")
+		got := recoder.Header().Get(requestIDHeader)
+		if got != "req-42" {
+			t.Errorf("Expected: %v, recieved: %v", "req-42", got)
+		}
+	})
+
+	t.Run("TestRequestInfo_Generated", func(t *testing.T) {
+		recoder := balance(t, "")
+		_, err := uuid.Parse(recoder.Header().Get(requestIDHeader))
+		if err != nil {
+			t.Errorf("Expected: %v, recieved: %v", "generated uuid", recoder.Header().Get(requestIDHeader))
+		}
+	})
+
+	t.Run("TestRequestInfo_InvalidClientID", func(t *testing.T) {
+		recoder := balance(t, "bad id\x7f")
+		_, err := uuid.Parse(recoder.Header().Get(requestIDHeader))
+		if err != nil {
+			t.Errorf("Expected: %v, recieved: %v", "generated uuid", recoder.Header().Get(requestIDHeader))
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Option configures the handler.
+type Option func(*handler)
+
+// WithAuthenticator requires requests to be authenticated by a.
+// Authenticators are tried in the order they were added until one recognizes the credentials.
+// Without authenticators every request is allowed.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(h *handler) {
+		h.authenticators = append(h.authenticators, a)
+	}
+}
+
+// authenticate attaches the principal of the request to its context,
+// or rejects the request with 401 Unauthorized if it cannot be authenticated.
+func (h *handler) authenticate(c *gin.Context) {
+	if len(h.authenticators) == 0 {
+		c.Next()
+		return
+	}
+
+	for _, a := range h.authenticators {
+		p, err := a.Authenticate(c.Request.Context(), c.Request)
+		if errors.Is(err, auth.ErrNoCredentials) {
+			continue
+		}
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			h.sendError(c, http.StatusUnauthorized, service.ErrUnauthenticated.WithMessage(err.Error()))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			log.Println("authenticate err: ", err)
+			h.sendError(c, http.StatusInternalServerError, errInternal.WithMessage("internal server error"))
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		c.Next()
+		return
+	}
+
+	h.sendError(c, http.StatusUnauthorized, service.ErrUnauthenticated.WithMessage(auth.ErrNoCredentials.Error()))
+	c.Abort()
+}
+
+// require rejects requests whose principal was not granted scope with 403 Forbidden.
+// It lets every request through when authentication is disabled.
+func (h *handler) require(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(h.authenticators) == 0 {
+			c.Next()
+			return
+		}
+		p, ok := auth.FromContext(c.Request.Context())
+		if !ok || !p.HasScope(scope) {
+			h.sendError(c, http.StatusForbidden, service.ErrForbidden.WithMessage("missing scope "+scope))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// principal returns the principal of the request. Without authentication it is an unrestricted principal.
+func (h *handler) principal(c *gin.Context) auth.Principal {
+	p, _ := auth.FromContext(c.Request.Context())
+	return p
+}
+
+// authorizeWallet reports whether the principal of the request may access the wallet.
+// Principals restricted to an owner, such as end users authenticated by a token, may only access
+// that owner's wallets. Other wallets are reported as not found so that their existence is not disclosed.
+// The response is sent if access is denied.
+func (h *handler) authorizeWallet(c *gin.Context, id uuid.UUID) bool {
+	return h.authorizeOwner(c, id, service.ErrWalletNotFound)
+}
+
+// authorizeOwner reports whether the principal of the request may access a resource of the wallet,
+// such as a hold or an operation. Resources of wallets the principal may not access are reported
+// as notFound. The response is sent if access is denied.
+func (h *handler) authorizeOwner(c *gin.Context, id uuid.UUID, notFound *service.Error) bool {
+	p := h.principal(c)
+	if p.Owner == "" {
+		return true
+	}
+
+	w, err := h.walletService.Balance(c.Request.Context(), id)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		h.sendError(c, http.StatusInternalServerError, errInternal)
+		return false
+	}
+	if err != nil || !p.CanAccess(w.OwnerID) {
+		h.sendError(c, http.StatusNotFound, notFound)
+		return false
+	}
+	return true
+}
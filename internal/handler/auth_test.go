@@ -0,0 +1,526 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cmd/app/main.go/internal/auth"
+	dbmocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+	fakeKeys := dbmocks.NewMockStorage(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService, WithAuthenticator(auth.NewAPIKeys(fakeKeys)))
+	handler.Register()
+
+	readKey := "wk_read"
+	readOnly := model.APIKey{ID: uuid.New(), Name: "reporting", Scopes: []string{auth.ScopeWalletsRead}}
+	adminKey := "wk_admin"
+	admin := model.APIKey{ID: uuid.New(), Name: "backoffice", Scopes: []string{auth.ScopeAdmin}}
+
+	t.Run("TestAuth_NoKey", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/wallets/%s", uuid.New())
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnauthorized
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    service.ErrUnauthenticated.Code,
+			"message": "missing credentials",
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestAuth_NoKeyProblem", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/wallets/%s", uuid.New())
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", problemContentType)
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnauthorized
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+		if contentType := recoder.Header().Get("Content-Type"); contentType != problemContentType {
+			t.Errorf("Expected: %v, recieved: %v", problemContentType, contentType)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"type":     "urn:wallet:problem:UNAUTHENTICATED",
+			"title":    "Unauthorized",
+			"status":   float64(http.StatusUnauthorized),
+			"detail":   "missing credentials",
+			"instance": url,
+			"code":     service.ErrUnauthenticated.Code,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestAuth_UnknownKey", func(t *testing.T) {
+		fakeKeys.EXPECT().APIKeyByHash(gomock.Any(), auth.HashKey("wk_unknown")).Return(model.APIKey{}, pgx.ErrNoRows)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s", uuid.New())
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set(auth.APIKeyHeader, "wk_unknown")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnauthorized
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    service.ErrUnauthenticated.Code,
+			"message": "invalid credentials",
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestAuth_MalformedKey", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/wallets/%s", uuid.New())
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set(auth.APIKeyHeader, "not-a-key")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnauthorized
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestAuth_StorageErr", func(t *testing.T) {
+		fakeKeys.EXPECT().APIKeyByHash(gomock.Any(), auth.HashKey(readKey)).Return(model.APIKey{}, fmt.Errorf("db err"))
+
+		url := fmt.Sprintf("/api/v1/wallets/%s", uuid.New())
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set(auth.APIKeyHeader, readKey)
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusInternalServerError
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestAuth_ReadScope", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeKeys.EXPECT().APIKeyByHash(gomock.Any(), auth.HashKey(readKey)).Return(readOnly, nil)
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s", fakeUUID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set(auth.APIKeyHeader, readKey)
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestAuth_MissingWriteScope", func(t *testing.T) {
+		fakeKeys.EXPECT().APIKeyByHash(gomock.Any(), auth.HashKey(readKey)).Return(readOnly, nil)
+
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "WITHDRAW", "amount": 10}`, uuid.New()))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set(auth.APIKeyHeader, readKey)
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusForbidden
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    service.ErrForbidden.Code,
+			"message": "missing scope " + auth.ScopeWalletsWrite,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestAuth_MissingAdminScope", func(t *testing.T) {
+		fakeKeys.EXPECT().APIKeyByHash(gomock.Any(), auth.HashKey(readKey)).Return(readOnly, nil)
+
+		url := fmt.Sprintf("/api/v1/admin/wallets/%s/freeze", uuid.New())
+		body := []byte(`{"reason": "fraud report"}`)
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set(auth.APIKeyHeader, readKey)
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusForbidden
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestAuth_AdminGrantsAll", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeKeys.EXPECT().APIKeyByHash(gomock.Any(), auth.HashKey(adminKey)).Return(admin, nil)
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s", fakeUUID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set(auth.APIKeyHeader, adminKey)
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+}
+
+// principalAuth authenticates every request as the same principal.
+type principalAuth auth.Principal
+
+func (p principalAuth) Authenticate(ctx context.Context, r *http.Request) (auth.Principal, error) {
+	return auth.Principal(p), nil
+}
+
+func TestWalletOwnership(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	owner := "customer-1842"
+	other := "customer-7"
+	user := principalAuth{ID: owner, Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}, Owner: owner}
+
+	router := gin.Default()
+	handler := New(router, fakeService, WithAuthenticator(user))
+	handler.Register()
+
+	t.Run("TestWalletOwnership_BalanceOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_BalanceNotOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &other}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    service.ErrWalletNotFound.Code,
+			"message": "wallet not found",
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletOwnership_BalanceNoOwner", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_TransactionOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "WITHDRAW", "amount": 10}`, fakeUUID))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_TransactionNotOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &other}, nil)
+
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "WITHDRAW", "amount": 10}`, fakeUUID))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_HoldNotOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeID := uuid.New()
+		fakeService.EXPECT().Hold(gomock.Any(), fakeID).Return(model.Hold{ID: fakeID, UUID: fakeUUID}, nil)
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &other}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/holds/%s", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_CaptureNotOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeID := uuid.New()
+		fakeService.EXPECT().Hold(gomock.Any(), fakeID).Return(model.Hold{ID: fakeID, UUID: fakeUUID}, nil)
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &other}, nil)
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/holds/%s/capture", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_VoidOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeID := uuid.New()
+		fakeService.EXPECT().Hold(gomock.Any(), fakeID).Return(model.Hold{ID: fakeID, UUID: fakeUUID}, nil)
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+		fakeService.EXPECT().VoidHold(gomock.Any(), fakeID).Return(model.Hold{ID: fakeID, UUID: fakeUUID, Status: model.HoldVoided}, nil)
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/holds/%s/void", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_OperationNotOwned", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeID := uuid.New()
+		fakeService.EXPECT().Operation(gomock.Any(), fakeID).Return(model.Operation{ID: fakeID, UUID: fakeUUID}, nil)
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &other}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/transactions/%s", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_Reverse", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/transactions/%s/reverse", uuid.New()), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusForbidden
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_OtherOwnerWallets", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/owners/%s/wallets", other), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusForbidden
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletOwnership_CreateOwnWallet", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Create(gomock.Any(), dto.WalletCreateRequest{OwnerID: owner}).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallets", nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusCreated
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+}
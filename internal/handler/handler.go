@@ -1,16 +1,22 @@
 package handler
 
 import (
+	"cmd/app/main.go/internal/auth"
 	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/ratelimit"
 	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/internal/stream"
+	"cmd/app/main.go/pkg/money"
+	"context"
 	"errors"
-	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 )
 
 type Handler interface {
@@ -21,84 +27,260 @@ type handler struct {
 	router        *gin.Engine
 	walletService service.Wallet
 	validator     *validator.Validate
+
+	authenticators []auth.Authenticator
+	signatures     *auth.Signatures
+
+	limiter     ratelimit.Limiter
+	clientLimit ratelimit.Limit
+	walletLimit ratelimit.Limit
+
+	broker            *stream.Broker
+	streamHeartbeat   time.Duration
+	streamMaxDuration time.Duration
+
+	socket http.Handler
 }
 
-func New(r *gin.Engine, ws service.Wallet) Handler {
-	return &handler{
+func New(r *gin.Engine, ws service.Wallet, opts ...Option) Handler {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterStructValidation(validateTransactionRequest, dto.WalletTransactionRequest{})
+	h := &handler{
 		router:        r,
 		walletService: ws,
-		validator:     validator.New(validator.WithRequiredStructEnabled()),
+		validator:     v,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// validateTransactionRequest reports transfers whose destination is the source wallet itself
+// and amounts with more decimal places than the declared currency allows.
+// The nefield tag cannot be used here because it does not compare uuid.UUID arrays.
+func validateTransactionRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(dto.WalletTransactionRequest)
+	if req.Type == "TRANSFER" && req.ToUUID == req.UUID {
+		sl.ReportError(req.ToUUID, "ToUUID", "ToUUID", "nefield", "UUID")
+	}
+	if req.Currency != "" {
+		decimals := money.Decimals(req.Currency)
+		if req.Amount.Decimals() > decimals {
+			sl.ReportError(req.Amount, "Amount", "Amount", "precision", strconv.Itoa(decimals))
+		}
 	}
 }
 
 // Register configures HTTP routes for managing wallet resources.
+// Every route requires an authenticated principal with the scope it is registered with.
+// Routes of the signed group also require partners to sign their requests.
+// Every client is throttled once authenticated. The routes are described by the OpenAPI document
+// served at /api/v1/openapi.json, and requests are validated against it before reaching the handlers.
+// The document is written by hand, not derived from the routes, so it has to be updated along with them;
+// TestOpenAPI_Routes fails when they differ.
 func (h *handler) Register() {
-	v1 := h.router.Group("/api/v1")
-	v1.POST("/wallet", h.WalletTransaction)
-	v1.POST("/wallets", h.WalletCreate)
-	v1.GET("/wallets/:uuid", h.WalletBalance)
+	read := h.require(auth.ScopeWalletsRead)
+	write := h.require(auth.ScopeWalletsWrite)
+
+	h.router.GET("/api/v1/openapi.json", h.OpenAPI)
+
+	v1 := h.router.Group("/api/v1", h.requestInfo, h.authenticate, h.limitClient, h.validateContract)
+
+	signed := v1.Group("", h.verifySignature)
+	signed.POST("/wallet", write, h.WalletTransaction)
+
+	v1.POST("/wallets", write, h.WalletCreate)
+	v1.GET("/wallets/:uuid", read, h.WalletBalance)
+	v1.GET("/wallets/:uuid/transactions", read, h.WalletTransactions)
+	v1.GET("/wallets/:uuid/events", read, h.WalletEvents)
+	v1.GET("/ws", read, h.Socket)
+	v1.GET("/owners/:id/wallets", read, h.OwnerWallets)
+	v1.POST("/wallets/:uuid/holds", write, h.HoldCreate)
+	v1.GET("/holds/:id", read, h.Hold)
+	v1.POST("/holds/:id/capture", write, h.HoldCapture)
+	v1.POST("/holds/:id/void", write, h.HoldVoid)
+	v1.GET("/transactions/:id", read, h.Operation)
+	v1.POST("/transactions/:id/reverse", h.require(auth.ScopeAdmin), h.TransactionReverse)
+
+	admin := v1.Group("/admin", h.require(auth.ScopeAdmin))
+	admin.POST("/wallets/:uuid/freeze", h.WalletFreeze)
+	admin.POST("/wallets/:uuid/unfreeze", h.WalletUnfreeze)
+	admin.POST("/wallets/:uuid/close", h.WalletClose)
+	admin.GET("/wallets/:uuid/limits", h.WalletLimits)
+	admin.PUT("/wallets/:uuid/limits", h.WalletLimitsSet)
+	admin.GET("/audit", h.AuditLog)
+	admin.POST("/webhooks", h.WebhookCreate)
+	admin.GET("/webhooks", h.Webhooks)
+	admin.DELETE("/webhooks/:id", h.WebhookDelete)
+	admin.GET("/webhooks/:id/deliveries", h.WebhookDeliveries)
+	admin.POST("/deliveries/:id/redeliver", h.WebhookRedeliver)
 }
 
 // WalletTransaction processes incoming requests to perform financial transactions on wallets.
 // It first binds and validates the request payload, ensuring proper input structure.
 // Then, it delegates the actual transaction processing to the wallet service layer.
+// Principals restricted to an owner may only debit or deposit to the owner's wallets.
+// Requests carrying an Idempotency-Key header are processed at most once per key.
 // Upon completion, it either returns the result or an appropriate error code if something goes wrong.
 func (h *handler) WalletTransaction(c *gin.Context) {
 	req := dto.WalletTransactionRequest{}
-	c.ShouldBindJSON(&req)
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
 
-	err := h.validator.Struct(req)
+	err = h.validator.Struct(req)
 	if err != nil {
-		h.sendMsg(c, false, http.StatusBadRequest, fmt.Sprint("validation err: ", err))
+		h.invalid(c, req, err)
+		return
+	}
+
+	if !h.authorizeWallet(c, req.UUID) || !h.limitWallet(c, req.UUID) {
+		return
+	}
+
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key != "" {
+		h.idempotentTransaction(c, key, req)
 		return
 	}
 
 	res, err := h.walletService.Transaction(c.Request.Context(), req)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			h.sendMsg(c, false, http.StatusNotFound, "wallet not found")
-			return
-		}
-		h.sendMsg(c, false, http.StatusInternalServerError, "wallet service err")
+		h.fail(c, err, service.ErrWalletNotFound)
 		return
 	}
 	h.sendMsg(c, true, http.StatusOK, res)
 }
 
+// transaction performs the requested operation for idempotentTransaction and maps its outcome
+// to a response status and the v1 body stored under the idempotency key.
+func (h *handler) transaction(ctx context.Context, req dto.WalletTransactionRequest) (int, gin.H) {
+	res, err := h.walletService.Transaction(ctx, req)
+	if err != nil {
+		e := serviceError(err, service.ErrWalletNotFound)
+		return errorStatuses[e.Kind], errBody(e.Code, e.Message)
+	}
+	return http.StatusOK, msgBody(true, res)
+}
+
 // WalletCreate handles HTTP POST requests to create a new wallet.
+// The optional JSON body may carry the ISO 4217 currency of the wallet and the owner reference.
+// Wallets created by principals restricted to an owner belong to that owner.
 // It calls the wallet service to generate a unique identifier for the newly created wallet
-// and responds with this ID along with a success message. If an error occurs during the process,
+// and responds with the new wallet along with a success message. If an error occurs during the process,
 // it sends back an appropriate error response.
 func (h *handler) WalletCreate(c *gin.Context) {
-	uuid, err := h.walletService.Create(c.Request.Context())
+	req := dto.WalletCreateRequest{}
+	if c.Request.ContentLength != 0 {
+		err := c.ShouldBindJSON(&req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			h.invalid(c, req, err)
+			return
+		}
+	}
+
+	err := h.validator.Struct(req)
 	if err != nil {
-		h.sendMsg(c, false, http.StatusInternalServerError, fmt.Sprint(err))
+		h.invalid(c, req, err)
 		return
 	}
-	data := map[string]any{
-		"walletId": uuid,
+
+	p := h.principal(c)
+	if p.Owner != "" {
+		if req.OwnerID != "" && req.OwnerID != p.Owner {
+			h.sendError(c, http.StatusForbidden, service.ErrForbidden.WithMessage("cannot create wallets for another owner"))
+			return
+		}
+		req.OwnerID = p.Owner
 	}
-	h.sendMsg(c, true, http.StatusCreated, data)
+
+	res, err := h.walletService.Create(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusCreated, res)
 }
 
 // WalletBalance fetches the current balance of a wallet identified by its UUID.
 // It extracts the UUID from the request parameters, validates it, then queries the wallet service
 // to retrieve the corresponding balance. On successful execution, it returns the balance details.
 // In case of errors such as invalid UUID format or missing wallet entry, appropriate error responses are sent.
+// Wallets the principal may not access are reported as not found.
 func (h *handler) WalletBalance(c *gin.Context) {
 	uuidStr := c.Params.ByName("uuid")
 	uuid, err := uuid.Parse(uuidStr)
 	if err != nil {
-		h.sendMsg(c, false, http.StatusBadRequest, "incorrect wallet uuid")
+		h.invalidParam(c, "uuid", "incorrect wallet uuid")
 		return
 	}
 	res, err := h.walletService.Balance(c.Request.Context(), uuid)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			h.sendMsg(c, false, http.StatusNotFound, "wallet not found")
-			return
-		}
-		h.sendMsg(c, false, http.StatusInternalServerError, "wallet service err")
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	if !h.principal(c).CanAccess(res.OwnerID) {
+		h.sendError(c, http.StatusNotFound, service.ErrWalletNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// OwnerWallets lists the wallets of the owner identified by the id in the path along with their balances.
+func (h *handler) OwnerWallets(c *gin.Context) {
+	ownerID := c.Params.ByName("id")
+	if len(ownerID) > 255 {
+		h.invalidParam(c, "id", "incorrect owner id")
+		return
+	}
+	p := h.principal(c)
+	if p.Owner != "" && p.Owner != ownerID {
+		h.sendError(c, http.StatusForbidden, service.ErrForbidden.WithMessage("cannot list wallets of another owner"))
+		return
+	}
+
+	res, err := h.walletService.OwnerWallets(c.Request.Context(), ownerID)
+	if err != nil {
+		h.sendError(c, http.StatusInternalServerError, errInternal)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// WalletTransactions returns the operation history of a wallet, newest first.
+// It parses the wallet UUID and the query parameters (cursor, limit, operationType, from, to),
+// validates them and asks the wallet service for the requested page.
+// The response contains the operations and, when more are available, a cursor for the next page.
+func (h *handler) WalletTransactions(c *gin.Context) {
+	req := dto.TransactionHistoryRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	req.UUID, err = uuid.Parse(c.Params.ByName("uuid"))
+	if err != nil {
+		h.invalidParam(c, "uuid", "incorrect wallet uuid")
+		return
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	if !h.authorizeWallet(c, req.UUID) {
+		return
+	}
+
+	res, err := h.walletService.Transactions(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
 		return
 	}
 	h.sendMsg(c, true, http.StatusOK, res)
@@ -106,8 +288,27 @@ func (h *handler) WalletBalance(c *gin.Context) {
 
 // sendMsg sends a JSON response containing a success indicator and additional message or data.
 func (h *handler) sendMsg(c *gin.Context, success bool, status int, message any) {
-	c.JSON(status, gin.H{
+	c.JSON(status, msgBody(success, message))
+}
+
+// sendErr sends a failed JSON response carrying a machine-readable error code next to the message.
+func (h *handler) sendErr(c *gin.Context, status int, code string, message string) {
+	c.JSON(status, errBody(code, message))
+}
+
+// msgBody builds the response body sent by sendMsg.
+func msgBody(success bool, message any) gin.H {
+	return gin.H{
 		"success": success,
 		"message": message,
-	})
+	}
+}
+
+// errBody builds the response body sent by sendErr.
+func errBody(code string, message string) gin.H {
+	return gin.H{
+		"success": false,
+		"code":    code,
+		"message": message,
+	}
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,9 +10,12 @@ import (
 	"reflect"
 	"testing"
 
+	"cmd/app/main.go/internal/auth"
 	"cmd/app/main.go/internal/dto"
 	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
 	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -31,7 +35,7 @@ func TestWalletCreate(t *testing.T) {
 
 	t.Run("TestWalletCreate_Success", func(t *testing.T) {
 		fakeUUID := uuid.New()
-		fakeService.EXPECT().Create(gomock.Any()).Return(fakeUUID, nil)
+		fakeService.EXPECT().Create(gomock.Any(), dto.WalletCreateRequest{}).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
 
 		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallets", nil)
 		if err != nil {
@@ -66,7 +70,7 @@ func TestWalletCreate(t *testing.T) {
 	t.Run("TestWalletCreate_ServiceErr", func(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeErr := fmt.Errorf("db err")
-		fakeService.EXPECT().Create(gomock.Any()).Return(fakeUUID, fakeErr)
+		fakeService.EXPECT().Create(gomock.Any(), dto.WalletCreateRequest{}).Return(model.Wallet{UUID: fakeUUID}, fakeErr)
 
 		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallets", nil)
 
@@ -83,8 +87,9 @@ func TestWalletCreate(t *testing.T) {
 
 		resp := make(map[string]any)
 		correctResp := map[string]any{
-			"message": fmt.Sprint(fakeErr),
+			"message": "wallet service err",
 			"success": false,
+			"code":    service.CodeInternal,
 		}
 
 		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
@@ -98,6 +103,174 @@ func TestWalletCreate(t *testing.T) {
 			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
 		}
 	})
+
+	t.Run("TestWalletCreate_Currency", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletCreateRequest{
+			Currency: "JPY",
+		}
+		fakeService.EXPECT().Create(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, Currency: "JPY"}, nil)
+
+		body, err := json.Marshal(fakeReq)
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallets", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		if recoder.Code != http.StatusCreated {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", http.StatusCreated, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		message := resp["message"].(map[string]any)
+		value, ok := message["currency"]
+		if !ok || value != "JPY" {
+			t.Errorf("response body incorrect. Expected currency: %v, received: %v", "JPY", value)
+		}
+	})
+
+	t.Run("TestWalletCreate_BadCurrency", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallets", bytes.NewBufferString(`{"currency":"XYZ"}`))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("validation err: Key: 'WalletCreateRequest.Currency' Error:Field validation for 'Currency' failed on the 'iso4217' tag"),
+			"success": false,
+			"code":    service.CodeValidationFailed,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletCreate_OwnerExists", func(t *testing.T) {
+		fakeReq := dto.WalletCreateRequest{
+			Currency: "USD",
+			OwnerID:  "user-42",
+		}
+		fakeService.EXPECT().Create(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrWalletExists)
+
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallets", bytes.NewBufferString(`{"currency":"USD","ownerId":"user-42"}`))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"code":    "WALLET_EXISTS",
+			"message": "owner already has a wallet in the currency",
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
+
+func TestOwnerWallets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestOwnerWallets_Success", func(t *testing.T) {
+		owner := "user-42"
+		fakeWallets := []model.Wallet{
+			{UUID: uuid.New(), Balance: money.MustParse("10"), Currency: "USD", OwnerID: &owner},
+			{UUID: uuid.New(), Balance: money.MustParse("5"), Currency: "EUR", OwnerID: &owner},
+		}
+		fakeService.EXPECT().OwnerWallets(gomock.Any(), owner).Return(fakeWallets, nil)
+
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/owners/user-42/wallets", nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		wallets, ok := resp["message"].([]any)
+		if !ok || len(wallets) != len(fakeWallets) {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", fakeWallets, resp["message"])
+		}
+	})
+
+	t.Run("TestOwnerWallets_ServiceErr", func(t *testing.T) {
+		fakeService.EXPECT().OwnerWallets(gomock.Any(), "user-43").Return(nil, fmt.Errorf("db err"))
+
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/owners/user-43/wallets", nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusInternalServerError
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
 }
 
 func TestWalletBalance(t *testing.T) {
@@ -114,7 +287,7 @@ func TestWalletBalance(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 0,
+			Balance: money.MustParse("0"),
 		}
 		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, nil)
 
@@ -146,7 +319,7 @@ func TestWalletBalance(t *testing.T) {
 		}
 
 		respBalance, ok := message["balance"]
-		if !ok || respBalance != fakeWallet.Balance {
+		if !ok || fmt.Sprint(respBalance) != fakeWallet.Balance.String() {
 			t.Errorf("response body incorrect. Expected walletId: %v, received: %v", respBalance, fakeWallet.Balance)
 		}
 	})
@@ -168,8 +341,9 @@ func TestWalletBalance(t *testing.T) {
 		resp := make(map[string]any)
 
 		correctResp := map[string]any{
-			"message": fmt.Sprint("incorrect wallet uuid"),
+			"message": fmt.Sprint("validation err: uuid must be a UUID"),
 			"success": false,
+			"code":    service.CodeValidationFailed,
 		}
 
 		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
@@ -188,7 +362,7 @@ func TestWalletBalance(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 0,
+			Balance: money.MustParse("0"),
 		}
 		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, pgx.ErrNoRows)
 
@@ -211,6 +385,7 @@ func TestWalletBalance(t *testing.T) {
 		correctResp := map[string]any{
 			"message": fmt.Sprint("wallet not found"),
 			"success": false,
+			"code":    service.ErrWalletNotFound.Code,
 		}
 
 		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
@@ -229,7 +404,7 @@ func TestWalletBalance(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 0,
+			Balance: money.MustParse("0"),
 		}
 		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, fmt.Errorf("db random err"))
 
@@ -252,6 +427,7 @@ func TestWalletBalance(t *testing.T) {
 		correctResp := map[string]any{
 			"message": fmt.Sprint("wallet service err"),
 			"success": false,
+			"code":    service.CodeInternal,
 		}
 
 		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
@@ -281,13 +457,13 @@ func TestWalletTransaction(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 
 		fakeReq := dto.WalletTransactionRequest{
 			UUID:   fakeUUID,
 			Type:   "DEPOSIT",
-			Amount: 100,
+			Amount: money.MustParse("100"),
 		}
 
 		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(fakeWallet, nil)
@@ -326,7 +502,7 @@ func TestWalletTransaction(t *testing.T) {
 		}
 
 		respBalance, ok := message["balance"]
-		if !ok || respBalance != fakeWallet.Balance {
+		if !ok || fmt.Sprint(respBalance) != fakeWallet.Balance.String() {
 			t.Errorf("response body incorrect. Expected walletId: %v, received: %v", respBalance, fakeWallet.Balance)
 		}
 	})
@@ -335,7 +511,7 @@ func TestWalletTransaction(t *testing.T) {
 
 		fakeReq := dto.WalletTransactionRequest{
 			Type:   "DEPOSIT",
-			Amount: 100,
+			Amount: money.MustParse("100"),
 		}
 
 		url := fmt.Sprintf("/api/v1/wallet")
@@ -363,6 +539,131 @@ func TestWalletTransaction(t *testing.T) {
 		correctResp := map[string]any{
 			"message": fmt.Sprint("validation err: Key: 'WalletTransactionRequest.UUID' Error:Field validation for 'UUID' failed on the 'required' tag"),
 			"success": false,
+			"code":    service.CodeValidationFailed,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransaction_TransferSuccess", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeWallet := model.Wallet{
+			UUID:    fakeUUID,
+			Balance: money.MustParse("0"),
+		}
+
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse("100"),
+			ToUUID: uuid.New(),
+		}
+
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(fakeWallet, nil)
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletTransaction_TransferSameWallet", func(t *testing.T) {
+		fakeUUID := uuid.New()
+
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse("100"),
+			ToUUID: fakeUUID,
+		}
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("validation err: Key: 'WalletTransactionRequest.ToUUID' Error:Field validation for 'ToUUID' failed on the 'nefield' tag"),
+			"success": false,
+			"code":    service.CodeValidationFailed,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransaction_ExcessPrecision", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body := fmt.Sprintf(`{"valletId":"%s","operationType":"DEPOSIT","amount":10.001,"currency":"USD"}`, uuid.New())
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("validation err: Key: 'WalletTransactionRequest.Amount' Error:Field validation for 'Amount' failed on the 'precision' tag"),
+			"success": false,
+			"code":    service.CodeValidationFailed,
 		}
 
 		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
@@ -381,13 +682,13 @@ func TestWalletTransaction(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 
 		fakeReq := dto.WalletTransactionRequest{
 			UUID:   fakeUUID,
 			Type:   "DEPOSIT",
-			Amount: 100,
+			Amount: money.MustParse("100"),
 		}
 
 		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(fakeWallet, pgx.ErrNoRows)
@@ -417,6 +718,7 @@ func TestWalletTransaction(t *testing.T) {
 		correctResp := map[string]any{
 			"message": fmt.Sprint("wallet not found"),
 			"success": false,
+			"code":    service.ErrWalletNotFound.Code,
 		}
 
 		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
@@ -431,20 +733,16 @@ func TestWalletTransaction(t *testing.T) {
 		}
 	})
 
-	t.Run("TestWalletTransaction_InternalErr", func(t *testing.T) {
+	t.Run("TestWalletTransaction_InsufficientFunds", func(t *testing.T) {
 		fakeUUID := uuid.New()
-		fakeWallet := model.Wallet{
-			UUID:    fakeUUID,
-			Balance: 100,
-		}
 
 		fakeReq := dto.WalletTransactionRequest{
 			UUID:   fakeUUID,
-			Type:   "DEPOSIT",
-			Amount: 100,
+			Type:   "WITHDRAW",
+			Amount: money.MustParse("100"),
 		}
 
-		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(fakeWallet, fmt.Errorf("random db err"))
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrInsufficientFunds)
 
 		url := fmt.Sprintf("/api/v1/wallet")
 
@@ -461,7 +759,7 @@ func TestWalletTransaction(t *testing.T) {
 
 		recoder := httptest.NewRecorder()
 		router.ServeHTTP(recoder, req)
-		correctCode := http.StatusInternalServerError
+		correctCode := http.StatusConflict
 		if recoder.Code != correctCode {
 			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
 		}
@@ -469,7 +767,8 @@ func TestWalletTransaction(t *testing.T) {
 		resp := make(map[string]any)
 
 		correctResp := map[string]any{
-			"message": fmt.Sprint("wallet service err"),
+			"code":    "INSUFFICIENT_FUNDS",
+			"message": fmt.Sprint("insufficient funds"),
 			"success": false,
 		}
 
@@ -484,4 +783,617 @@ func TestWalletTransaction(t *testing.T) {
 			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
 		}
 	})
+
+	t.Run("TestWalletTransaction_CurrencyMismatch", func(t *testing.T) {
+		fakeUUID := uuid.New()
+
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:     fakeUUID,
+			Type:     "DEPOSIT",
+			Amount:   money.MustParse("100"),
+			Currency: "EUR",
+		}
+
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrCurrencyMismatch)
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnprocessableEntity
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"code":    "CURRENCY_MISMATCH",
+			"message": fmt.Sprint("currency mismatch"),
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransaction_RateUnavailable", func(t *testing.T) {
+		fakeUUID := uuid.New()
+
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse("100"),
+			ToUUID: uuid.New(),
+		}
+
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrRateUnavailable)
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnprocessableEntity
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"code":    "RATE_UNAVAILABLE",
+			"message": fmt.Sprint("exchange rate unavailable"),
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransaction_InternalErr", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeWallet := model.Wallet{
+			UUID:    fakeUUID,
+			Balance: money.MustParse("100"),
+		}
+
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "DEPOSIT",
+			Amount: money.MustParse("100"),
+		}
+
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(fakeWallet, fmt.Errorf("random db err"))
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusInternalServerError
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("wallet service err"),
+			"success": false,
+			"code":    service.CodeInternal,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
+
+func TestWalletTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestWalletTransactions_Success", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID:  fakeUUID,
+			Limit: 1,
+			Type:  "DEPOSIT",
+		}
+		fakeRes := dto.TransactionHistoryResponse{
+			Transactions: []model.Transaction{
+				{
+					ID:      2,
+					UUID:    fakeUUID,
+					Type:    "DEPOSIT",
+					Amount:  money.MustParse("100"),
+					Balance: money.MustParse("200"),
+				},
+			},
+			NextCursor: "Mg",
+		}
+		fakeService.EXPECT().Transactions(gomock.Any(), fakeReq).Return(fakeRes, nil)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s/transactions?limit=1&operationType=DEPOSIT", fakeUUID)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		message := resp["message"].(map[string]any)
+		respCursor, ok := message["nextCursor"]
+		if !ok || respCursor != fakeRes.NextCursor {
+			t.Errorf("response body incorrect. Expected nextCursor: %v, received: %v", fakeRes.NextCursor, respCursor)
+		}
+
+		respTransactions, ok := message["transactions"].([]any)
+		if !ok || len(respTransactions) != len(fakeRes.Transactions) {
+			t.Errorf("response body incorrect. Expected transactions: %v, received: %v", fakeRes.Transactions, message["transactions"])
+		}
+	})
+
+	t.Run("TestWalletTransactions_BadUUID", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/wallets/%s/transactions", "123")
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("validation err: uuid must be a UUID"),
+			"success": false,
+			"code":    service.CodeValidationFailed,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransactions_ValErr", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		url := fmt.Sprintf("/api/v1/wallets/%s/transactions?limit=1000", fakeUUID)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("validation err: limit must be at most 100"),
+			"success": false,
+			"code":    service.CodeValidationFailed,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransactions_InvalidCursor", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID:   fakeUUID,
+			Cursor: "bad",
+		}
+		fakeService.EXPECT().Transactions(gomock.Any(), fakeReq).Return(dto.TransactionHistoryResponse{}, service.ErrInvalidCursor)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s/transactions?cursor=bad", fakeUUID)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("invalid cursor"),
+			"success": false,
+			"code":    service.ErrInvalidCursor.Code,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransactions_WalletNotFound", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID: fakeUUID,
+		}
+		fakeService.EXPECT().Transactions(gomock.Any(), fakeReq).Return(dto.TransactionHistoryResponse{}, pgx.ErrNoRows)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s/transactions", fakeUUID)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("wallet not found"),
+			"success": false,
+			"code":    service.ErrWalletNotFound.Code,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletTransactions_InternalErr", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID: fakeUUID,
+		}
+		fakeService.EXPECT().Transactions(gomock.Any(), fakeReq).Return(dto.TransactionHistoryResponse{}, fmt.Errorf("db random err"))
+
+		url := fmt.Sprintf("/api/v1/wallets/%s/transactions", fakeUUID)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusInternalServerError
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"message": fmt.Sprint("wallet service err"),
+			"success": false,
+			"code":    service.CodeInternal,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
+
+func TestWalletTransactionIdempotent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	runIdempotent := func(ctx context.Context, key string, fingerprint string, fn service.IdempotentFunc) (model.IdempotencyRecord, error) {
+		status, body, err := fn(ctx)
+		return model.IdempotencyRecord{Key: key, Fingerprint: fingerprint, Status: status, Body: body}, err
+	}
+
+	t.Run("TestWalletTransactionIdempotent_FirstRequest", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeWallet := model.Wallet{
+			UUID:    fakeUUID,
+			Balance: money.MustParse("100"),
+		}
+
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "DEPOSIT",
+			Amount: money.MustParse("100"),
+		}
+
+		fakeService.EXPECT().Idempotent(gomock.Any(), "key-1", gomock.Any(), gomock.Any()).DoAndReturn(runIdempotent)
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(fakeWallet, nil)
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Idempotency-Key", "key-1")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+		if recoder.Header().Get("Idempotent-Replayed") != "" {
+			t.Error("first response must not be marked as replayed")
+		}
+
+		resp := make(map[string]any)
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		message := resp["message"].(map[string]any)
+		respWalletId, ok := message["walletId"]
+		if !ok || respWalletId != fakeWallet.UUID.String() {
+			t.Errorf("response body incorrect. Expected walletId: %v, received: %v", fakeWallet.UUID, respWalletId)
+		}
+	})
+
+	t.Run("TestWalletTransactionIdempotent_Replay", func(t *testing.T) {
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   uuid.New(),
+			Type:   "DEPOSIT",
+			Amount: money.MustParse("100"),
+		}
+		fakeRec := model.IdempotencyRecord{
+			Key:      "key-2",
+			Status:   http.StatusConflict,
+			Body:     []byte(`{"code":"INSUFFICIENT_FUNDS","message":"insufficient funds","success":false}`),
+			Replayed: true,
+		}
+
+		fakeService.EXPECT().Idempotent(gomock.Any(), "key-2", gomock.Any(), gomock.Any()).Return(fakeRec, nil)
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Idempotency-Key", "key-2")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+		if recoder.Header().Get("Idempotent-Replayed") != "true" {
+			t.Error("replayed response must be marked as replayed")
+		}
+		if recoder.Body.String() != string(fakeRec.Body) {
+			t.Errorf("response body incorrect. Expected: %s, received: %s", fakeRec.Body, recoder.Body.String())
+		}
+	})
+
+	t.Run("TestWalletTransactionIdempotent_KeyReused", func(t *testing.T) {
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   uuid.New(),
+			Type:   "DEPOSIT",
+			Amount: money.MustParse("100"),
+		}
+
+		fakeService.EXPECT().Idempotent(gomock.Any(), "key-3", gomock.Any(), gomock.Any()).Return(model.IdempotencyRecord{}, service.ErrIdempotencyKeyReused)
+
+		url := fmt.Sprintf("/api/v1/wallet")
+
+		body, err := json.Marshal(fakeReq)
+
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Idempotency-Key", "key-3")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnprocessableEntity
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+
+		correctResp := map[string]any{
+			"code":    "IDEMPOTENCY_KEY_REUSED",
+			"message": fmt.Sprint("idempotency key reused with a different payload"),
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
+
+func TestWalletTransactionIdempotentScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	client := principalAuth{ID: "acme", Scopes: []string{auth.ScopeWalletsWrite}}
+
+	router := gin.Default()
+	handler := New(router, fakeService, WithAuthenticator(client))
+	handler.Register()
+
+	fakeUUID := uuid.New()
+	fakeRec := model.IdempotencyRecord{Status: http.StatusOK, Body: []byte(`{"success":true}`), Replayed: true}
+	fakeService.EXPECT().Idempotent(gomock.Any(), `"acme":key-1`, gomock.Any(), gomock.Any()).Return(fakeRec, nil)
+
+	body := []byte(fmt.Sprintf(`{"walletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, fakeUUID))
+	req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+	if err != nil {
+		t.Error("new request err: ", err)
+	}
+	req.Header.Set("Idempotency-Key", "key-1")
+
+	recoder := httptest.NewRecorder()
+	router.ServeHTTP(recoder, req)
+	correctCode := http.StatusOK
+	if recoder.Code != correctCode {
+		t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+	}
 }
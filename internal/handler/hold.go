@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/service"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HoldCreate reserves funds on the wallet identified by the UUID in the path.
+// The JSON body carries the amount, an optional currency and an optional TTL in seconds.
+// It responds with the created hold, or 409 if the available balance does not cover the amount.
+func (h *handler) HoldCreate(c *gin.Context) {
+	req := dto.HoldCreateRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	req.UUID, err = uuid.Parse(c.Params.ByName("uuid"))
+	if err != nil {
+		h.invalidParam(c, "uuid", "incorrect wallet uuid")
+		return
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	if !h.authorizeWallet(c, req.UUID) || !h.limitWallet(c, req.UUID) {
+		return
+	}
+
+	res, err := h.walletService.CreateHold(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusCreated, res)
+}
+
+// Hold returns the hold identified by the id in the path. Holds on wallets of other owners are reported as not found.
+func (h *handler) Hold(c *gin.Context) {
+	id, err := uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect hold id")
+		return
+	}
+
+	res, err := h.walletService.Hold(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, service.ErrHoldNotFound)
+		return
+	}
+	if !h.authorizeOwner(c, res.UUID, service.ErrHoldNotFound) {
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// HoldCapture takes held funds from the wallet. The optional JSON body may carry
+// the amount to capture, otherwise the whole held amount is captured.
+// The rest of the hold is released in both cases.
+func (h *handler) HoldCapture(c *gin.Context) {
+	req := dto.HoldCaptureRequest{}
+	if c.Request.ContentLength != 0 {
+		err := c.ShouldBindJSON(&req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			h.invalid(c, req, err)
+			return
+		}
+	}
+
+	var err error
+	req.ID, err = uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect hold id")
+		return
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	if !h.authorizeHold(c, req.ID) {
+		return
+	}
+
+	res, err := h.walletService.CaptureHold(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrHoldNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// HoldVoid releases a hold without taking anything from the wallet.
+func (h *handler) HoldVoid(c *gin.Context) {
+	id, err := uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect hold id")
+		return
+	}
+
+	if !h.authorizeHold(c, id) {
+		return
+	}
+
+	res, err := h.walletService.VoidHold(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, service.ErrHoldNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// authorizeHold reports whether the principal of the request may act on the hold.
+// Holds on wallets of other owners are reported as not found. The response is sent if access is denied.
+func (h *handler) authorizeHold(c *gin.Context, id uuid.UUID) bool {
+	if h.principal(c).Owner == "" {
+		return true
+	}
+	hold, err := h.walletService.Hold(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, service.ErrHoldNotFound)
+		return false
+	}
+	return h.authorizeOwner(c, hold.UUID, service.ErrHoldNotFound)
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestHoldCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestHoldCreate_Success", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.HoldCreateRequest{
+			UUID:   fakeUUID,
+			Amount: money.MustParse("10"),
+			TTL:    60,
+		}
+		fakeHold := model.Hold{ID: uuid.New(), UUID: fakeUUID, Amount: fakeReq.Amount, Status: model.HoldActive}
+		fakeService.EXPECT().CreateHold(gomock.Any(), fakeReq).Return(fakeHold, nil)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s/holds", fakeUUID)
+		body := []byte(`{"amount": 10, "ttlSeconds": 60}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		if recoder.Code != http.StatusCreated {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", http.StatusCreated, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		message := resp["message"].(map[string]any)
+		value, ok := message["holdId"]
+		if !ok || value != fakeHold.ID.String() {
+			t.Errorf("response body incorrect. Expected holdId: %v, received: %v", fakeHold.ID, value)
+		}
+	})
+
+	t.Run("TestHoldCreate_BadUUID", func(t *testing.T) {
+		body := []byte(`{"amount": 10}`)
+
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallets/123/holds", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestHoldCreate_InsufficientFunds", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.HoldCreateRequest{
+			UUID:   fakeUUID,
+			Amount: money.MustParse("10"),
+		}
+		fakeService.EXPECT().CreateHold(gomock.Any(), fakeReq).Return(model.Hold{}, service.ErrInsufficientFunds)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s/holds", fakeUUID)
+		body := []byte(`{"amount": 10}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"code":    "INSUFFICIENT_FUNDS",
+			"message": "insufficient funds",
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
+
+func TestHoldCapture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestHoldCapture_Full", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeHold := model.Hold{ID: fakeID, Amount: money.MustParse("10"), Captured: money.MustParse("10"), Status: model.HoldCaptured}
+		fakeService.EXPECT().CaptureHold(gomock.Any(), dto.HoldCaptureRequest{ID: fakeID}).Return(fakeHold, nil)
+
+		url := fmt.Sprintf("/api/v1/holds/%s/capture", fakeID)
+
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		message := resp["message"].(map[string]any)
+		value, ok := message["status"]
+		if !ok || value != model.HoldCaptured {
+			t.Errorf("response body incorrect. Expected status: %v, received: %v", model.HoldCaptured, value)
+		}
+	})
+
+	t.Run("TestHoldCapture_Exceeds", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeReq := dto.HoldCaptureRequest{ID: fakeID, Amount: money.MustParse("20")}
+		fakeService.EXPECT().CaptureHold(gomock.Any(), fakeReq).Return(model.Hold{}, service.ErrCaptureExceedsHold)
+
+		url := fmt.Sprintf("/api/v1/holds/%s/capture", fakeID)
+		body := []byte(`{"amount": "20"}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusUnprocessableEntity
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"code":    "CAPTURE_EXCEEDS_HOLD",
+			"message": "capture amount exceeds the held amount",
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
+
+func TestHoldVoid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestHoldVoid_NotActive", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeService.EXPECT().VoidHold(gomock.Any(), fakeID).Return(model.Hold{}, service.ErrHoldNotActive)
+
+		url := fmt.Sprintf("/api/v1/holds/%s/void", fakeID)
+
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"code":    "HOLD_NOT_ACTIVE",
+			"message": "hold is not active",
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+}
+
+func TestHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestHold_NotFound", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeService.EXPECT().Hold(gomock.Any(), fakeID).Return(model.Hold{}, pgx.ErrNoRows)
+
+		url := fmt.Sprintf("/api/v1/holds/%s", fakeID)
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+}
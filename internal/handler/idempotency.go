@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/pkg/money"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	idempotencyKeyHeader       = "Idempotency-Key"
+	idempotentReplayedHeader   = "Idempotent-Replayed"
+	idempotencyKeyMaxLength    = 255
+	idempotencyJSONContentType = "application/json; charset=utf-8"
+)
+
+var errNotStored = errors.New("response is not stored")
+
+// idempotentTransaction performs the transaction at most once per idempotency key.
+// The first outcome is stored by the wallet service together with the operation and
+// replayed for repeated requests with the same payload. Server errors are not stored,
+// so that clients can retry them. Reusing the key with a different payload yields 422.
+func (h *handler) idempotentTransaction(c *gin.Context, key string, req dto.WalletTransactionRequest) {
+	if len(key) > idempotencyKeyMaxLength {
+		h.invalidParam(c, "Idempotency-Key", "idempotency key is too long")
+		return
+	}
+
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		h.sendError(c, http.StatusInternalServerError, errInternal)
+		return
+	}
+
+	rec, err := h.walletService.Idempotent(c.Request.Context(), h.idempotencyScope(c, key), fingerprint, func(ctx context.Context) (int, []byte, error) {
+		status, body := h.transaction(ctx, req)
+		if status >= http.StatusInternalServerError {
+			return status, nil, errNotStored
+		}
+		data, err := json.Marshal(body)
+		return status, data, err
+	})
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+
+	if rec.Replayed {
+		c.Header(idempotentReplayedHeader, "true")
+	}
+	if rec.Status >= http.StatusBadRequest && wantsProblem(c) {
+		h.sendError(c, rec.Status, storedError(rec.Status, rec.Body))
+		return
+	}
+	c.Data(rec.Status, idempotencyJSONContentType, rec.Body)
+}
+
+// idempotencyScope namespaces the idempotency key with the principal of the request, so that clients
+// can neither replay nor block each other's keys. The principal is quoted to keep the namespace unambiguous.
+// Keys are used as they are when authentication is disabled.
+func (h *handler) idempotencyScope(c *gin.Context, key string) string {
+	p := h.principal(c)
+	if p.ID == "" {
+		return key
+	}
+	return strconv.Quote(p.ID) + ":" + key
+}
+
+// storedError recovers the error of a failed response stored under an idempotency key,
+// which keeps the v1 envelope. Unknown wallets were stored without a code before every
+// failure carried one.
+func storedError(status int, body []byte) *service.Error {
+	e := &service.Error{}
+	_ = json.Unmarshal(body, e)
+	if e.Code == "" && status == http.StatusNotFound {
+		e.Code = service.ErrWalletNotFound.Code
+	}
+	return e
+}
+
+// fingerprintRequest is the encoding of transaction requests fingerprinted for idempotency keys.
+// It keeps the wallet under valletId, so that keys stored before walletId was accepted still match.
+type fingerprintRequest struct {
+	UUID     uuid.UUID    `json:"valletId"`
+	Type     string       `json:"operationType"`
+	Amount   money.Amount `json:"amount"`
+	ToUUID   uuid.UUID    `json:"toWalletId"`
+	Currency string       `json:"currency,omitempty"`
+}
+
+// requestFingerprint identifies the payload of a transaction request.
+func requestFingerprint(req dto.WalletTransactionRequest) (string, error) {
+	data, err := json.Marshal(fingerprintRequest(req))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
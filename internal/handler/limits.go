@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WalletLimits returns the spending limits of the wallet identified by the UUID in the path.
+func (h *handler) WalletLimits(c *gin.Context) {
+	id, err := uuid.Parse(c.Params.ByName("uuid"))
+	if err != nil {
+		h.invalidParam(c, "uuid", "incorrect wallet uuid")
+		return
+	}
+
+	res, err := h.walletService.Limits(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// WalletLimitsSet replaces the spending limits of the wallet identified by the UUID in the path.
+// Limits missing from the JSON body or set to null are removed. It responds with the new limits.
+func (h *handler) WalletLimitsSet(c *gin.Context) {
+	req := dto.WalletLimitsRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	req.UUID, err = uuid.Parse(c.Params.ByName("uuid"))
+	if err != nil {
+		h.invalidParam(c, "uuid", "incorrect wallet uuid")
+		return
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	res, err := h.walletService.SetLimits(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
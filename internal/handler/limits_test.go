@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestWalletLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestWalletLimits_Set", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		maxWithdrawal := money.MustParse("200")
+		monthly := money.MustParse("5000")
+		fakeReq := dto.WalletLimitsRequest{UUID: fakeUUID, MaxWithdrawal: &maxWithdrawal, MonthlyDebit: &monthly}
+		fakeService.EXPECT().SetLimits(gomock.Any(), fakeReq).Return(model.WalletLimits{
+			UUID:          fakeUUID,
+			Currency:      "USD",
+			MaxWithdrawal: &maxWithdrawal,
+			MonthlyDebit:  &monthly,
+		}, nil)
+
+		url := fmt.Sprintf("/api/v1/admin/wallets/%s/limits", fakeUUID)
+		body := []byte(`{"maxWithdrawal": 200, "monthlyDebit": "5000"}`)
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": true,
+			"message": map[string]any{
+				"walletId":      fakeUUID.String(),
+				"currency":      "USD",
+				"maxWithdrawal": float64(200),
+				"dailyDebit":    nil,
+				"monthlyDebit":  float64(5000),
+				"maxBalance":    nil,
+			},
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletLimits_SetNotPositive", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/admin/wallets/%s/limits", uuid.New())
+		body := []byte(`{"dailyDebit": 0}`)
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletLimits_GetNotFound", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Limits(gomock.Any(), fakeUUID).Return(model.WalletLimits{}, pgx.ErrNoRows)
+
+		url := fmt.Sprintf("/api/v1/admin/wallets/%s/limits", fakeUUID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletLimits_TransactionExceeds", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("300")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, &service.LimitError{Wallet: fakeUUID, Limit: model.LimitMaxWithdrawal})
+
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "WITHDRAW", "amount": 300}`, fakeUUID))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    "MAX_WITHDRAWAL_EXCEEDED",
+			"message": fmt.Sprintf("amount exceeds the maximum single withdrawal of wallet %s", fakeUUID),
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"cmd/app/main.go/api"
+	"cmd/app/main.go/internal/openapi"
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRequestBody limits the size of request bodies, which are read whole before validation.
+const maxRequestBody = 1 << 20
+
+// contract is the OpenAPI document served by OpenAPI, which requests are validated against.
+var contract = openapi.MustLoad(api.OpenAPI)
+
+// OpenAPI serves the hand-written OpenAPI 3 document describing the routes of Register.
+// It is public, so that client code generators can read it before clients are issued credentials.
+func (h *handler) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", api.OpenAPI)
+}
+
+// validateContract rejects with 400 Bad Request the requests whose path or query parameters or JSON body
+// do not match the operation of their route in the OpenAPI document, so that the document served to
+// clients is the one enforced. The handlers go on to validate what the document does not express,
+// such as the decimal places allowed by a currency. The body is restored for the handlers to bind it.
+func (h *handler) validateContract(c *gin.Context) {
+	var body []byte
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBody))
+		if err != nil {
+			h.sendError(c, http.StatusBadRequest, service.ErrInvalidBody)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	params := make(map[string]string, len(c.Params))
+	for _, p := range c.Params {
+		params[p.Key] = p.Value
+	}
+	err := contract.ValidateRequest(openapi.Request{
+		Method: c.Request.Method,
+		Path:   openAPIPath(c.FullPath()),
+		Params: params,
+		Query:  c.Request.URL.Query(),
+		Body:   body,
+	})
+	var vs openapi.Violations
+	if errors.As(err, &vs) {
+		h.violations(c, vs)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// violationCodes name the schema keywords failed by fields like the validation rules of the handlers,
+// so that clients get the same code for a field whichever check rejects it.
+var violationCodes = map[string]string{
+	"enum":             "oneof",
+	"minimum":          "min",
+	"exclusiveMinimum": "gt",
+	"maximum":          "max",
+	"minLength":        "min",
+	"maxLength":        "max",
+	"minItems":         "min",
+}
+
+// violations sends 400 Bad Request for a request breaking the OpenAPI document, like invalid does
+// for requests failing binding or validation.
+func (h *handler) violations(c *gin.Context, vs openapi.Violations) {
+	if !wantsProblem(c) {
+		h.sendErr(c, http.StatusBadRequest, service.CodeValidationFailed, "validation err: "+vs.Error())
+		return
+	}
+	fields := make([]service.FieldError, 0, len(vs))
+	for _, v := range vs {
+		code, ok := violationCodes[v.Rule]
+		if !ok {
+			code = v.Rule
+		}
+		fields = append(fields, service.FieldError{Field: v.Field, Code: code, Message: v.Message})
+	}
+	h.sendError(c, http.StatusBadRequest, service.NewValidationError(fields))
+}
+
+// openAPIPath turns the path of a route, such as /api/v1/wallets/:uuid, into the path template
+// documenting it, such as /wallets/{uuid}.
+func openAPIPath(route string) string {
+	parts := strings.Split(strings.TrimPrefix(route, "/api/v1"), "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") {
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,494 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// openAPIPrefix is the server URL the paths of the document are relative to.
+const openAPIPrefix = "/api/v1"
+
+func TestOpenAPI_Routes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := gin.Default()
+	New(router, mocks.NewMockWallet(ctrl)).Register()
+
+	param := regexp.MustCompile(`:(\w+)`)
+	registered := make(map[string]bool)
+	for _, r := range router.Routes() {
+		path := param.ReplaceAllString(strings.TrimPrefix(r.Path, openAPIPrefix), "{$1}")
+		key := r.Method + " " + path
+		registered[key] = true
+
+		if contract.Operation(r.Method, path) == nil {
+			t.Errorf("Expected: %v, recieved: %v", key+" documented", "undocumented route")
+		}
+	}
+
+	for path, item := range contract.Paths() {
+		for method := range item.(map[string]any) {
+			key := strings.ToUpper(method) + " " + path
+			if !registered[key] {
+				t.Errorf("Expected: %v, recieved: %v", key+" registered", "documented route without handler")
+			}
+		}
+	}
+}
+
+func TestOpenAPI_Contract(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	New(router, fakeService).Register()
+
+	reader := principalAuth{ID: "dashboard", Scopes: []string{auth.ScopeWalletsRead}}
+	securedRouter := gin.Default()
+	New(securedRouter, fakeService, WithAuthenticator(reader)).Register()
+
+	owner := "customer-1842"
+	walletID := uuid.New()
+	otherID := uuid.New()
+	operationID := uuid.New()
+	holdID := uuid.New()
+	now := time.Now()
+	rate := "0.92"
+	eur := "EUR"
+	counter := money.MustParse("9.2")
+	status := 503
+	lastErr := "unexpected status 503"
+
+	wallet := model.Wallet{UUID: walletID, Balance: money.MustParse("100.5"), Available: money.MustParse("90"), Currency: "USD", Status: model.WalletActive, OwnerID: &owner}
+	hold := model.Hold{ID: holdID, UUID: walletID, Currency: "USD", Amount: money.MustParse("10"), Status: model.HoldActive, ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+	operation := model.Operation{ID: operationID, Type: "TRANSFER", UUID: walletID, Currency: "USD", Amount: money.MustParse("10"), Counterparty: &otherID, CounterCurrency: &eur, CounterAmount: &counter, CreatedAt: now}
+	limit := money.MustParse("1000")
+	event := model.Event{ID: uuid.New(), Type: model.EventWalletCredited, UUID: walletID, Data: json.RawMessage(`{"amount": 10}`), CreatedAt: now}
+	eventPayload, _ := json.Marshal(event)
+	delivery := model.WebhookDelivery{ID: uuid.New(), WebhookID: uuid.New(), EventID: event.ID, EventType: event.Type, Payload: eventPayload, Status: model.DeliveryPending, Attempts: 2, LastStatus: &status, LastError: &lastErr, NextAttemptAt: now, CreatedAt: now}
+	webhook := model.Webhook{ID: uuid.New(), URL: "https://hooks.example.com/wallet", EventTypes: []string{model.EventWalletCredited}, CreatedAt: now}
+
+	cases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		// accept is the Accept header of the request, which selects the documented media type.
+		accept string
+		// invalid marks requests deliberately violating the document.
+		invalid bool
+		router  *gin.Engine
+		expect  func()
+		status  int
+	}{
+		{name: "OpenAPI", method: http.MethodGet, target: "/api/v1/openapi.json", status: http.StatusOK},
+		{
+			name: "WalletCreate", method: http.MethodPost, target: "/api/v1/wallets", body: `{"currency": "USD", "ownerId": "customer-1842"}`,
+			expect: func() { fakeService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(wallet, nil) },
+			status: http.StatusCreated,
+		},
+		{
+			name: "WalletCreateExists", method: http.MethodPost, target: "/api/v1/wallets", body: `{"ownerId": "customer-1842"}`,
+			expect: func() {
+				fakeService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Wallet{}, service.ErrWalletExists)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name: "WalletCreateForbidden", method: http.MethodPost, target: "/api/v1/wallets", router: securedRouter,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "WalletTransaction", method: http.MethodPost, target: "/api/v1/wallet",
+			body: fmt.Sprintf(`{"walletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, walletID),
+			expect: func() {
+				fakeReq := dto.WalletTransactionRequest{UUID: walletID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+				fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(wallet, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "WalletTransactionDeprecatedID", method: http.MethodPost, target: "/api/v1/wallet",
+			body: fmt.Sprintf(`{"valletId": "%s", "operationType": "WITHDRAW", "amount": "10.25"}`, walletID),
+			expect: func() {
+				fakeReq := dto.WalletTransactionRequest{UUID: walletID, Type: "WITHDRAW", Amount: money.MustParse("10.25")}
+				fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(wallet, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "WalletTransactionTransfer", method: http.MethodPost, target: "/api/v1/wallet",
+			body:   fmt.Sprintf(`{"walletId": "%s", "operationType": "TRANSFER", "amount": 10, "toWalletId": "%s", "currency": "USD"}`, walletID, otherID),
+			expect: func() { fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(wallet, nil) },
+			status: http.StatusOK,
+		},
+		{
+			name: "WalletTransactionInsufficientFunds", method: http.MethodPost, target: "/api/v1/wallet",
+			body: fmt.Sprintf(`{"walletId": "%s", "operationType": "WITHDRAW", "amount": 1000}`, walletID),
+			expect: func() {
+				fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(model.Wallet{}, service.ErrInsufficientFunds)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name: "WalletTransactionLimitExceeded", method: http.MethodPost, target: "/api/v1/wallet",
+			body: fmt.Sprintf(`{"walletId": "%s", "operationType": "WITHDRAW", "amount": 1000}`, walletID),
+			expect: func() {
+				fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(model.Wallet{}, &service.LimitError{Wallet: walletID, Limit: model.LimitDailyDebit})
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name: "WalletTransactionCurrencyMismatch", method: http.MethodPost, target: "/api/v1/wallet",
+			body: fmt.Sprintf(`{"walletId": "%s", "operationType": "DEPOSIT", "amount": 10, "currency": "EUR"}`, walletID),
+			expect: func() {
+				fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(model.Wallet{}, service.ErrCurrencyMismatch)
+			},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "WalletTransactionNotFound", method: http.MethodPost, target: "/api/v1/wallet",
+			body: fmt.Sprintf(`{"walletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, walletID),
+			expect: func() {
+				fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(model.Wallet{}, pgx.ErrNoRows)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name: "WalletTransactionInvalid", method: http.MethodPost, target: "/api/v1/wallet",
+			body:   fmt.Sprintf(`{"walletId": "%s", "operationType": "DEPOSIT", "amount": -10}`, walletID),
+			status: http.StatusBadRequest, invalid: true,
+		},
+		{
+			name: "WalletTransactionIDMismatch", method: http.MethodPost, target: "/api/v1/wallet",
+			body:   fmt.Sprintf(`{"walletId": "%s", "valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, walletID, otherID),
+			status: http.StatusBadRequest,
+		},
+		{
+			name: "WalletBalance", method: http.MethodGet, target: "/api/v1/wallets/" + walletID.String(),
+			expect: func() { fakeService.EXPECT().Balance(gomock.Any(), walletID).Return(wallet, nil) },
+			status: http.StatusOK,
+		},
+		{
+			name: "WalletBalanceNotFound", method: http.MethodGet, target: "/api/v1/wallets/" + otherID.String(),
+			expect: func() { fakeService.EXPECT().Balance(gomock.Any(), otherID).Return(model.Wallet{}, pgx.ErrNoRows) },
+			status: http.StatusNotFound,
+		},
+		{name: "WalletBalanceIncorrectUUID", method: http.MethodGet, target: "/api/v1/wallets/1234", status: http.StatusBadRequest},
+		{
+			name: "ProblemValidation", method: http.MethodPost, target: "/api/v1/wallet", accept: problemContentType,
+			body:   fmt.Sprintf(`{"walletId": "%s", "operationType": "DEPOSIT", "amount": -10}`, walletID),
+			status: http.StatusBadRequest, invalid: true,
+		},
+		{
+			name: "ProblemNotFound", method: http.MethodGet, target: "/api/v1/wallets/" + otherID.String(), accept: problemContentType,
+			expect: func() { fakeService.EXPECT().Balance(gomock.Any(), otherID).Return(model.Wallet{}, pgx.ErrNoRows) },
+			status: http.StatusNotFound,
+		},
+		{
+			name: "ProblemLimitExceeded", method: http.MethodPost, target: "/api/v1/wallet", accept: problemContentType,
+			body: fmt.Sprintf(`{"walletId": "%s", "operationType": "WITHDRAW", "amount": 1000}`, walletID),
+			expect: func() {
+				fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(model.Wallet{}, &service.LimitError{Wallet: walletID, Limit: model.LimitDailyDebit})
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name: "ProblemCurrencyMismatch", method: http.MethodPost, target: "/api/v1/wallet", accept: problemContentType,
+			body: fmt.Sprintf(`{"walletId": "%s", "operationType": "DEPOSIT", "amount": 10, "currency": "EUR"}`, walletID),
+			expect: func() {
+				fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(model.Wallet{}, service.ErrCurrencyMismatch)
+			},
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "WalletTransactions", method: http.MethodGet, target: "/api/v1/wallets/" + walletID.String() + "/transactions?limit=2",
+			expect: func() {
+				fakeService.EXPECT().Transactions(gomock.Any(), gomock.Any()).Return(dto.TransactionHistoryResponse{
+					Transactions: []model.Transaction{{ID: 2, OperationID: operationID, UUID: walletID, Type: "TRANSFER", Amount: money.MustParse("-10"), Balance: money.MustParse("90"), Counterparty: &otherID, CounterAmount: &counter, CounterCurrency: &eur, Rate: &rate, CreatedAt: now}},
+					NextCursor:   "MjAyNQ",
+				}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "OwnerWallets", method: http.MethodGet, target: "/api/v1/owners/" + owner + "/wallets",
+			expect: func() { fakeService.EXPECT().OwnerWallets(gomock.Any(), owner).Return([]model.Wallet{wallet}, nil) },
+			status: http.StatusOK,
+		},
+		{
+			name: "WalletEventsDisabled", method: http.MethodGet, target: "/api/v1/wallets/" + walletID.String() + "/events",
+			status: http.StatusServiceUnavailable,
+		},
+		{name: "SocketDisabled", method: http.MethodGet, target: "/api/v1/ws", status: http.StatusServiceUnavailable},
+		{name: "ProblemSocketDisabled", method: http.MethodGet, target: "/api/v1/ws", accept: problemContentType, status: http.StatusServiceUnavailable},
+		{
+			name: "HoldCreate", method: http.MethodPost, target: "/api/v1/wallets/" + walletID.String() + "/holds", body: `{"amount": 10, "ttlSeconds": 3600}`,
+			expect: func() { fakeService.EXPECT().CreateHold(gomock.Any(), gomock.Any()).Return(hold, nil) },
+			status: http.StatusCreated,
+		},
+		{
+			name: "Hold", method: http.MethodGet, target: "/api/v1/holds/" + holdID.String(),
+			expect: func() { fakeService.EXPECT().Hold(gomock.Any(), holdID).Return(hold, nil) },
+			status: http.StatusOK,
+		},
+		{
+			name: "HoldCapture", method: http.MethodPost, target: "/api/v1/holds/" + holdID.String() + "/capture", body: `{"amount": 5}`,
+			expect: func() {
+				captured := hold
+				captured.Status, captured.Captured, captured.OperationID = model.HoldCaptured, money.MustParse("5"), &operationID
+				fakeService.EXPECT().CaptureHold(gomock.Any(), gomock.Any()).Return(captured, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "HoldVoidNotActive", method: http.MethodPost, target: "/api/v1/holds/" + holdID.String() + "/void",
+			expect: func() {
+				fakeService.EXPECT().VoidHold(gomock.Any(), holdID).Return(model.Hold{}, service.ErrHoldNotActive)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name: "Operation", method: http.MethodGet, target: "/api/v1/transactions/" + operationID.String(),
+			expect: func() { fakeService.EXPECT().Operation(gomock.Any(), operationID).Return(operation, nil) },
+			status: http.StatusOK,
+		},
+		{
+			name: "TransactionReverse", method: http.MethodPost, target: "/api/v1/transactions/" + operationID.String() + "/reverse",
+			expect: func() {
+				reversal := model.Operation{ID: uuid.New(), Type: "REVERSAL", UUID: walletID, Currency: "USD", Amount: money.MustParse("10"), ReversalOf: &operationID, CreatedAt: now}
+				fakeService.EXPECT().Reverse(gomock.Any(), gomock.Any()).Return(reversal, nil)
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name: "WalletFreeze", method: http.MethodPost, target: "/api/v1/admin/wallets/" + walletID.String() + "/freeze", body: `{"reason": "chargeback"}`,
+			expect: func() {
+				frozen := wallet
+				frozen.Status = model.WalletFrozen
+				fakeService.EXPECT().SetStatus(gomock.Any(), gomock.Any()).Return(frozen, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "WalletCloseBalanceNotZero", method: http.MethodPost, target: "/api/v1/admin/wallets/" + walletID.String() + "/close", body: `{"reason": "closed by owner"}`,
+			expect: func() {
+				fakeService.EXPECT().SetStatus(gomock.Any(), gomock.Any()).Return(model.Wallet{}, service.ErrBalanceNotZero)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name: "WalletLimits", method: http.MethodGet, target: "/api/v1/admin/wallets/" + walletID.String() + "/limits",
+			expect: func() {
+				fakeService.EXPECT().Limits(gomock.Any(), walletID).Return(model.WalletLimits{UUID: walletID, Currency: "USD", DailyDebit: &limit}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "WalletLimitsSet", method: http.MethodPut, target: "/api/v1/admin/wallets/" + walletID.String() + "/limits", body: `{"dailyDebit": 1000, "maxBalance": null}`,
+			expect: func() {
+				fakeService.EXPECT().SetLimits(gomock.Any(), gomock.Any()).Return(model.WalletLimits{UUID: walletID, Currency: "USD", DailyDebit: &limit}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "AuditLog", method: http.MethodGet, target: "/api/v1/admin/audit?walletId=" + walletID.String(),
+			expect: func() {
+				fakeService.EXPECT().AuditLog(gomock.Any(), gomock.Any()).Return(dto.AuditLogResponse{
+					Records: []model.AuditRecord{{ID: 1, Action: model.AuditWalletCreate, UUID: &walletID, Principal: "dashboard", RequestID: "req-1", Before: json.RawMessage("null"), After: json.RawMessage(`{"status": "ACTIVE"}`), CreatedAt: now}},
+				}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "WebhookCreate", method: http.MethodPost, target: "/api/v1/admin/webhooks",
+			body:   `{"url": "https://hooks.example.com/wallet", "secret": "0123456789abcdef", "eventTypes": ["wallet.credited"]}`,
+			expect: func() { fakeService.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(webhook, nil) },
+			status: http.StatusCreated,
+		},
+		{
+			name: "Webhooks", method: http.MethodGet, target: "/api/v1/admin/webhooks",
+			expect: func() { fakeService.EXPECT().Webhooks(gomock.Any()).Return([]model.Webhook{webhook}, nil) },
+			status: http.StatusOK,
+		},
+		{
+			name: "WebhookDelete", method: http.MethodDelete, target: "/api/v1/admin/webhooks/" + webhook.ID.String(),
+			expect: func() { fakeService.EXPECT().DeleteWebhook(gomock.Any(), webhook.ID).Return(nil) },
+			status: http.StatusOK,
+		},
+		{
+			name: "WebhookDeliveries", method: http.MethodGet, target: "/api/v1/admin/webhooks/" + webhook.ID.String() + "/deliveries?status=PENDING",
+			expect: func() {
+				fakeService.EXPECT().WebhookDeliveries(gomock.Any(), gomock.Any()).Return([]model.WebhookDelivery{delivery}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "WebhookRedeliver", method: http.MethodPost, target: "/api/v1/admin/deliveries/" + delivery.ID.String() + "/redeliver",
+			expect: func() { fakeService.EXPECT().Redeliver(gomock.Any(), delivery.ID).Return(delivery, nil) },
+			status: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("TestOpenAPI_"+tc.name, func(t *testing.T) {
+			if tc.expect != nil {
+				tc.expect()
+			}
+			r := router
+			if tc.router != nil {
+				r = tc.router
+			}
+
+			op, template := contract.Match(tc.method, strings.TrimPrefix(strings.Split(tc.target, "?")[0], openAPIPrefix))
+			if op == nil {
+				t.Fatalf("Expected: %v, recieved: %v", tc.method+" "+tc.target+" documented", "undocumented route")
+			}
+
+			if tc.body != "" && !tc.invalid {
+				reqBody := contract.Resolve(op["requestBody"].(map[string]any))
+				var v any
+				err := json.Unmarshal([]byte(tc.body), &v)
+				if err != nil {
+					t.Fatal("unmarshal request err: ", err)
+				}
+				schema := reqBody["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+				err = contract.Validate(schema, v, "request")
+				if err != nil {
+					t.Errorf("request does not match %s %s: %v", tc.method, template, err)
+				}
+			}
+
+			req, err := http.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Error("new request err: ", err)
+			}
+			mediaType := "application/json"
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+				mediaType = tc.accept
+			}
+			recoder := httptest.NewRecorder()
+			r.ServeHTTP(recoder, req)
+			if recoder.Code != tc.status {
+				t.Errorf("response code incorrect. Expected: %d, received: %d", tc.status, recoder.Code)
+			}
+
+			res, ok := op["responses"].(map[string]any)[strconv.Itoa(recoder.Code)].(map[string]any)
+			if !ok {
+				t.Fatalf("Expected: %v, recieved: %v", "documented status", recoder.Code)
+			}
+			res = contract.Resolve(res)
+			media, ok := res["content"].(map[string]any)[mediaType].(map[string]any)
+			if !ok {
+				t.Fatalf("Expected: %v, recieved: %v", "documented "+mediaType+" response", res)
+			}
+			if ct := recoder.Header().Get("Content-Type"); !strings.HasPrefix(ct, mediaType) {
+				t.Errorf("Expected: %v, recieved: %v", mediaType, ct)
+			}
+			var v any
+			err = json.Unmarshal(recoder.Body.Bytes(), &v)
+			if err != nil {
+				t.Fatal("unmarshal body err: ", err)
+			}
+			err = contract.Validate(media["schema"].(map[string]any), v, "response")
+			if err != nil {
+				t.Errorf("response does not match %s %s %d: %v", tc.method, template, recoder.Code, err)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_Requests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := gin.Default()
+	New(router, mocks.NewMockWallet(ctrl)).Register()
+
+	walletID := uuid.New()
+
+	cases := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		message string
+	}{
+		{
+			name: "InvalidQueryEnum", method: http.MethodGet, target: "/api/v1/wallets/" + walletID.String() + "/transactions?operationType=BORROW",
+			message: "validation err: operationType must be one of OPENING DEPOSIT WITHDRAW TRANSFER CAPTURE REVERSAL",
+		},
+		{
+			name: "InvalidQueryType", method: http.MethodGet, target: "/api/v1/admin/webhooks/" + walletID.String() + "/deliveries?limit=ten",
+			message: "validation err: limit must be of type integer",
+		},
+		{
+			name: "InvalidBodyPattern", method: http.MethodPost, target: "/api/v1/wallets", body: `{"currency": "usd"}`,
+			message: "validation err: currency must match ^[A-Z]{3}$",
+		},
+		{
+			name: "InvalidBodyRequired", method: http.MethodPost, target: "/api/v1/admin/wallets/" + walletID.String() + "/freeze",
+			message: "validation err: request body is required",
+		},
+		{
+			name: "InvalidBodyNested", method: http.MethodPost, target: "/api/v1/admin/webhooks",
+			body:    `{"url": "https://hooks.example.com/wallet", "secret": "0123456789abcdef", "eventTypes": ["wallet.lost"]}`,
+			message: "validation err: eventTypes[0] must be one of wallet.created wallet.credited wallet.debited transfer.completed hold.created hold.voided hold.expired",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("TestOpenAPI_"+tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Error("new request err: ", err)
+			}
+
+			recoder := httptest.NewRecorder()
+			router.ServeHTTP(recoder, req)
+			correctCode := http.StatusBadRequest
+			if recoder.Code != correctCode {
+				t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+			}
+
+			resp := make(map[string]any)
+			correctResp := map[string]any{
+				"success": false,
+				"code":    service.CodeValidationFailed,
+				"message": tc.message,
+			}
+			err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+			if err != nil {
+				t.Error("unmarshal body err")
+			}
+			if !reflect.DeepEqual(correctResp, resp) {
+				t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	// problemTypePrefix turns error codes into the type URIs of problem details.
+	problemTypePrefix = "urn:wallet:problem:"
+)
+
+// errorStatuses are the response statuses of the kinds of service errors.
+var errorStatuses = map[service.Kind]int{
+	service.KindInternal:          http.StatusInternalServerError,
+	service.KindNotFound:          http.StatusNotFound,
+	service.KindValidation:        http.StatusBadRequest,
+	service.KindInsufficientFunds: http.StatusConflict,
+	service.KindFrozen:            http.StatusConflict,
+	service.KindLimitExceeded:     http.StatusConflict,
+	service.KindConflict:          http.StatusConflict,
+	service.KindUnprocessable:     http.StatusUnprocessableEntity,
+	service.KindUnauthenticated:   http.StatusUnauthorized,
+	service.KindForbidden:         http.StatusForbidden,
+	service.KindRateLimited:       http.StatusTooManyRequests,
+	service.KindUnavailable:       http.StatusServiceUnavailable,
+}
+
+// errInternal is sent in place of unexpected errors of the wallet service.
+var errInternal = &service.Error{Kind: service.KindInternal, Code: service.CodeInternal, Message: "wallet service err"}
+
+// problem holds RFC 7807 problem details. Code is the error code also sent in the v1 envelope,
+// and Errors lists the invalid fields of validation problems.
+type problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Instance string               `json:"instance,omitempty"`
+	Code     string               `json:"code"`
+	Errors   []service.FieldError `json:"errors,omitempty"`
+}
+
+// wantsProblem reports whether the client asked for problem details in the Accept header.
+// Other clients receive the v1 envelope they were built against.
+func wantsProblem(c *gin.Context) bool {
+	for _, accept := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == problemContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// sendError sends a failed response for e, as problem details to clients asking for them
+// and as the v1 envelope carrying the code and the message of e otherwise.
+func (h *handler) sendError(c *gin.Context, status int, e *service.Error) {
+	if !wantsProblem(c) {
+		c.JSON(status, errBody(e.Code, e.Message))
+		return
+	}
+	c.Header("Content-Type", problemContentType)
+	c.JSON(status, problem{
+		Type:     problemTypePrefix + e.Code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   e.Message,
+		Instance: c.Request.URL.Path,
+		Code:     e.Code,
+		Errors:   e.Fields,
+	})
+}
+
+// fail sends the response for an error of the wallet service. Unknown resources are reported
+// as notFound and unexpected errors, which the service logs, are hidden from the client.
+func (h *handler) fail(c *gin.Context, err error, notFound *service.Error) {
+	e := serviceError(err, notFound)
+	h.sendError(c, errorStatuses[e.Kind], e)
+}
+
+// serviceError classifies an error of the wallet service for the response.
+func serviceError(err error, notFound *service.Error) *service.Error {
+	e := service.AsError(err, notFound)
+	if e.Kind == service.KindInternal {
+		return errInternal
+	}
+	return e
+}
+
+// invalid sends 400 Bad Request for a request that failed binding or validation. Problem details
+// list the invalid fields of req, while the v1 envelope keeps the message of err.
+func (h *handler) invalid(c *gin.Context, req any, err error) {
+	if !wantsProblem(c) {
+		h.sendErr(c, http.StatusBadRequest, service.CodeValidationFailed, fmt.Sprint("validation err: ", err))
+		return
+	}
+	h.sendError(c, http.StatusBadRequest, service.NewValidationError(service.FieldErrors(req, err)))
+}
+
+// invalidParam sends 400 Bad Request for a malformed path or query parameter.
+func (h *handler) invalidParam(c *gin.Context, name string, message string) {
+	e := service.NewValidationError([]service.FieldError{{Field: name, Code: "format", Message: "is malformed"}})
+	e.Message = message
+	h.sendError(c, http.StatusBadRequest, e)
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestProblem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	checkProblem := func(t *testing.T, recoder *httptest.ResponseRecorder, correctCode int, correctResp map[string]any) {
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+		if contentType := recoder.Header().Get("Content-Type"); contentType != problemContentType {
+			t.Errorf("Expected: %v, recieved: %v", problemContentType, contentType)
+		}
+
+		resp := make(map[string]any)
+		err := json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	}
+
+	t.Run("TestProblem_Validation", func(t *testing.T) {
+		body := []byte(`{"operationType": "BORROW", "amount": 10}`)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/problem+json")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkProblem(t, recoder, http.StatusBadRequest, map[string]any{
+			"type":     "urn:wallet:problem:VALIDATION_FAILED",
+			"title":    "Bad Request",
+			"status":   float64(http.StatusBadRequest),
+			"detail":   "request is invalid",
+			"instance": "/api/v1/wallet",
+			"code":     service.CodeValidationFailed,
+			"errors": []any{
+				map[string]any{"field": "operationType", "code": "oneof", "message": "must be one of DEPOSIT WITHDRAW TRANSFER"},
+				map[string]any{"field": "walletId", "code": "required", "message": "is required"},
+			},
+		})
+	})
+
+	t.Run("TestProblem_MalformedBody", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBufferString(`{"operationType": 5}`))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/json, application/problem+json;q=0.9")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkProblem(t, recoder, http.StatusBadRequest, map[string]any{
+			"type":     "urn:wallet:problem:VALIDATION_FAILED",
+			"title":    "Bad Request",
+			"status":   float64(http.StatusBadRequest),
+			"detail":   "request is invalid",
+			"instance": "/api/v1/wallet",
+			"code":     service.CodeValidationFailed,
+			"errors": []any{
+				map[string]any{"field": "amount", "code": "required", "message": "is required"},
+				map[string]any{"field": "operationType", "code": "type", "message": "must be of type string"},
+				map[string]any{"field": "walletId", "code": "required", "message": "is required"},
+			},
+		})
+	})
+
+	t.Run("TestProblem_IncorrectUUID", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/wallets/1234", nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/problem+json")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkProblem(t, recoder, http.StatusBadRequest, map[string]any{
+			"type":     "urn:wallet:problem:VALIDATION_FAILED",
+			"title":    "Bad Request",
+			"status":   float64(http.StatusBadRequest),
+			"detail":   "request is invalid",
+			"instance": "/api/v1/wallets/1234",
+			"code":     service.CodeValidationFailed,
+			"errors": []any{
+				map[string]any{"field": "uuid", "code": "format", "message": "must be a UUID"},
+			},
+		})
+	})
+
+	t.Run("TestProblem_NotFound", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{}, pgx.ErrNoRows)
+
+		url := fmt.Sprintf("/api/v1/wallets/%s", fakeUUID)
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/problem+json")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkProblem(t, recoder, http.StatusNotFound, map[string]any{
+			"type":     "urn:wallet:problem:WALLET_NOT_FOUND",
+			"title":    "Not Found",
+			"status":   float64(http.StatusNotFound),
+			"detail":   "wallet not found",
+			"instance": url,
+			"code":     service.ErrWalletNotFound.Code,
+		})
+	})
+
+	t.Run("TestProblem_LimitExceeded", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("500")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, &service.LimitError{Wallet: fakeUUID, Limit: model.LimitDailyDebit})
+
+		body := []byte(fmt.Sprintf(`{"walletId": "%s", "operationType": "WITHDRAW", "amount": 500}`, fakeUUID))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/problem+json")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkProblem(t, recoder, http.StatusConflict, map[string]any{
+			"type":     "urn:wallet:problem:DAILY_LIMIT_EXCEEDED",
+			"title":    "Conflict",
+			"status":   float64(http.StatusConflict),
+			"detail":   fmt.Sprintf("amount exceeds the daily debit limit of wallet %s", fakeUUID),
+			"instance": "/api/v1/wallet",
+			"code":     "DAILY_LIMIT_EXCEEDED",
+		})
+	})
+
+	t.Run("TestProblem_IdempotentReplay", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		stored := []byte(`{"success": false, "code": "INSUFFICIENT_FUNDS", "message": "insufficient funds"}`)
+		fakeService.EXPECT().Idempotent(gomock.Any(), "key-1", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key string, fingerprint string, fn service.IdempotentFunc) (model.IdempotencyRecord, error) {
+				return model.IdempotencyRecord{Key: key, Status: http.StatusConflict, Body: stored, Replayed: true}, nil
+			})
+
+		body := []byte(fmt.Sprintf(`{"walletId": "%s", "operationType": "WITHDRAW", "amount": 10}`, fakeUUID))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/problem+json")
+		req.Header.Set(idempotencyKeyHeader, "key-1")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkProblem(t, recoder, http.StatusConflict, map[string]any{
+			"type":     "urn:wallet:problem:INSUFFICIENT_FUNDS",
+			"title":    "Conflict",
+			"status":   float64(http.StatusConflict),
+			"detail":   "insufficient funds",
+			"instance": "/api/v1/wallet",
+			"code":     service.ErrInsufficientFunds.Code,
+		})
+	})
+
+	t.Run("TestProblem_Unavailable", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/wallets/%s/events", uuid.New())
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/problem+json")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkProblem(t, recoder, http.StatusServiceUnavailable, map[string]any{
+			"type":     "urn:wallet:problem:UNAVAILABLE",
+			"title":    "Service Unavailable",
+			"status":   float64(http.StatusServiceUnavailable),
+			"detail":   "event streams are disabled",
+			"instance": url,
+			"code":     service.ErrUnavailable.Code,
+		})
+	})
+
+	t.Run("TestProblem_Envelope", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{}, pgx.ErrNoRows)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.Header.Set("Accept", "application/json")
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    service.ErrWalletNotFound.Code,
+			"message": "wallet not found",
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
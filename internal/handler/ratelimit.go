@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"cmd/app/main.go/internal/ratelimit"
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WithRateLimits throttles every client to the client limit and operations on every wallet
+// to the wallet limit, counting requests in l.
+func WithRateLimits(l ratelimit.Limiter, client ratelimit.Limit, wallet ratelimit.Limit) Option {
+	return func(h *handler) {
+		h.limiter = l
+		h.clientLimit = client
+		h.walletLimit = wallet
+	}
+}
+
+// limitClient rejects requests of clients exceeding their limit with 429 Too Many Requests.
+func (h *handler) limitClient(c *gin.Context) {
+	if !h.allow(c, h.clientKey(c), h.clientLimit) {
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// clientKey identifies the client of the request by its principal, or by address when authentication is disabled.
+// The address is read from X-Forwarded-For only if the router trusts the proxy that sent the request.
+func (h *handler) clientKey(c *gin.Context) string {
+	if p := h.principal(c); p.ID != "" {
+		return "client:" + p.ID
+	}
+	return "client:" + c.ClientIP()
+}
+
+// limitWallet reports whether an operation on the wallet is within the wallet limit.
+// The response is sent if it is not.
+func (h *handler) limitWallet(c *gin.Context, id uuid.UUID) bool {
+	return h.allow(c, "wallet:"+id.String(), h.walletLimit)
+}
+
+// allow takes a token from the bucket of key. Requests are let through if the limiter fails,
+// so that an unavailable limiter store does not take the API down.
+func (h *handler) allow(c *gin.Context, key string, l ratelimit.Limit) bool {
+	if h.limiter == nil {
+		return true
+	}
+	res, err := h.limiter.Allow(c.Request.Context(), key, l)
+	if err != nil {
+		log.Println("rate limiter err: ", err)
+		return true
+	}
+	if !res.Allowed {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+		h.sendError(c, http.StatusTooManyRequests, service.ErrRateLimited)
+		return false
+	}
+	return true
+}
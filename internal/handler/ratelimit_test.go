@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/ratelimit"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+// failingLimiter is a limiter whose store is unavailable.
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string, l ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, fmt.Errorf("db err")
+}
+
+func TestRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService, WithRateLimits(ratelimit.NewMemory(),
+		ratelimit.Limit{Rate: 0.1, Burst: 3},
+		ratelimit.Limit{Rate: 0.5, Burst: 1},
+	))
+	handler.Register()
+
+	transaction := func(t *testing.T, id uuid.UUID) *httptest.ResponseRecorder {
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, id))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		return recoder
+	}
+
+	t.Run("TestRateLimit_Wallet", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID}, nil).Times(1)
+
+		recoder := transaction(t, fakeUUID)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		recoder = transaction(t, fakeUUID)
+		correctCode = http.StatusTooManyRequests
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+		if recoder.Header().Get("Retry-After") != "2" {
+			t.Errorf("Retry-After header incorrect. Expected: %s, received: %s", "2", recoder.Header().Get("Retry-After"))
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    service.ErrRateLimited.Code,
+			"message": "rate limit exceeded",
+		}
+
+		err := json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestRateLimit_Client", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		recoder = httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode = http.StatusTooManyRequests
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+		if recoder.Header().Get("Retry-After") != "10" {
+			t.Errorf("Retry-After header incorrect. Expected: %s, received: %s", "10", recoder.Header().Get("Retry-After"))
+		}
+	})
+}
+
+func TestRateLimit_ForwardedFor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatal("set trusted proxies err: ", err)
+	}
+	handler := New(router, fakeService, WithRateLimits(ratelimit.NewMemory(),
+		ratelimit.Limit{Rate: 0.1, Burst: 1},
+		ratelimit.Limit{},
+	))
+	handler.Register()
+
+	fakeUUID := uuid.New()
+	fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+	get := func(forwardedFor string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		req.RemoteAddr = "10.0.0.7:40000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		return recoder
+	}
+
+	recoder := get("203.0.113.1")
+	correctCode := http.StatusOK
+	if recoder.Code != correctCode {
+		t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+	}
+
+	recoder = get("203.0.113.2")
+	correctCode = http.StatusTooManyRequests
+	if recoder.Code != correctCode {
+		t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+	}
+}
+
+func TestRateLimit_LimiterErr(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService, WithRateLimits(failingLimiter{},
+		ratelimit.Limit{Rate: 1, Burst: 1},
+		ratelimit.Limit{Rate: 1, Burst: 1},
+	))
+	handler.Register()
+
+	fakeUUID := uuid.New()
+	fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/wallets/%s", fakeUUID), nil)
+	if err != nil {
+		t.Error("new request err: ", err)
+	}
+
+	recoder := httptest.NewRecorder()
+	router.ServeHTTP(recoder, req)
+	correctCode := http.StatusOK
+	if recoder.Code != correctCode {
+		t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/service"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Operation returns the operation identified by the id in the path
+// along with the amount reversed so far. Operations on wallets of other owners are reported as not found.
+func (h *handler) Operation(c *gin.Context) {
+	id, err := uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect operation id")
+		return
+	}
+
+	res, err := h.walletService.Operation(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, service.ErrOperationNotFound)
+		return
+	}
+	if !h.authorizeOwner(c, res.UUID, service.ErrOperationNotFound) {
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// TransactionReverse creates a compensating operation for the operation identified by the id in the path.
+// The optional JSON body may carry the amount to refund, otherwise the whole remaining amount is reversed.
+// It responds with the reversal, which links to the original operation through reversalOf.
+// Reversals move funds back between wallets regardless of their owners, so the route requires the admin scope.
+func (h *handler) TransactionReverse(c *gin.Context) {
+	req := dto.ReversalRequest{}
+	if c.Request.ContentLength != 0 {
+		err := c.ShouldBindJSON(&req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			h.invalid(c, req, err)
+			return
+		}
+	}
+
+	var err error
+	req.ID, err = uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect operation id")
+		return
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	res, err := h.walletService.Reverse(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrOperationNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusCreated, res)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestTransactionReverse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestTransactionReverse_Success", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeReq := dto.ReversalRequest{ID: fakeID, Amount: money.MustParse("5")}
+		fakeRev := model.Operation{ID: uuid.New(), Type: "REVERSAL", Amount: fakeReq.Amount, ReversalOf: &fakeID}
+		fakeService.EXPECT().Reverse(gomock.Any(), fakeReq).Return(fakeRev, nil)
+
+		url := fmt.Sprintf("/api/v1/transactions/%s/reverse", fakeID)
+		body := []byte(`{"amount": 5}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		if recoder.Code != http.StatusCreated {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", http.StatusCreated, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		message := resp["message"].(map[string]any)
+		value, ok := message["reversalOf"]
+		if !ok || value != fakeID.String() {
+			t.Errorf("response body incorrect. Expected reversalOf: %v, received: %v", fakeID, value)
+		}
+	})
+
+	t.Run("TestTransactionReverse_AlreadyReversed", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeService.EXPECT().Reverse(gomock.Any(), dto.ReversalRequest{ID: fakeID}).Return(model.Operation{}, service.ErrAlreadyReversed)
+
+		url := fmt.Sprintf("/api/v1/transactions/%s/reverse", fakeID)
+
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"code":    "ALREADY_REVERSED",
+			"message": "operation already reversed",
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestTransactionReverse_NotFound", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeService.EXPECT().Reverse(gomock.Any(), dto.ReversalRequest{ID: fakeID}).Return(model.Operation{}, pgx.ErrNoRows)
+
+		url := fmt.Sprintf("/api/v1/transactions/%s/reverse", fakeID)
+
+		req, err := http.NewRequest(http.MethodPost, url, nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestTransactionReverse_BadAmount", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/transactions/%s/reverse", uuid.New())
+		body := []byte(`{"amount": -5}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+}
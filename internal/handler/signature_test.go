@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	secret := "s3cr3t"
+	router := gin.Default()
+	handler := New(router, fakeService, WithSignatures(auth.NewSignatures(map[string]string{"acme": secret}, 5*time.Minute)))
+	handler.Register()
+
+	signedRequest := func(t *testing.T, body []byte, timestamp time.Time, nonce string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+		ts := strconv.FormatInt(timestamp.Unix(), 10)
+		req.Header.Set(auth.ClientIDHeader, "acme")
+		req.Header.Set(auth.TimestampHeader, ts)
+		req.Header.Set(auth.NonceHeader, nonce)
+		req.Header.Set(auth.SignatureHeader, auth.SignRequest([]byte(secret), http.MethodPost, "/api/v1/wallet", ts, nonce, body))
+		return req
+	}
+
+	checkRejected := func(t *testing.T, recoder *httptest.ResponseRecorder, message string) {
+		correctCode := http.StatusUnauthorized
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"success": false,
+			"code":    service.ErrInvalidSignature.Code,
+			"message": message,
+		}
+
+		err := json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	}
+
+	t.Run("TestSignature_Valid", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, fakeUUID))
+		req := signedRequest(t, body, time.Now(), uuid.NewString())
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestSignature_Missing", func(t *testing.T) {
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, uuid.New()))
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkRejected(t, recoder, auth.ErrSignatureMissing.Error())
+	})
+
+	t.Run("TestSignature_TamperedBody", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, fakeUUID))
+		req := signedRequest(t, body, time.Now(), uuid.NewString())
+		tampered := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 1000}`, fakeUUID))
+		req.Body = io.NopCloser(bytes.NewReader(tampered))
+		req.ContentLength = int64(len(tampered))
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkRejected(t, recoder, auth.ErrSignatureMismatch.Error())
+	})
+
+	t.Run("TestSignature_OtherRoute", func(t *testing.T) {
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, uuid.New()))
+		req := signedRequest(t, body, time.Now(), uuid.NewString())
+		req.Header.Set(auth.SignatureHeader, auth.SignRequest([]byte(secret), http.MethodPost, "/api/v1/wallets", req.Header.Get(auth.TimestampHeader), req.Header.Get(auth.NonceHeader), body))
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkRejected(t, recoder, auth.ErrSignatureMismatch.Error())
+	})
+
+	t.Run("TestSignature_WrongSecret", func(t *testing.T) {
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, uuid.New()))
+		req := signedRequest(t, body, time.Now(), uuid.NewString())
+		req.Header.Set(auth.SignatureHeader, auth.SignRequest([]byte("guess"), http.MethodPost, "/api/v1/wallet", req.Header.Get(auth.TimestampHeader), req.Header.Get(auth.NonceHeader), body))
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkRejected(t, recoder, auth.ErrSignatureMismatch.Error())
+	})
+
+	t.Run("TestSignature_StaleTimestamp", func(t *testing.T) {
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, uuid.New()))
+		req := signedRequest(t, body, time.Now().Add(-10*time.Minute), uuid.NewString())
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkRejected(t, recoder, auth.ErrStaleTimestamp.Error())
+	})
+
+	t.Run("TestSignature_FutureTimestamp", func(t *testing.T) {
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, uuid.New()))
+		req := signedRequest(t, body, time.Now().Add(10*time.Minute), uuid.NewString())
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		checkRejected(t, recoder, auth.ErrStaleTimestamp.Error())
+	})
+
+	t.Run("TestSignature_Replay", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID}, nil).Times(1)
+
+		body := []byte(fmt.Sprintf(`{"valletId": "%s", "operationType": "DEPOSIT", "amount": 10}`, fakeUUID))
+		now := time.Now()
+		nonce := uuid.NewString()
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, signedRequest(t, body, now, nonce))
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		recoder = httptest.NewRecorder()
+		router.ServeHTTP(recoder, signedRequest(t, body, now, nonce))
+		checkRejected(t, recoder, auth.ErrNonceReused.Error())
+	})
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WithSocket serves the WebSocket API with s, which receives the authenticated requests.
+func WithSocket(s http.Handler) Option {
+	return func(h *handler) {
+		h.socket = s
+	}
+}
+
+// Socket upgrades the request to a WebSocket connection carrying wallet subscriptions and operations.
+// Reading wallets is required to connect; the scopes of each operation are checked by the socket server.
+func (h *handler) Socket(c *gin.Context) {
+	if h.socket == nil {
+		h.sendError(c, http.StatusServiceUnavailable, service.ErrUnavailable.WithMessage("websocket api is disabled"))
+		return
+	}
+	h.socket.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WalletFreeze freezes the wallet identified by the UUID in the path.
+func (h *handler) WalletFreeze(c *gin.Context) {
+	h.walletStatus(c, model.WalletFrozen)
+}
+
+// WalletUnfreeze makes a frozen wallet identified by the UUID in the path active again.
+func (h *handler) WalletUnfreeze(c *gin.Context) {
+	h.walletStatus(c, model.WalletActive)
+}
+
+// WalletClose closes the wallet identified by the UUID in the path. Only wallets with a zero balance can be closed.
+func (h *handler) WalletClose(c *gin.Context) {
+	h.walletStatus(c, model.WalletClosed)
+}
+
+// walletStatus moves the wallet identified by the UUID in the path to status.
+// The JSON body must carry the reason of the change. It responds with the updated wallet.
+func (h *handler) walletStatus(c *gin.Context, status string) {
+	req := dto.WalletStatusRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	req.UUID, err = uuid.Parse(c.Params.ByName("uuid"))
+	if err != nil {
+		h.invalidParam(c, "uuid", "incorrect wallet uuid")
+		return
+	}
+	req.Status = status
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	res, err := h.walletService.SetStatus(c.Request.Context(), req)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
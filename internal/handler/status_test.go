@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestWalletStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestWalletStatus_Freeze", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletStatusRequest{UUID: fakeUUID, Status: model.WalletFrozen, Reason: "fraud report"}
+		fakeService.EXPECT().SetStatus(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, Status: model.WalletFrozen}, nil)
+
+		url := fmt.Sprintf("/api/v1/admin/wallets/%s/freeze", fakeUUID)
+		body := []byte(`{"reason": "fraud report"}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		message := resp["message"].(map[string]any)
+		value, ok := message["status"]
+		if !ok || value != model.WalletFrozen {
+			t.Errorf("response body incorrect. Expected status: %v, received: %v", model.WalletFrozen, value)
+		}
+	})
+
+	t.Run("TestWalletStatus_NoReason", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v1/admin/wallets/%s/freeze", uuid.New())
+		body := []byte(`{}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusBadRequest
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWalletStatus_CloseNonZero", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletStatusRequest{UUID: fakeUUID, Status: model.WalletClosed, Reason: "customer request"}
+		fakeService.EXPECT().SetStatus(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrBalanceNotZero)
+
+		url := fmt.Sprintf("/api/v1/admin/wallets/%s/close", fakeUUID)
+		body := []byte(`{"reason": "customer request"}`)
+
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"code":    "BALANCE_NOT_ZERO",
+			"message": "wallet balance is not zero",
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+
+	t.Run("TestWalletStatus_TransactionFrozen", func(t *testing.T) {
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   uuid.New(),
+			Type:   "WITHDRAW",
+			Amount: money.MustParse("10"),
+		}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrWalletFrozen)
+
+		body, err := json.Marshal(fakeReq)
+		if err != nil {
+			t.Error("marshall err: ", err)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/wallet", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusConflict
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := make(map[string]any)
+		correctResp := map[string]any{
+			"code":    "WALLET_FROZEN",
+			"message": "wallet is frozen",
+			"success": false,
+		}
+
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+
+		ok := reflect.DeepEqual(correctResp, resp)
+
+		if !ok {
+			t.Errorf("response body incorrect. Expected: %v, received: %v", correctResp, resp)
+		}
+	})
+}
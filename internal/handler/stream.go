@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/internal/stream"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// lastEventIDHeader is sent by reconnecting event sources with the id of the last event they received.
+	lastEventIDHeader = "Last-Event-ID"
+	// streamReplayBatch is the number of missed events read at a time when a stream is resumed.
+	streamReplayBatch = 100
+	// streamReplayOverlap is the number of events up to Last-Event-ID replayed again when a stream is resumed,
+	// covering events written before the last one received but committed after it.
+	streamReplayOverlap = 16
+	// streamRetry is the delay in milliseconds event sources are told to wait before reconnecting.
+	streamRetry = 3000
+	// eventBalance is the event carrying the wallet when a stream starts without Last-Event-ID.
+	eventBalance = "wallet.balance"
+)
+
+// WithStream serves the events of wallets delivered by b as Server-Sent Events. Idle streams are sent
+// a comment every heartbeat to keep proxies from closing them and every stream is ended after
+// maxDuration, letting the client reconnect.
+func WithStream(b *stream.Broker, heartbeat time.Duration, maxDuration time.Duration) Option {
+	return func(h *handler) {
+		h.broker = b
+		h.streamHeartbeat = heartbeat
+		h.streamMaxDuration = maxDuration
+	}
+}
+
+// WalletEvents streams the changes of the wallet identified by the UUID in the path as Server-Sent Events
+// until the client disconnects, the stream reaches its maximum duration or the server shuts down.
+// Each event carries its sequence number as id. A stream resumed with the Last-Event-ID header, or
+// the lastEventId query parameter, first replays the events following that id, starting with the last
+// streamReplayOverlap events up to it, which clients deduplicate by event ID; a new stream starts
+// with a wallet.balance event carrying the wallet.
+// Clients opening too many streams are rejected with 429 Too Many Requests.
+func (h *handler) WalletEvents(c *gin.Context) {
+	if h.broker == nil {
+		h.sendError(c, http.StatusServiceUnavailable, service.ErrUnavailable.WithMessage("event streams are disabled"))
+		return
+	}
+
+	id, err := uuid.Parse(c.Params.ByName("uuid"))
+	if err != nil {
+		h.invalidParam(c, "uuid", "incorrect wallet uuid")
+		return
+	}
+
+	lastEventID := c.GetHeader(lastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	var last int64
+	if lastEventID != "" {
+		last, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || last < 0 {
+			h.invalidParam(c, "lastEventId", "incorrect last event id")
+			return
+		}
+	}
+
+	// Subscribing before reading the wallet and the missed events ensures no event falls in between.
+	sub, err := h.broker.Subscribe(id, h.clientKey(c))
+	if err != nil {
+		if errors.Is(err, stream.ErrTooManyStreams) {
+			h.sendError(c, http.StatusTooManyRequests, service.ErrTooManyStreams.WithMessage(err.Error()))
+			return
+		}
+		h.sendError(c, http.StatusServiceUnavailable, service.ErrUnavailable.WithMessage("event streams are unavailable"))
+		return
+	}
+	defer sub.Close()
+
+	ctx := c.Request.Context()
+	w, err := h.walletService.Balance(ctx, id)
+	if err != nil {
+		h.fail(c, err, service.ErrWalletNotFound)
+		return
+	}
+	if !h.principal(c).CanAccess(w.OwnerID) {
+		h.sendError(c, http.StatusNotFound, service.ErrWalletNotFound)
+		return
+	}
+
+	var backlog []model.Event
+	if lastEventID != "" {
+		backlog, err = h.walletService.WalletEvents(ctx, id, last, streamReplayOverlap, streamReplayBatch)
+		if err != nil {
+			h.sendError(c, http.StatusInternalServerError, errInternal)
+			return
+		}
+	}
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	_, err = fmt.Fprintf(c.Writer, "retry: %d\n\n", streamRetry)
+	if err != nil {
+		return
+	}
+	if lastEventID == "" {
+		err = sse.Encode(c.Writer, sse.Event{Event: eventBalance, Data: w})
+		if err != nil {
+			return
+		}
+	}
+
+	// Replay the missed events in batches, the live ones being buffered by the subscription meanwhile.
+	// Live events are deduplicated by ID rather than sequence number, which does not follow commit order.
+	replayed := make(map[uuid.UUID]struct{})
+	for len(backlog) > 0 {
+		for _, e := range backlog {
+			err = writeEvent(c, e)
+			if err != nil {
+				return
+			}
+			replayed[e.ID] = struct{}{}
+			last = e.Seq
+		}
+		if len(backlog) < streamReplayBatch {
+			break
+		}
+		backlog, err = h.walletService.WalletEvents(ctx, id, last, 0, streamReplayBatch)
+		if err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.streamHeartbeat)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(h.streamMaxDuration)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case <-sub.Done():
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(c.Writer, ": heartbeat\n\n")
+		case e := <-sub.Events():
+			if _, ok := replayed[e.ID]; ok {
+				delete(replayed, e.ID)
+				continue
+			}
+			err = writeEvent(c, e)
+		}
+		if err != nil {
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes e as a Server-Sent Event identified by its sequence number.
+func writeEvent(c *gin.Context, e model.Event) error {
+	return sse.Encode(c.Writer, sse.Event{
+		Id:    strconv.FormatInt(e.Seq, 10),
+		Event: e.Type,
+		Data:  e,
+	})
+}
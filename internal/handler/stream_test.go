@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/internal/stream"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// readEvent reads the fields of the next event of a stream, skipping comments and retry instructions.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	fields := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal("read stream err: ", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if fields["data"] != "" {
+				return fields
+			}
+			fields = map[string]string{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ":")
+		fields[name] = strings.TrimSpace(value)
+	}
+}
+
+func TestWalletEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	openStream := func(t *testing.T, b *stream.Broker, lastEventID string, id uuid.UUID) *http.Response {
+		router := gin.Default()
+		handler := New(router, fakeService, WithStream(b, time.Minute, time.Minute))
+		handler.Register()
+		srv := httptest.NewServer(router)
+		t.Cleanup(srv.Close)
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/api/v1/wallets/"+id.String()+"/events", nil)
+		if err != nil {
+			t.Fatal("new request err: ", err)
+		}
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal("request err: ", err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("TestWalletEvents_Live", func(t *testing.T) {
+		b := stream.NewBroker(nil)
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		resp := openStream(t, b, "", fakeUUID)
+		correctCode := http.StatusOK
+		if resp.StatusCode != correctCode {
+			t.Fatalf("response code incorrect. Expected: %d, received: %d", correctCode, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+			t.Errorf("Expected: %v, recieved: %v", "text/event-stream", ct)
+		}
+
+		r := bufio.NewReader(resp.Body)
+		e := readEvent(t, r)
+		if e["event"] != eventBalance || e["id"] != "" {
+			t.Errorf("Expected: %v, recieved: %v", eventBalance, e)
+		}
+
+		b.Publish(model.Event{Seq: 7, ID: uuid.New(), Type: model.EventWalletCredited, UUID: fakeUUID})
+		e = readEvent(t, r)
+		if e["event"] != model.EventWalletCredited || e["id"] != "7" {
+			t.Errorf("Expected: %v, recieved: %v", model.EventWalletCredited, e)
+		}
+
+		b.Close()
+		_, err := io.ReadAll(r)
+		if err != nil {
+			t.Error("stream not ended on close: ", err)
+		}
+	})
+
+	t.Run("TestWalletEvents_Resume", func(t *testing.T) {
+		b := stream.NewBroker(nil)
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+		replayed := model.Event{Seq: 6, ID: uuid.New(), Type: model.EventWalletDebited, UUID: fakeUUID}
+		fakeService.EXPECT().WalletEvents(gomock.Any(), fakeUUID, int64(5), streamReplayOverlap, streamReplayBatch).Return([]model.Event{
+			replayed,
+		}, nil)
+
+		resp := openStream(t, b, "5", fakeUUID)
+		correctCode := http.StatusOK
+		if resp.StatusCode != correctCode {
+			t.Fatalf("response code incorrect. Expected: %d, received: %d", correctCode, resp.StatusCode)
+		}
+
+		r := bufio.NewReader(resp.Body)
+		e := readEvent(t, r)
+		if e["event"] != model.EventWalletDebited || e["id"] != "6" {
+			t.Errorf("Expected: %v, recieved: %v", model.EventWalletDebited, e)
+		}
+
+		// The replayed event is announced again and is not sent twice.
+		b.Publish(replayed)
+		b.Publish(model.Event{Seq: 8, ID: uuid.New(), Type: model.EventWalletCredited, UUID: fakeUUID})
+		e = readEvent(t, r)
+		if e["event"] != model.EventWalletCredited || e["id"] != "8" {
+			t.Errorf("Expected: %v, recieved: %v", model.EventWalletCredited, e)
+		}
+		b.Close()
+	})
+
+	t.Run("TestWalletEvents_ResumeLateCommit", func(t *testing.T) {
+		b := stream.NewBroker(nil)
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+		fakeService.EXPECT().WalletEvents(gomock.Any(), fakeUUID, int64(9), streamReplayOverlap, streamReplayBatch).Return([]model.Event{
+			{Seq: 8, ID: uuid.New(), Type: model.EventWalletCredited, UUID: fakeUUID},
+			{Seq: 9, ID: uuid.New(), Type: model.EventWalletDebited, UUID: fakeUUID},
+		}, nil)
+
+		resp := openStream(t, b, "9", fakeUUID)
+		correctCode := http.StatusOK
+		if resp.StatusCode != correctCode {
+			t.Fatalf("response code incorrect. Expected: %d, received: %d", correctCode, resp.StatusCode)
+		}
+
+		r := bufio.NewReader(resp.Body)
+		for _, id := range []string{"8", "9"} {
+			e := readEvent(t, r)
+			if e["id"] != id {
+				t.Errorf("Expected: %v, recieved: %v", id, e)
+			}
+		}
+
+		// An event written before the last one replayed but committed after it is still sent.
+		b.Publish(model.Event{Seq: 7, ID: uuid.New(), Type: model.EventHoldCreated, UUID: fakeUUID})
+		e := readEvent(t, r)
+		if e["event"] != model.EventHoldCreated || e["id"] != "7" {
+			t.Errorf("Expected: %v, recieved: %v", model.EventHoldCreated, e)
+		}
+		b.Close()
+	})
+
+	t.Run("TestWalletEvents_NotFound", func(t *testing.T) {
+		b := stream.NewBroker(nil)
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{}, pgx.ErrNoRows)
+
+		resp := openStream(t, b, "", fakeUUID)
+		correctCode := http.StatusNotFound
+		if resp.StatusCode != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, resp.StatusCode)
+		}
+	})
+
+	t.Run("TestWalletEvents_IncorrectLastEventID", func(t *testing.T) {
+		resp := openStream(t, stream.NewBroker(nil), "abc", uuid.New())
+		correctCode := http.StatusBadRequest
+		if resp.StatusCode != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, resp.StatusCode)
+		}
+	})
+
+	t.Run("TestWalletEvents_TooManyStreams", func(t *testing.T) {
+		b := stream.NewBroker(nil, stream.WithMaxStreams(10, 0))
+
+		resp := openStream(t, b, "", uuid.New())
+		correctCode := http.StatusTooManyRequests
+		if resp.StatusCode != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, resp.StatusCode)
+		}
+	})
+}
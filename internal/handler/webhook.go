@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookCreate registers a webhook from the JSON body. The secret is used to sign the deliveries
+// and is never returned.
+func (h *handler) WebhookCreate(c *gin.Context) {
+	req := dto.WebhookCreateRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	res, err := h.walletService.CreateWebhook(c.Request.Context(), req)
+	if err != nil {
+		h.sendError(c, http.StatusInternalServerError, errInternal)
+		return
+	}
+	h.sendMsg(c, true, http.StatusCreated, res)
+}
+
+// Webhooks lists the registered webhooks.
+func (h *handler) Webhooks(c *gin.Context) {
+	res, err := h.walletService.Webhooks(c.Request.Context())
+	if err != nil {
+		h.sendError(c, http.StatusInternalServerError, errInternal)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// WebhookDelete removes the webhook identified by the id in the path.
+func (h *handler) WebhookDelete(c *gin.Context) {
+	id, err := uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect webhook id")
+		return
+	}
+
+	err = h.walletService.DeleteWebhook(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, service.ErrWebhookNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, "webhook deleted")
+}
+
+// WebhookDeliveries lists the latest deliveries of the webhook identified by the id in the path,
+// optionally only those in the status given in the query.
+func (h *handler) WebhookDeliveries(c *gin.Context) {
+	req := dto.WebhookDeliveriesRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	req.WebhookID, err = uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect webhook id")
+		return
+	}
+
+	err = h.validator.Struct(req)
+	if err != nil {
+		h.invalid(c, req, err)
+		return
+	}
+
+	res, err := h.walletService.WebhookDeliveries(c.Request.Context(), req)
+	if err != nil {
+		h.sendError(c, http.StatusInternalServerError, errInternal)
+		return
+	}
+	h.sendMsg(c, true, http.StatusOK, res)
+}
+
+// WebhookRedeliver schedules the delivery identified by the id in the path to be attempted again.
+func (h *handler) WebhookRedeliver(c *gin.Context) {
+	id, err := uuid.Parse(c.Params.ByName("id"))
+	if err != nil {
+		h.invalidParam(c, "id", "incorrect delivery id")
+		return
+	}
+
+	res, err := h.walletService.Redeliver(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err, service.ErrDeliveryNotFound)
+		return
+	}
+	h.sendMsg(c, true, http.StatusAccepted, res)
+}
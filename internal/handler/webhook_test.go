@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	mocks "cmd/app/main.go/internal/service/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func TestWebhooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	router := gin.Default()
+	handler := New(router, fakeService)
+	handler.Register()
+
+	t.Run("TestWebhooks_Create", func(t *testing.T) {
+		fakeReq := dto.WebhookCreateRequest{
+			URL:        "https://hooks.example.com/wallet",
+			Secret:     "0123456789abcdef",
+			EventTypes: []string{model.EventWalletCredited, model.EventTransferCompleted},
+		}
+		fakeService.EXPECT().CreateWebhook(gomock.Any(), fakeReq).Return(model.Webhook{
+			ID:         uuid.New(),
+			URL:        fakeReq.URL,
+			Secret:     fakeReq.Secret,
+			EventTypes: fakeReq.EventTypes,
+		}, nil)
+
+		body := []byte(`{"url": "https://hooks.example.com/wallet", "secret": "0123456789abcdef", "eventTypes": ["wallet.credited", "transfer.completed"]}`)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/admin/webhooks", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusCreated
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+
+		resp := struct {
+			Message map[string]any `json:"message"`
+		}{}
+		err = json.Unmarshal(recoder.Body.Bytes(), &resp)
+		if err != nil {
+			t.Error("unmarshal body err")
+		}
+		if _, ok := resp.Message["secret"]; ok {
+			t.Errorf("response body must not contain the secret: %v", resp.Message)
+		}
+	})
+
+	invalid := map[string]string{
+		"NoURL":        `{"secret": "0123456789abcdef", "eventTypes": ["wallet.created"]}`,
+		"NotHTTP":      `{"url": "ftp://hooks.example.com", "secret": "0123456789abcdef", "eventTypes": ["wallet.created"]}`,
+		"ShortSecret":  `{"url": "https://hooks.example.com", "secret": "short", "eventTypes": ["wallet.created"]}`,
+		"NoEventTypes": `{"url": "https://hooks.example.com", "secret": "0123456789abcdef", "eventTypes": []}`,
+		"UnknownEvent": `{"url": "https://hooks.example.com", "secret": "0123456789abcdef", "eventTypes": ["wallet.deleted"]}`,
+	}
+	for name, body := range invalid {
+		t.Run("TestWebhooks_Create"+name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/api/v1/admin/webhooks", bytes.NewBufferString(body))
+			if err != nil {
+				t.Error("new request err: ", err)
+			}
+
+			recoder := httptest.NewRecorder()
+			router.ServeHTTP(recoder, req)
+			correctCode := http.StatusBadRequest
+			if recoder.Code != correctCode {
+				t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+			}
+		})
+	}
+
+	t.Run("TestWebhooks_CreateHoldEvents", func(t *testing.T) {
+		fakeReq := dto.WebhookCreateRequest{
+			URL:        "https://hooks.example.com/wallet",
+			Secret:     "0123456789abcdef",
+			EventTypes: []string{model.EventHoldCreated, model.EventHoldVoided, model.EventHoldExpired},
+		}
+		fakeService.EXPECT().CreateWebhook(gomock.Any(), fakeReq).Return(model.Webhook{
+			ID:         uuid.New(),
+			URL:        fakeReq.URL,
+			Secret:     fakeReq.Secret,
+			EventTypes: fakeReq.EventTypes,
+		}, nil)
+
+		body := []byte(`{"url": "https://hooks.example.com/wallet", "secret": "0123456789abcdef", "eventTypes": ["hold.created", "hold.voided", "hold.expired"]}`)
+		req, err := http.NewRequest(http.MethodPost, "/api/v1/admin/webhooks", bytes.NewBuffer(body))
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusCreated
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWebhooks_Deliveries", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeReq := dto.WebhookDeliveriesRequest{WebhookID: fakeID, Status: model.DeliveryDead}
+		fakeService.EXPECT().WebhookDeliveries(gomock.Any(), fakeReq).Return([]model.WebhookDelivery{}, nil)
+
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/admin/webhooks/%s/deliveries?status=DEAD", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusOK
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWebhooks_Redeliver", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeService.EXPECT().Redeliver(gomock.Any(), fakeID).Return(model.WebhookDelivery{ID: fakeID, Status: model.DeliveryPending}, nil)
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/admin/deliveries/%s/redeliver", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusAccepted
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWebhooks_RedeliverNotFound", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeService.EXPECT().Redeliver(gomock.Any(), fakeID).Return(model.WebhookDelivery{}, pgx.ErrNoRows)
+
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/admin/deliveries/%s/redeliver", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+
+	t.Run("TestWebhooks_DeleteNotFound", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeService.EXPECT().DeleteWebhook(gomock.Any(), fakeID).Return(pgx.ErrNoRows)
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/admin/webhooks/%s", fakeID), nil)
+		if err != nil {
+			t.Error("new request err: ", err)
+		}
+
+		recoder := httptest.NewRecorder()
+		router.ServeHTTP(recoder, req)
+		correctCode := http.StatusNotFound
+		if recoder.Code != correctCode {
+			t.Errorf("response code incorrect. Expected: %d, received: %d", correctCode, recoder.Code)
+		}
+	})
+}
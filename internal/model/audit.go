@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited actions.
+const (
+	AuditWalletCreate   = "WALLET_CREATE"
+	AuditDeposit        = "DEPOSIT"
+	AuditWithdraw       = "WITHDRAW"
+	AuditTransfer       = "TRANSFER"
+	AuditHoldCreate     = "HOLD_CREATE"
+	AuditHoldCapture    = "HOLD_CAPTURE"
+	AuditHoldVoid       = "HOLD_VOID"
+	AuditReversal       = "REVERSAL"
+	AuditWalletFreeze   = "WALLET_FREEZE"
+	AuditWalletUnfreeze = "WALLET_UNFREEZE"
+	AuditWalletClose    = "WALLET_CLOSE"
+	AuditLimitsSet      = "LIMITS_SET"
+	AuditWebhookCreate  = "WEBHOOK_CREATE"
+	AuditWebhookDelete  = "WEBHOOK_DELETE"
+	AuditRedeliver      = "WEBHOOK_REDELIVER"
+)
+
+// AuditRecord describes a state change: who made it, from where, as part of which request,
+// and the affected values before and after it. Before is null for things the change created
+// and after for things it removed. UUID is nil for changes not tied to a wallet.
+type AuditRecord struct {
+	ID            int64           `json:"id"`
+	Action        string          `json:"action"`
+	UUID          *uuid.UUID      `json:"walletId,omitempty" db:"wallet_uuid"`
+	Principal     string          `json:"principal,omitempty"`
+	PrincipalName string          `json:"principalName,omitempty" db:"principal_name"`
+	SourceIP      string          `json:"sourceIp,omitempty" db:"source_ip"`
+	RequestID     string          `json:"requestId,omitempty" db:"request_id"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+}
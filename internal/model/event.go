@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Domain event types.
+const (
+	EventWalletCreated     = "wallet.created"
+	EventWalletCredited    = "wallet.credited"
+	EventWalletDebited     = "wallet.debited"
+	EventTransferCompleted = "transfer.completed"
+	EventHoldCreated       = "hold.created"
+	EventHoldVoided        = "hold.voided"
+	EventHoldExpired       = "hold.expired"
+)
+
+// Event is a domain event written to the outbox in the transaction of the change it describes.
+// Events may be delivered more than once, consumers deduplicate them by ID. Seq orders the
+// events in the outbox and Data holds the payload matching the event type.
+type Event struct {
+	Seq       int64           `json:"-"`
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	UUID      uuid.UUID       `json:"walletId" db:"wallet_uuid"`
+	Data      json.RawMessage `json:"data" db:"payload"`
+	CreatedAt time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// BalanceChange is the payload of wallet.credited and wallet.debited events.
+// Amount is the absolute amount of the ledger entry and Balance and Available
+// are the balances of the wallet right after it.
+type BalanceChange struct {
+	UUID         uuid.UUID    `json:"walletId"`
+	OperationID  uuid.UUID    `json:"operationId"`
+	Type         string       `json:"operationType"`
+	Amount       money.Amount `json:"amount"`
+	Currency     string       `json:"currency"`
+	Balance      money.Amount `json:"balance"`
+	Available    money.Amount `json:"available"`
+	Counterparty *uuid.UUID   `json:"counterpartyId,omitempty"`
+}
+
+// TransferCompleted is the payload of transfer.completed events.
+// Rate is only set for transfers between currencies.
+type TransferCompleted struct {
+	OperationID    uuid.UUID    `json:"operationId"`
+	From           uuid.UUID    `json:"fromWalletId"`
+	To             uuid.UUID    `json:"toWalletId"`
+	Debit          money.Amount `json:"debit"`
+	DebitCurrency  string       `json:"debitCurrency"`
+	Credit         money.Amount `json:"credit"`
+	CreditCurrency string       `json:"creditCurrency"`
+	Rate           string       `json:"rate,omitempty"`
+}
+
+// HoldChange is the payload of hold.created, hold.voided and hold.expired events.
+// Amount is the amount of the hold and Balance and Available are the balances
+// of the wallet right after it was reserved or released.
+type HoldChange struct {
+	HoldID    uuid.UUID    `json:"holdId"`
+	UUID      uuid.UUID    `json:"walletId"`
+	Amount    money.Amount `json:"amount"`
+	Currency  string       `json:"currency"`
+	Balance   money.Amount `json:"balance"`
+	Available money.Amount `json:"available"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"cmd/app/main.go/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hold statuses.
+const (
+	HoldActive   = "ACTIVE"
+	HoldCaptured = "CAPTURED"
+	HoldVoided   = "VOIDED"
+	HoldExpired  = "EXPIRED"
+)
+
+// Hold is a reservation of wallet funds. While active, Amount is excluded from the
+// wallet's available balance. Captured is the part of Amount taken from the wallet
+// by the ledger operation OperationID when the hold is captured.
+type Hold struct {
+	ID          uuid.UUID    `json:"holdId"`
+	UUID        uuid.UUID    `json:"walletId" db:"wallet_uuid"`
+	Currency    string       `json:"currency"`
+	Amount      money.Amount `json:"amount"`
+	Captured    money.Amount `json:"captured"`
+	Status      string       `json:"status"`
+	OperationID *uuid.UUID   `json:"operationId,omitempty" db:"operation_id"`
+	ExpiresAt   time.Time    `json:"expiresAt" db:"expires_at"`
+	CreatedAt   time.Time    `json:"createdAt" db:"created_at"`
+}
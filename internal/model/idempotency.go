@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// IdempotencyRecord is the response stored for the first request made with an idempotency key.
+// Replayed is set when the record is returned for a repeated request instead of a new outcome.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Status      int
+	Body        []byte
+	CreatedAt   time.Time `db:"created_at"`
+	Replayed    bool      `db:"-"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Spending limits of a wallet.
+const (
+	LimitMaxWithdrawal = "MAX_WITHDRAWAL"
+	LimitDailyDebit    = "DAILY_DEBIT"
+	LimitMonthlyDebit  = "MONTHLY_DEBIT"
+	LimitMaxBalance    = "MAX_BALANCE"
+)
+
+// WalletLimits are the spending limits of a wallet in its currency. A nil limit is not enforced.
+// MaxWithdrawal caps a single debit, DailyDebit the debits of the last 24 hours, MonthlyDebit
+// the debits of the calendar month (UTC) and MaxBalance the balance reached by credits.
+type WalletLimits struct {
+	UUID          uuid.UUID     `json:"walletId" db:"uuid"`
+	Currency      string        `json:"currency"`
+	MaxWithdrawal *money.Amount `json:"maxWithdrawal" db:"max_withdrawal"`
+	DailyDebit    *money.Amount `json:"dailyDebit" db:"daily_debit"`
+	MonthlyDebit  *money.Amount `json:"monthlyDebit" db:"monthly_debit"`
+	MaxBalance    *money.Amount `json:"maxBalance" db:"max_balance"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"cmd/app/main.go/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operation is a money-moving operation recorded in the ledger by one or more entries.
+// UUID is the debited wallet of transfers and Amount is the operation amount in its Currency.
+// For transfers, CounterAmount is the amount credited to the Counterparty wallet in CounterCurrency.
+// Reversed sums up the amounts of the reversals made so far, each of which links back
+// to the operation through ReversalOf.
+type Operation struct {
+	ID              uuid.UUID     `json:"operationId"`
+	Type            string        `json:"operationType"`
+	UUID            uuid.UUID     `json:"walletId" db:"wallet_uuid"`
+	Currency        string        `json:"currency"`
+	Amount          money.Amount  `json:"amount"`
+	Counterparty    *uuid.UUID    `json:"counterpartyWalletId,omitempty" db:"counterparty_uuid"`
+	CounterCurrency *string       `json:"counterCurrency,omitempty" db:"counter_currency"`
+	CounterAmount   *money.Amount `json:"counterAmount,omitempty" db:"counter_amount"`
+	Reversed        money.Amount  `json:"reversed"`
+	CounterReversed money.Amount  `json:"-" db:"counter_reversed"`
+	ReversalOf      *uuid.UUID    `json:"reversalOf,omitempty" db:"reversal_of"`
+	CreatedAt       time.Time     `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"cmd/app/main.go/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transaction is a single wallet ledger entry. Amount is the signed change
+// applied to the wallet and Balance is the wallet balance right after it.
+// Entries written by one operation, such as both legs of a transfer, share OperationID.
+// For transfers between currencies the entry also records the amount and currency of
+// the other leg and the applied exchange rate.
+type Transaction struct {
+	ID              int64         `json:"id"`
+	OperationID     uuid.UUID     `json:"operationId" db:"operation_id"`
+	UUID            uuid.UUID     `json:"walletId" db:"wallet_uuid"`
+	Type            string        `json:"operationType"`
+	Amount          money.Amount  `json:"amount"`
+	Balance         money.Amount  `json:"balance"`
+	Counterparty    *uuid.UUID    `json:"counterpartyWalletId,omitempty" db:"counterparty_uuid"`
+	CounterAmount   *money.Amount `json:"counterAmount,omitempty" db:"counter_amount"`
+	CounterCurrency *string       `json:"counterCurrency,omitempty" db:"counter_currency"`
+	Rate            *string       `json:"rate,omitempty"`
+	CreatedAt       time.Time     `json:"createdAt" db:"created_at"`
+}
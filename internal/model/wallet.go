@@ -1,8 +1,25 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"cmd/app/main.go/pkg/money"
 
+	"github.com/google/uuid"
+)
+
+// Wallet statuses.
+const (
+	WalletActive = "ACTIVE"
+	WalletFrozen = "FROZEN"
+	WalletClosed = "CLOSED"
+)
+
+// Wallet is a wallet state. Balance is the ledger balance and Available is the part of it
+// not reserved by active holds. OwnerID is the external reference of the owner, if any.
 type Wallet struct {
-	UUID    uuid.UUID `json:"walletId"`
-	Balance float64   `json:"balance"`
+	UUID      uuid.UUID    `json:"walletId"`
+	Balance   money.Amount `json:"balance"`
+	Available money.Amount `json:"available"`
+	Currency  string       `json:"currency"`
+	Status    string       `json:"status"`
+	OwnerID   *string      `json:"ownerId,omitempty" db:"owner_id"`
 }
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
+)
+
+// Webhook is an endpoint receiving the events of the listed types, signed with Secret.
+type Webhook struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"`
+	EventTypes []string  `json:"eventTypes" db:"event_types"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+}
+
+// WebhookDelivery is the delivery of an event to a webhook. Payload is the event as posted to URL.
+// Attempts counts the attempts made so far and NextAttemptAt is when a pending delivery is tried next.
+// LastStatus and LastError describe the outcome of the last failed attempt.
+type WebhookDelivery struct {
+	ID            uuid.UUID       `json:"id"`
+	WebhookID     uuid.UUID       `json:"webhookId" db:"webhook_id"`
+	URL           string          `json:"-"`
+	Secret        string          `json:"-"`
+	EventID       uuid.UUID       `json:"eventId" db:"event_id"`
+	EventType     string          `json:"eventType" db:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastStatus    *int            `json:"lastStatus,omitempty" db:"last_status"`
+	LastError     *string         `json:"lastError,omitempty" db:"last_error"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt" db:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"createdAt" db:"created_at"`
+	DeliveredAt   *time.Time      `json:"deliveredAt,omitempty" db:"delivered_at"`
+}
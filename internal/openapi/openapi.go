@@ -0,0 +1,460 @@
+// Package openapi validates values and requests against an OpenAPI 3 document, supporting the subset
+// of JSON Schema the document of the REST API is written with.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Document is a decoded OpenAPI document.
+type Document struct {
+	root map[string]any
+	// patterns holds the compiled patterns of the schemas, which are compiled once when loading.
+	patterns map[string]*regexp.Regexp
+}
+
+// Load decodes an OpenAPI document.
+func Load(data []byte) (*Document, error) {
+	root := make(map[string]any)
+	err := json.Unmarshal(data, &root)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := root["paths"].(map[string]any); !ok {
+		return nil, fmt.Errorf("openapi document has no paths")
+	}
+	d := &Document{root: root, patterns: make(map[string]*regexp.Regexp)}
+	err = d.compilePatterns(root)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// compilePatterns compiles the patterns of the schemas found under node.
+func (d *Document) compilePatterns(node any) error {
+	switch node := node.(type) {
+	case map[string]any:
+		for key, value := range node {
+			if p, ok := value.(string); ok && key == "pattern" {
+				re, err := regexp.Compile(p)
+				if err != nil {
+					return fmt.Errorf("openapi document pattern %q: %w", p, err)
+				}
+				d.patterns[p] = re
+				continue
+			}
+			err := d.compilePatterns(value)
+			if err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range node {
+			err := d.compilePatterns(value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MustLoad is like Load but panics if the document cannot be decoded.
+func MustLoad(data []byte) *Document {
+	d, err := Load(data)
+	if err != nil {
+		panic("openapi: " + err.Error())
+	}
+	return d
+}
+
+// Root returns the decoded document.
+func (d *Document) Root() map[string]any {
+	return d.root
+}
+
+// Paths returns the path items of the document by path template.
+func (d *Document) Paths() map[string]any {
+	return d.root["paths"].(map[string]any)
+}
+
+// Operation returns the operation documented for the method on the path template, such as
+// /wallets/{uuid}, or nil if there is none.
+func (d *Document) Operation(method string, template string) map[string]any {
+	item, ok := d.Paths()[template].(map[string]any)
+	if !ok {
+		return nil
+	}
+	op, _ := item[strings.ToLower(method)].(map[string]any)
+	return op
+}
+
+// Match finds the operation documented for a request path relative to the server URL,
+// along with its path template.
+func (d *Document) Match(method string, path string) (map[string]any, string) {
+	segments := strings.Split(path, "/")
+	for template := range d.Paths() {
+		parts := strings.Split(template, "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+		match := true
+		for i, part := range parts {
+			if part != segments[i] && !strings.HasPrefix(part, "{") {
+				match = false
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if op := d.Operation(method, template); op != nil {
+			return op, template
+		}
+	}
+	return nil, ""
+}
+
+// Resolve follows a local reference such as #/components/schemas/Wallet.
+func (d *Document) Resolve(node map[string]any) map[string]any {
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node
+	}
+	var cur any = d.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return map[string]any{}
+		}
+		cur = m[part]
+	}
+	target, ok := cur.(map[string]any)
+	if !ok {
+		return map[string]any{}
+	}
+	return d.Resolve(target)
+}
+
+// Violation describes a value that does not match its schema.
+type Violation struct {
+	// Field names the value in the body, query or path, such as amount or eventTypes[0].
+	// It is empty for the body as a whole.
+	Field string
+	// Rule is the schema keyword the value fails, such as required or maxLength. Values equal to
+	// an exclusive minimum fail exclusiveMinimum.
+	Rule    string
+	Message string
+}
+
+func (v *Violation) Error() string {
+	if v.Field == "" {
+		return v.Message
+	}
+	return v.Field + " " + v.Message
+}
+
+// Violations lists every part of a value or request that does not match the document.
+type Violations []*Violation
+
+func (vs Violations) Error() string {
+	msgs := make([]string, 0, len(vs))
+	for _, v := range vs {
+		msgs = append(msgs, v.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// violations collects the violations found while validating.
+type violations struct {
+	list Violations
+}
+
+func (vs *violations) add(field string, rule string, format string, args ...any) {
+	vs.list = append(vs.list, &Violation{Field: field, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+func (vs *violations) err() error {
+	if len(vs.list) == 0 {
+		return nil
+	}
+	return vs.list
+}
+
+// Request is a request to validate against the document.
+type Request struct {
+	Method string
+	// Path is the path template of the route relative to the server URL, such as /wallets/{uuid}.
+	Path string
+	// Params holds the values of the path parameters by name.
+	Params map[string]string
+	Query  url.Values
+	// Body is the raw body of the request, empty if it has none.
+	Body []byte
+}
+
+// ValidateRequest reports as Violations the path and query parameters and the parts of the JSON body
+// that do not match the operation of the request. Header parameters are not checked. A body that is
+// not JSON is left to the handler to report. Requests of undocumented operations are not checked.
+func (d *Document) ValidateRequest(r Request) error {
+	op := d.Operation(r.Method, r.Path)
+	if op == nil {
+		return nil
+	}
+
+	vs := &violations{}
+	for _, p := range asSlice(op["parameters"]) {
+		param, ok := p.(map[string]any)
+		if !ok {
+			continue
+		}
+		param = d.Resolve(param)
+		name, _ := param["name"].(string)
+		schema, _ := param["schema"].(map[string]any)
+
+		var values []string
+		switch param["in"] {
+		case "path":
+			if v, ok := r.Params[name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = r.Query[name]
+		default:
+			continue
+		}
+		if len(values) == 0 && param["required"] == true {
+			vs.add(name, "required", "is required")
+		}
+		for _, raw := range values {
+			d.check(vs, schema, parameterValue(d.Resolve(schema), raw), name)
+		}
+	}
+
+	if reqBody, ok := op["requestBody"].(map[string]any); ok {
+		d.checkBody(vs, d.Resolve(reqBody), r.Body)
+	}
+	return vs.err()
+}
+
+func (d *Document) checkBody(vs *violations, reqBody map[string]any, body []byte) {
+	if len(strings.TrimSpace(string(body))) == 0 {
+		if reqBody["required"] == true {
+			vs.add("", "required", "request body is required")
+		}
+		return
+	}
+	var v any
+	if json.Unmarshal(body, &v) != nil {
+		return
+	}
+	content, _ := reqBody["content"].(map[string]any)
+	media, _ := content["application/json"].(map[string]any)
+	schema, _ := media["schema"].(map[string]any)
+	d.check(vs, schema, v, "")
+}
+
+// parameterValue converts the raw value of a parameter to the type of its schema,
+// leaving values that do not convert as strings so that they fail validation.
+func parameterValue(schema map[string]any, raw string) any {
+	switch schema["type"] {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+// Validate reports as Violations the parts of v, decoded from JSON, that do not match the schema.
+// Violations name their field relative to field.
+func (d *Document) Validate(schema map[string]any, v any, field string) error {
+	vs := &violations{}
+	d.check(vs, schema, v, field)
+	return vs.err()
+}
+
+func (d *Document) check(vs *violations, schema map[string]any, v any, field string) {
+	schema = d.Resolve(schema)
+	if v == nil {
+		if len(schema) == 0 || schema["nullable"] == true {
+			return
+		}
+		if _, typed := schema["type"]; !typed && schema["allOf"] == nil && schema["anyOf"] == nil {
+			return
+		}
+		vs.add(field, "nullable", "must not be null")
+		return
+	}
+
+	if !d.checkType(vs, schema, v, field) {
+		return
+	}
+	if enum := asSlice(schema["enum"]); len(enum) > 0 && !contains(enum, v) {
+		values := make([]string, 0, len(enum))
+		for _, e := range enum {
+			values = append(values, fmt.Sprint(e))
+		}
+		vs.add(field, "enum", "must be one of %s", strings.Join(values, " "))
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		d.checkObject(vs, schema, v, field)
+	case []any:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(v)) < min {
+			vs.add(field, "minItems", "must have a length of at least %v", min)
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range v {
+			d.check(vs, items, item, field+"["+strconv.Itoa(i)+"]")
+		}
+	case string:
+		d.checkString(vs, schema, v, field)
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok {
+			if v < min {
+				vs.add(field, "minimum", "must be at least %v", min)
+			} else if v == min && schema["exclusiveMinimum"] == true {
+				vs.add(field, "exclusiveMinimum", "must be greater than %v", min)
+			}
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			vs.add(field, "maximum", "must be at most %v", max)
+		}
+	}
+
+	for _, s := range asSlice(schema["allOf"]) {
+		sub, _ := s.(map[string]any)
+		d.check(vs, sub, v, field)
+	}
+	if anyOf := asSlice(schema["anyOf"]); len(anyOf) > 0 {
+		// the violations of the first alternative are reported when none matches
+		var first *violations
+		for _, s := range anyOf {
+			sub, _ := s.(map[string]any)
+			alt := &violations{}
+			d.check(alt, sub, v, field)
+			if len(alt.list) == 0 {
+				first = nil
+				break
+			}
+			if first == nil {
+				first = alt
+			}
+		}
+		if first != nil {
+			vs.list = append(vs.list, first.list...)
+		}
+	}
+}
+
+// checkType reports whether v has the type of the schema, which is then checked further.
+func (d *Document) checkType(vs *violations, schema map[string]any, v any, field string) bool {
+	ok := true
+	switch schema["type"] {
+	case "object":
+		_, ok = v.(map[string]any)
+	case "array":
+		_, ok = v.([]any)
+	case "string":
+		_, ok = v.(string)
+	case "integer":
+		n, number := v.(float64)
+		ok = number && n == math.Trunc(n)
+	case "number":
+		_, ok = v.(float64)
+	case "boolean":
+		_, ok = v.(bool)
+	}
+	if !ok {
+		vs.add(field, "type", "must be of type %s", schema["type"])
+	}
+	return ok
+}
+
+func (d *Document) checkObject(vs *violations, schema map[string]any, obj map[string]any, field string) {
+	for _, name := range asSlice(schema["required"]) {
+		name, _ := name.(string)
+		if _, ok := obj[name]; !ok {
+			vs.add(join(field, name), "required", "is required")
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	props, _ := schema["properties"].(map[string]any)
+	for _, name := range names {
+		prop, ok := props[name].(map[string]any)
+		if !ok {
+			if schema["additionalProperties"] == false {
+				vs.add(join(field, name), "additionalProperties", "is not allowed")
+			}
+			continue
+		}
+		d.check(vs, prop, obj[name], join(field, name))
+	}
+}
+
+func (d *Document) checkString(vs *violations, schema map[string]any, s string, field string) {
+	switch schema["format"] {
+	case "uuid":
+		if _, err := uuid.Parse(s); err != nil {
+			vs.add(field, "format", "must be a UUID")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			vs.add(field, "format", "must be an RFC 3339 date-time")
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok && !d.patterns[pattern].MatchString(s) {
+		vs.add(field, "pattern", "must match %s", pattern)
+	}
+	if max, ok := schema["maxLength"].(float64); ok && float64(len(s)) > max {
+		vs.add(field, "maxLength", "must be at most %v characters long", max)
+	}
+	if min, ok := schema["minLength"].(float64); ok && float64(len(s)) < min {
+		vs.add(field, "minLength", "must be at least %v characters long", min)
+	}
+}
+
+func contains(values []any, v any) bool {
+	for _, e := range values {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func join(field string, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+const testDocument = `{
+  "openapi": "3.0.3",
+  "paths": {
+    "/wallets/{uuid}/holds": {
+      "post": {
+        "parameters": [
+          {"name": "uuid", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid"}},
+          {"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 100}}
+        ],
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Hold"}}}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Amount": {"anyOf": [{"type": "number"}, {"type": "string", "pattern": "^[0-9]+(\\.[0-9]+)?$"}]},
+      "Hold": {
+        "type": "object",
+        "required": ["amount"],
+        "properties": {
+          "amount": {"allOf": [{"$ref": "#/components/schemas/Amount"}], "minimum": 0, "exclusiveMinimum": true},
+          "currency": {"type": "string", "pattern": "^[A-Z]{3}$", "nullable": true},
+          "tags": {"type": "array", "minItems": 1, "items": {"type": "string", "enum": ["a", "b"]}}
+        }
+      }
+    }
+  }
+}`
+
+func TestValidateRequest(t *testing.T) {
+	doc, err := Load([]byte(testDocument))
+	if err != nil {
+		t.Fatal("load err: ", err)
+	}
+
+	valid := map[string]string{"uuid": "c5a72fdd-f1d8-47b2-b461-c132429120bb"}
+
+	cases := []struct {
+		name   string
+		params map[string]string
+		query  url.Values
+		body   string
+		expect Violations
+	}{
+		{name: "Valid", params: valid, query: url.Values{"limit": {"10"}}, body: `{"amount": "10.5", "currency": null, "tags": ["a"]}`},
+		{name: "NotJSON", params: valid, body: `{"amount":`},
+		{
+			name: "Params", params: map[string]string{"uuid": "1234"}, query: url.Values{"limit": {"0", "x"}}, body: `{"amount": 1}`,
+			expect: Violations{
+				{Field: "uuid", Rule: "format", Message: "must be a UUID"},
+				{Field: "limit", Rule: "minimum", Message: "must be at least 1"},
+				{Field: "limit", Rule: "type", Message: "must be of type integer"},
+			},
+		},
+		{
+			name: "MissingBody", params: valid,
+			expect: Violations{{Rule: "required", Message: "request body is required"}},
+		},
+		{
+			name: "Body", params: valid, body: `{"amount": 0, "currency": "usd", "tags": ["c"]}`,
+			expect: Violations{
+				{Field: "amount", Rule: "exclusiveMinimum", Message: "must be greater than 0"},
+				{Field: "currency", Rule: "pattern", Message: "must match ^[A-Z]{3}$"},
+				{Field: "tags[0]", Rule: "enum", Message: "must be one of a b"},
+			},
+		},
+		{
+			name: "AnyOf", params: valid, body: `{"amount": "ten", "tags": []}`,
+			expect: Violations{
+				{Field: "amount", Rule: "type", Message: "must be of type number"},
+				{Field: "tags", Rule: "minItems", Message: "must have a length of at least 1"},
+			},
+		},
+		{
+			name: "Required", params: valid, body: `{}`,
+			expect: Violations{{Field: "amount", Rule: "required", Message: "is required"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("TestValidateRequest_"+tc.name, func(t *testing.T) {
+			err := doc.ValidateRequest(Request{
+				Method: "POST",
+				Path:   "/wallets/{uuid}/holds",
+				Params: tc.params,
+				Query:  tc.query,
+				Body:   []byte(tc.body),
+			})
+
+			var vs Violations
+			if tc.expect == nil {
+				if err != nil {
+					t.Errorf("Expected: %v, recieved: %v", nil, err)
+				}
+				return
+			}
+			if !errors.As(err, &vs) {
+				t.Fatalf("Expected: %v, recieved: %v", tc.expect, err)
+			}
+			if !reflect.DeepEqual(tc.expect, vs) {
+				t.Errorf("Expected: %v, recieved: %v", tc.expect, vs)
+			}
+		})
+	}
+
+	t.Run("TestValidateRequest_Undocumented", func(t *testing.T) {
+		err := doc.ValidateRequest(Request{Method: "GET", Path: "/wallets/{uuid}/holds"})
+		if err != nil {
+			t.Errorf("Expected: %v, recieved: %v", nil, err)
+		}
+	})
+}
+
+func TestLoad_InvalidPattern(t *testing.T) {
+	_, err := Load([]byte(`{"paths": {}, "components": {"schemas": {"Code": {"type": "string", "pattern": "("}}}}`))
+	if err == nil {
+		t.Error("Expected an error for a pattern that does not compile")
+	}
+}
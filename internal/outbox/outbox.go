@@ -0,0 +1,127 @@
+// Package outbox relays the domain events written to the outbox table to publishers.
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+)
+
+// Default relay settings used unless configured otherwise.
+const (
+	defaultInterval  = time.Second
+	defaultBatchSize = 100
+)
+
+// Publisher delivers events to their consumers. Publish returns once the event is delivered,
+// an error means it was not and will be published again.
+type Publisher interface {
+	Publish(ctx context.Context, e model.Event) error
+}
+
+// Store keeps the outbox. It is implemented by db.Storage.
+type Store interface {
+	PendingEvents(ctx context.Context, limit int) ([]model.Event, error)
+	MarkPublished(ctx context.Context, seqs []int64) error
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Relay publishes the pending events of the outbox in order with at-least-once semantics:
+// an event is marked as published only after its publisher accepted it, so an event whose
+// publication was interrupted is published again. Several relays may run against the same
+// outbox, each one skipping the events another is publishing.
+type Relay struct {
+	store     Store
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+}
+
+// Option customizes the relay created by NewRelay.
+type Option func(*Relay)
+
+// WithInterval sets how often the outbox is polled for new events.
+func WithInterval(d time.Duration) Option {
+	return func(r *Relay) {
+		r.interval = d
+	}
+}
+
+// WithBatchSize sets how many events are published per outbox transaction.
+func WithBatchSize(n int) Option {
+	return func(r *Relay) {
+		r.batchSize = n
+	}
+}
+
+// NewRelay returns a relay publishing the events of store with p.
+func NewRelay(store Store, p Publisher, opts ...Option) *Relay {
+	r := &Relay{
+		store:     store,
+		publisher: p,
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run publishes pending events until ctx is done. Full batches are followed by the next one
+// right away, otherwise the outbox is polled again after the interval.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.Publish(ctx)
+			if err != nil {
+				log.Println("outbox relay err: ", err)
+				break
+			}
+			if n < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publish publishes one batch of pending events and returns how many were published.
+// Publishing stops at the first event the publisher fails on; the events published before it
+// are still marked as published and the failed one is retried by the next call.
+func (r *Relay) Publish(ctx context.Context) (int, error) {
+	var published []int64
+	var publishErr error
+
+	err := r.store.WithTx(ctx, func(ctx context.Context) error {
+		events, err := r.store.PendingEvents(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			publishErr = r.publisher.Publish(ctx, e)
+			if publishErr != nil {
+				break
+			}
+			published = append(published, e.Seq)
+		}
+		if len(published) == 0 {
+			return nil
+		}
+		return r.store.MarkPublished(ctx, published)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(published), publishErr
+}
@@ -0,0 +1,138 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+// failingPublisher fails on the events with the listed ids and publishes the others to Memory.
+type failingPublisher struct {
+	Memory
+	fail map[uuid.UUID]bool
+}
+
+func (p *failingPublisher) Publish(ctx context.Context, e model.Event) error {
+	if p.fail[e.ID] {
+		return errors.New("broker unavailable")
+	}
+	return p.Memory.Publish(ctx, e)
+}
+
+func TestRelayPublish(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+
+	runTx := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}
+	fakeEvents := func() []model.Event {
+		return []model.Event{
+			{Seq: 1, ID: uuid.New(), Type: model.EventWalletCreated},
+			{Seq: 2, ID: uuid.New(), Type: model.EventWalletCredited},
+			{Seq: 3, ID: uuid.New(), Type: model.EventWalletDebited},
+		}
+	}
+
+	t.Run("TestRelayPublish_Batch", func(t *testing.T) {
+		events := fakeEvents()
+		p := NewMemory()
+		r := NewRelay(fakeDB, p, WithBatchSize(10))
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().PendingEvents(gomock.Any(), 10).Return(events, nil)
+		fakeDB.EXPECT().MarkPublished(gomock.Any(), []int64{1, 2, 3}).Return(nil)
+
+		n, err := r.Publish(t.Context())
+		if err != nil {
+			t.Error("publish err: ", err)
+		}
+		if n != 3 {
+			t.Errorf("Expected: %v, recieved: %v", 3, n)
+		}
+		if !reflect.DeepEqual(p.Events(), events) {
+			t.Errorf("Expected: %v, recieved: %v", events, p.Events())
+		}
+	})
+
+	t.Run("TestRelayPublish_Empty", func(t *testing.T) {
+		r := NewRelay(fakeDB, NewMemory())
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().PendingEvents(gomock.Any(), defaultBatchSize).Return(nil, nil)
+
+		n, err := r.Publish(t.Context())
+		if err != nil || n != 0 {
+			t.Errorf("Expected: %v, recieved: %v %v", 0, n, err)
+		}
+	})
+
+	t.Run("TestRelayPublish_PublisherFails", func(t *testing.T) {
+		events := fakeEvents()
+		p := &failingPublisher{fail: map[uuid.UUID]bool{events[1].ID: true}}
+		r := NewRelay(fakeDB, p)
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().PendingEvents(gomock.Any(), defaultBatchSize).Return(events, nil)
+		fakeDB.EXPECT().MarkPublished(gomock.Any(), []int64{1}).Return(nil)
+
+		n, err := r.Publish(t.Context())
+		if err == nil {
+			t.Error("Expected publish error")
+		}
+		if n != 1 {
+			t.Errorf("Expected: %v, recieved: %v", 1, n)
+		}
+	})
+
+	t.Run("TestRelayPublish_MarkFails", func(t *testing.T) {
+		events := fakeEvents()
+		fakeErr := errors.New("random db err")
+		r := NewRelay(fakeDB, NewMemory())
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().PendingEvents(gomock.Any(), defaultBatchSize).Return(events, nil)
+		fakeDB.EXPECT().MarkPublished(gomock.Any(), []int64{1, 2, 3}).Return(fakeErr)
+
+		n, err := r.Publish(t.Context())
+		if !errors.Is(err, fakeErr) {
+			t.Errorf("Expected: %v, recieved: %v", fakeErr, err)
+		}
+		if n != 0 {
+			t.Errorf("Expected: %v, recieved: %v", 0, n)
+		}
+	})
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewWriter(&buf)
+	events := []model.Event{
+		{ID: uuid.New(), Type: model.EventWalletCreated, UUID: uuid.New(), Data: json.RawMessage(`{"currency":"USD"}`)},
+		{ID: uuid.New(), Type: model.EventWalletCredited, UUID: uuid.New(), Data: json.RawMessage(`{"amount":10}`)},
+	}
+	for _, e := range events {
+		err := p.Publish(t.Context(), e)
+		if err != nil {
+			t.Error("publish err: ", err)
+		}
+	}
+
+	dec := json.NewDecoder(&buf)
+	for _, correct := range events {
+		var e model.Event
+		err := dec.Decode(&e)
+		if err != nil {
+			t.Fatal("decode err: ", err)
+		}
+		if e.ID != correct.ID || e.Type != correct.Type || e.UUID != correct.UUID || !bytes.Equal(e.Data, correct.Data) {
+			t.Errorf("Expected: %v, recieved: %v", correct, e)
+		}
+	}
+}
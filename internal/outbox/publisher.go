@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"cmd/app/main.go/internal/model"
+)
+
+// Writer publishes events as JSON lines to an io.Writer, such as the standard output or a file.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriter returns a publisher writing events to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// OpenFile returns a publisher appending events to the file at path, which is created if needed.
+// Every event is synced to disk before it counts as published.
+func OpenFile(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriter(f), nil
+}
+
+// Publish writes e as one line of JSON.
+func (p *Writer) Publish(ctx context.Context, e model.Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.w.Write(line)
+	if err != nil {
+		return err
+	}
+	if f, ok := p.w.(*os.File); ok && f != os.Stdout && f != os.Stderr {
+		return f.Sync()
+	}
+	return nil
+}
+
+// Close closes the underlying writer if it is a closer other than the standard output.
+func (p *Writer) Close() error {
+	if p.w == os.Stdout || p.w == os.Stderr {
+		return nil
+	}
+	if c, ok := p.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Publishers publishes every event with each of its publishers in turn. An event is only published
+// once all of them accepted it, so publishers that accepted it may get it again after another one failed.
+type Publishers []Publisher
+
+// Publish publishes e with every publisher, stopping at the first failure.
+func (ps Publishers) Publish(ctx context.Context, e model.Event) error {
+	for _, p := range ps {
+		err := p.Publish(ctx, e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Memory keeps published events in memory. It is meant for tests and local runs.
+type Memory struct {
+	mu     sync.Mutex
+	events []model.Event
+}
+
+// NewMemory returns an empty in-memory publisher.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Publish appends e to the published events.
+func (p *Memory) Publish(ctx context.Context, e model.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, e)
+	return nil
+}
+
+// Events returns the events published so far, in order.
+func (p *Memory) Events() []model.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]model.Event(nil), p.events...)
+}
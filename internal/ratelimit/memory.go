@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Memory keeps the buckets in process memory, so each replica enforces its own limits.
+type Memory struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow refills the bucket for the time passed since it was last used and takes a token if one is left.
+func (m *Memory) Allow(ctx context.Context, key string, l Limit) (Result, error) {
+	if l.Rate <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	now := m.now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst()), updated: now}
+		m.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(l.burst()), b.tokens+now.Sub(b.updated).Seconds()*l.Rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.Rate * float64(time.Second))
+		return Result{RetryAfter: wait}, nil
+	}
+	b.tokens--
+	b.full = now.Add(time.Duration((float64(l.burst()) - b.tokens) / l.Rate * float64(time.Second)))
+	return Result{Allowed: true}, nil
+}
+
+// prune drops buckets that have refilled completely, as they are the same as new ones.
+// It runs at most once a minute.
+func (m *Memory) prune(now time.Time) {
+	if now.Sub(m.lastPrune) < time.Minute {
+		return
+	}
+	for k, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, k)
+		}
+	}
+	m.lastPrune = now
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	now := time.Date(2026, 1, 19, 12, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 3}
+
+	t.Run("TestMemory_Burst", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			res, err := m.Allow(context.Background(), "burst", limit)
+			if err != nil || !res.Allowed {
+				t.Errorf("Expected request %d to be allowed, recieved: %v, %v", i, res, err)
+			}
+		}
+
+		res, err := m.Allow(context.Background(), "burst", limit)
+		if err != nil || res.Allowed {
+			t.Errorf("Expected request to be rejected, recieved: %v, %v", res, err)
+		}
+		if res.RetryAfter != 500*time.Millisecond {
+			t.Errorf("Expected: %v, recieved: %v", 500*time.Millisecond, res.RetryAfter)
+		}
+	})
+
+	t.Run("TestMemory_Refill", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			m.Allow(context.Background(), "refill", limit)
+		}
+		now = now.Add(time.Second)
+
+		for i := 0; i < 2; i++ {
+			res, _ := m.Allow(context.Background(), "refill", limit)
+			if !res.Allowed {
+				t.Errorf("Expected refilled request %d to be allowed", i)
+			}
+		}
+		res, _ := m.Allow(context.Background(), "refill", limit)
+		if res.Allowed {
+			t.Errorf("Expected request beyond the refill to be rejected")
+		}
+	})
+
+	t.Run("TestMemory_SeparateKeys", func(t *testing.T) {
+		one := Limit{Rate: 1, Burst: 1}
+		m.Allow(context.Background(), "a", one)
+		res, _ := m.Allow(context.Background(), "b", one)
+		if !res.Allowed {
+			t.Errorf("Expected buckets of other keys not to be affected")
+		}
+	})
+
+	t.Run("TestMemory_Unlimited", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			res, _ := m.Allow(context.Background(), "unlimited", Limit{})
+			if !res.Allowed {
+				t.Errorf("Expected a zero limit not to restrict requests")
+			}
+		}
+	})
+
+	t.Run("TestMemory_Prune", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		m.Allow(context.Background(), "fresh", limit)
+		if len(m.buckets) != 1 {
+			t.Errorf("Expected: %v buckets, recieved: %v", 1, len(m.buckets))
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store keeps token buckets shared between replicas.
+// TakeToken takes a token from the bucket of key refilled every interval and holding up to burst tokens.
+// It returns zero if the token was taken and otherwise how long until one is available.
+type Store interface {
+	TakeToken(ctx context.Context, key string, interval time.Duration, burst int) (time.Duration, error)
+}
+
+// Postgres keeps the buckets in the database, so limits hold across replicas.
+type Postgres struct {
+	store Store
+}
+
+func NewPostgres(store Store) *Postgres {
+	return &Postgres{
+		store: store,
+	}
+}
+
+func (p *Postgres) Allow(ctx context.Context, key string, l Limit) (Result, error) {
+	if l.Rate <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	wait, err := p.store.TakeToken(ctx, key, l.interval(), l.burst())
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Allowed: wait == 0, RetryAfter: wait}, nil
+}
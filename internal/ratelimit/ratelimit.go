@@ -0,0 +1,34 @@
+// Package ratelimit throttles requests with token buckets kept in memory or in Postgres.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second and holding up to Burst tokens.
+// A limit with a zero rate does not restrict anything; a burst below one is treated as one.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) burst() int {
+	return max(l.Burst, 1)
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return time.Duration(float64(time.Second) / l.Rate)
+}
+
+// Result reports whether a request may proceed and, if not, when a token becomes available.
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
+
+// Limiter takes a token from the bucket identified by key.
+type Limiter interface {
+	Allow(ctx context.Context, key string, l Limit) (Result, error)
+}
@@ -0,0 +1,191 @@
+package rates
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// FileProvider serves exchange rates read from a local JSON or CSV file.
+//
+// The JSON file maps source currencies to target currencies and rates:
+//
+//	{"USD": {"EUR": "0.92", "JPY": "150.25"}}
+//
+// The CSV file has one "from,to,rate" record per line.
+// Rates can be given as strings or numbers and are parsed exactly.
+// When only the reverse pair is listed, its inverse is used.
+type FileProvider struct {
+	path string
+
+	mu      sync.RWMutex
+	rates   map[string]*big.Rat
+	modTime time.Time
+}
+
+// NewFileProvider creates a provider for the rates file at path and loads it.
+func NewFileProvider(path string) (*FileProvider, error) {
+	p := &FileProvider{
+		path: path,
+	}
+	err := p.Load()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Rate returns how many units of currency to one unit of currency from is worth.
+func (p *FileProvider) Rate(ctx context.Context, from string, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if r, ok := p.rates[pair(from, to)]; ok {
+		return new(big.Rat).Set(r), nil
+	}
+	if r, ok := p.rates[pair(to, from)]; ok {
+		return new(big.Rat).Inv(r), nil
+	}
+	return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, from, to)
+}
+
+// Load reads the rates file and replaces the rates served by the provider.
+// The previous rates are kept when the file cannot be read or parsed.
+func (p *FileProvider) Load() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("stat rates file error: %w", err)
+	}
+
+	f, err := os.Open(p.path)
+	if err != nil {
+		return fmt.Errorf("open rates file error: %w", err)
+	}
+	defer f.Close()
+
+	var rates map[string]*big.Rat
+	if strings.EqualFold(filepath.Ext(p.path), ".csv") {
+		rates, err = parseCSV(f)
+	} else {
+		rates, err = parseJSON(f)
+	}
+	if err != nil {
+		return fmt.Errorf("parse rates file error: %w", err)
+	}
+
+	p.mu.Lock()
+	p.rates = rates
+	p.modTime = info.ModTime()
+	p.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the rates file whenever its modification time changes.
+// The file is checked every interval until ctx is done.
+func (p *FileProvider) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(p.path)
+		if err != nil {
+			log.Println("rates file watch err: ", err)
+			continue
+		}
+
+		p.mu.RLock()
+		changed := !info.ModTime().Equal(p.modTime)
+		p.mu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		err = p.Load()
+		if err != nil {
+			log.Println("rates file reload err: ", err)
+			continue
+		}
+		log.Println("rates file reloaded: ", p.path)
+	}
+}
+
+// pair builds the key of a currency pair in the rates map.
+func pair(from string, to string) string {
+	return from + "/" + to
+}
+
+// parseRate converts a decimal rate into an exact positive rational number.
+func parseRate(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid rate %q", s)
+	}
+	return r, nil
+}
+
+func parseJSON(r io.Reader) (map[string]*big.Rat, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var raw map[string]map[string]any
+	err := dec.Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]*big.Rat)
+	for from, targets := range raw {
+		for to, v := range targets {
+			rate, err := parseRate(fmt.Sprint(v))
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", from, to, err)
+			}
+			rates[pair(from, to)] = rate
+		}
+	}
+	return rates, nil
+}
+
+func parseCSV(r io.Reader) (map[string]*big.Rat, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]*big.Rat)
+	for _, rec := range records {
+		from, to := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1])
+		rate, err := parseRate(rec[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", from, to, err)
+		}
+		rates[pair(from, to)] = rate
+	}
+	return rates, nil
+}
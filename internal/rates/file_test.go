@@ -0,0 +1,98 @@
+package rates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path string, data string) {
+	t.Helper()
+	err := os.WriteFile(path, []byte(data), 0o644)
+	if err != nil {
+		t.Fatal("write file err: ", err)
+	}
+}
+
+func TestFileProviderJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	writeFile(t, path, `{"USD": {"EUR": "0.92", "JPY": 150.25}}`)
+
+	p, err := NewFileProvider(path)
+	if err != nil {
+		t.Fatal("new provider err: ", err)
+	}
+
+	t.Run("TestFileProviderJSON_Direct", func(t *testing.T) {
+		rate, err := p.Rate(t.Context(), "USD", "JPY")
+		if err != nil || rate.FloatString(2) != "150.25" {
+			t.Errorf("Expected: 150.25, recieved: %v, err: %v", rate, err)
+		}
+	})
+
+	t.Run("TestFileProviderJSON_Inverse", func(t *testing.T) {
+		rate, err := p.Rate(t.Context(), "EUR", "USD")
+		if err != nil || rate.FloatString(4) != "1.0870" {
+			t.Errorf("Expected: 1.0870, recieved: %v, err: %v", rate, err)
+		}
+	})
+
+	t.Run("TestFileProviderJSON_NotFound", func(t *testing.T) {
+		_, err := p.Rate(t.Context(), "EUR", "JPY")
+		if !errors.Is(err, ErrRateNotFound) {
+			t.Errorf("Expected: %v, recieved: %v", ErrRateNotFound, err)
+		}
+	})
+}
+
+func TestFileProviderCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.csv")
+	writeFile(t, path, "# from,to,rate\nUSD,BHD,0.376\n")
+
+	p, err := NewFileProvider(path)
+	if err != nil {
+		t.Fatal("new provider err: ", err)
+	}
+
+	rate, err := p.Rate(t.Context(), "USD", "BHD")
+	if err != nil || rate.FloatString(3) != "0.376" {
+		t.Errorf("Expected: 0.376, recieved: %v, err: %v", rate, err)
+	}
+}
+
+func TestFileProviderReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	writeFile(t, path, `{"USD": {"EUR": "0.92"}}`)
+
+	p, err := NewFileProvider(path)
+	if err != nil {
+		t.Fatal("new provider err: ", err)
+	}
+
+	go p.Watch(t.Context(), 10*time.Millisecond)
+
+	writeFile(t, path, `{"USD": {"EUR": "0.95"}}`)
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		rate, err := p.Rate(t.Context(), "USD", "EUR")
+		if err == nil && rate.FloatString(2) == "0.95" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("rates file was not reloaded")
+}
+
+func TestFileProviderInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	writeFile(t, path, `{"USD": {"EUR": "-1"}}`)
+
+	_, err := NewFileProvider(path)
+	if err == nil {
+		t.Error("Expected invalid rate to fail")
+	}
+}
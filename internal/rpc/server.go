@@ -0,0 +1,278 @@
+// Package rpc serves the wallet service over gRPC for internal services.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/ratelimit"
+	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/pkg/walletpb"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// requestIDKey is the metadata key identifying a call in the audit log, like the X-Request-Id header.
+const requestIDKey = "x-request-id"
+
+// scopes are the scopes required by every method.
+var scopes = map[string]string{
+	walletpb.WalletService_Create_FullMethodName:      auth.ScopeWalletsWrite,
+	walletpb.WalletService_Balance_FullMethodName:     auth.ScopeWalletsRead,
+	walletpb.WalletService_Transaction_FullMethodName: auth.ScopeWalletsWrite,
+	walletpb.WalletService_History_FullMethodName:     auth.ScopeWalletsRead,
+}
+
+// Server implements walletpb.WalletServiceServer with the wallet service.
+type Server struct {
+	walletpb.UnimplementedWalletServiceServer
+
+	walletService  service.Wallet
+	validator      *validator.Validate
+	authenticators []auth.Authenticator
+	signatures     *auth.Signatures
+	limiter        ratelimit.Limiter
+	clientLimit    ratelimit.Limit
+	walletLimit    ratelimit.Limit
+	grpc           *grpc.Server
+}
+
+// Option configures the server.
+type Option func(*Server)
+
+// WithAuthenticator requires calls to be authenticated by a, reading the credentials from the
+// call metadata as if they were request headers. Authenticators are tried in the order they were
+// added until one recognizes the credentials. Without authenticators every call is allowed.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(s *Server) {
+		s.authenticators = append(s.authenticators, a)
+	}
+}
+
+// WithSignatures requires transactions of principals not restricted to an owner to be signed with
+// a secret shared with the partner, as on the REST API. The signature is sent in the x-client-id,
+// x-timestamp, x-nonce and x-signature metadata and covers the POST method, the full method name
+// as the request URI and the deterministic protobuf encoding of the request as the body.
+func WithSignatures(sig *auth.Signatures) Option {
+	return func(s *Server) {
+		s.signatures = sig
+	}
+}
+
+// WithRateLimits throttles every client to the client limit and transactions on every wallet
+// to the wallet limit, counting calls in l.
+func WithRateLimits(l ratelimit.Limiter, client ratelimit.Limit, wallet ratelimit.Limit) Option {
+	return func(s *Server) {
+		s.limiter = l
+		s.clientLimit = client
+		s.walletLimit = wallet
+	}
+}
+
+// New returns a gRPC server exposing ws.
+func New(ws service.Wallet, opts ...Option) *Server {
+	s := &Server{
+		walletService: ws,
+		validator:     validator.New(validator.WithRequiredStructEnabled()),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor),
+	)
+	walletpb.RegisterWalletServiceServer(s.grpc, s)
+	return s
+}
+
+// Serve accepts connections on l until the server is shut down.
+func (s *Server) Serve(l net.Listener) error {
+	return s.grpc.Serve(l)
+}
+
+// Shutdown stops accepting calls and waits for the calls in progress to complete.
+// Calls still in progress when ctx is done are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.intercept(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if in, ok := req.(*walletpb.TransactionRequest); ok {
+		err = s.verifySignature(ctx, info.FullMethod, in)
+		if err != nil {
+			return nil, err
+		}
+		if id, err := uuid.Parse(in.GetWalletId()); err == nil {
+			err = s.allow(ctx, "wallet:"+id.String(), s.walletLimit)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.intercept(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// serverStream overrides the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// intercept authenticates the call to method, checks the scope it requires and throttles the client.
+// The returned context carries the principal and identifies the call in the audit log by its x-request-id metadata.
+func (s *Server) intercept(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	info := service.RequestInfo{RequestID: uuid.NewString()}
+	if ids := md.Get(requestIDKey); len(ids) > 0 && ids[0] != "" {
+		info.RequestID = ids[0]
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		info.SourceIP, _, _ = net.SplitHostPort(p.Addr.String())
+	}
+	ctx = service.WithRequestInfo(ctx, info)
+
+	ctx, err := s.authenticate(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "client:" + info.SourceIP
+	if p, ok := auth.FromContext(ctx); ok && p.ID != "" {
+		key = "client:" + p.ID
+	}
+	err = s.allow(ctx, key, s.clientLimit)
+	if err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// authenticate attaches the principal of the call to method to ctx and checks the scope the method requires.
+func (s *Server) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if len(s.authenticators) == 0 {
+		return ctx, nil
+	}
+
+	r, err := metadataRequest(ctx, method)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	for _, a := range s.authenticators {
+		p, err := a.Authenticate(ctx, r)
+		if errors.Is(err, auth.ErrNoCredentials) {
+			continue
+		}
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if err != nil {
+			log.Println("grpc authenticate err: ", err)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		if scope := scopes[method]; !p.HasScope(scope) {
+			return nil, status.Error(codes.PermissionDenied, "missing scope "+scope)
+		}
+		return auth.WithPrincipal(ctx, p), nil
+	}
+	return nil, status.Error(codes.Unauthenticated, auth.ErrNoCredentials.Error())
+}
+
+// verifySignature checks the signature of a transaction. Like on the REST API, transactions of
+// principals restricted to an owner are not signed.
+func (s *Server) verifySignature(ctx context.Context, method string, req proto.Message) error {
+	if s.signatures == nil {
+		return nil
+	}
+	if p, _ := auth.FromContext(ctx); p.Owner != "" {
+		return nil
+	}
+
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return status.Error(codes.Internal, "internal server error")
+	}
+	r, err := metadataRequest(ctx, method)
+	if err != nil {
+		return status.Error(codes.Internal, "internal server error")
+	}
+	err = s.signatures.Verify(r, body)
+	if err != nil {
+		return withReason(codes.Unauthenticated, "INVALID_SIGNATURE", err.Error())
+	}
+	return nil
+}
+
+// allow takes a token from the bucket of key. Calls are let through if the limiter fails,
+// so that an unavailable limiter store does not take the API down.
+func (s *Server) allow(ctx context.Context, key string, l ratelimit.Limit) error {
+	if s.limiter == nil {
+		return nil
+	}
+	res, err := s.limiter.Allow(ctx, key, l)
+	if err != nil {
+		log.Println("grpc rate limiter err: ", err)
+		return nil
+	}
+	if !res.Allowed {
+		return withReason(codes.ResourceExhausted, "RATE_LIMITED", "rate limit exceeded")
+	}
+	return nil
+}
+
+// metadataRequest returns a request to method carrying the metadata of the call as headers,
+// for authenticators and signatures written for HTTP requests.
+func metadataRequest(ctx context.Context, method string) (*http.Request, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, method, nil)
+	if err != nil {
+		return nil, err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, vs := range md {
+		for _, v := range vs {
+			r.Header.Add(k, v)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,430 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/ratelimit"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/pkg/money"
+	"cmd/app/main.go/pkg/walletpb"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeKeys authenticates the API keys it holds.
+type fakeKeys map[string]auth.Principal
+
+func (k fakeKeys) Authenticate(ctx context.Context, r *http.Request) (auth.Principal, error) {
+	key := r.Header.Get(auth.APIKeyHeader)
+	if key == "" {
+		return auth.Principal{}, auth.ErrNoCredentials
+	}
+	p, ok := k[key]
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+	return p, nil
+}
+
+// dial serves s in process and returns a client connected to it.
+func dial(t *testing.T, s *Server) walletpb.WalletServiceClient {
+	l := bufconn.Listen(1 << 20)
+	go s.Serve(l)
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return l.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal("dial err: ", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return walletpb.NewWalletServiceClient(conn)
+}
+
+func checkCode(t *testing.T, err error, code codes.Code, reason string) {
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Errorf("Expected: %v, recieved: %v", code, st.Code())
+	}
+	if reason == "" {
+		return
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Reason == reason {
+			return
+		}
+	}
+	t.Errorf("Expected: %v, recieved: %v", reason, st.Details())
+}
+
+func TestServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+	client := dial(t, New(fakeService))
+
+	t.Run("TestServer_Balance", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{
+			UUID: fakeUUID, Balance: money.MustParse("12.5"), Available: money.MustParse("10"), Currency: "USD", Status: model.WalletActive,
+		}, nil)
+
+		res, err := client.Balance(t.Context(), &walletpb.BalanceRequest{WalletId: fakeUUID.String()})
+		if err != nil {
+			t.Fatal("balance err: ", err)
+		}
+		if res.GetWalletId() != fakeUUID.String() || res.GetBalance() != "12.5" || res.GetAvailable() != "10" {
+			t.Errorf("Expected: %v, recieved: %v", fakeUUID, res)
+		}
+	})
+
+	t.Run("TestServer_BalanceNotFound", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{}, pgx.ErrNoRows)
+
+		_, err := client.Balance(t.Context(), &walletpb.BalanceRequest{WalletId: fakeUUID.String()})
+		checkCode(t, err, codes.NotFound, "")
+	})
+
+	t.Run("TestServer_BalanceIncorrectUUID", func(t *testing.T) {
+		_, err := client.Balance(t.Context(), &walletpb.BalanceRequest{WalletId: "1234"})
+		checkCode(t, err, codes.InvalidArgument, "")
+	})
+
+	t.Run("TestServer_Transaction", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10.25")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, Balance: money.MustParse("10.25")}, nil)
+
+		res, err := client.Transaction(t.Context(), &walletpb.TransactionRequest{
+			WalletId:      fakeUUID.String(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+			Amount:        "10.25",
+		})
+		if err != nil {
+			t.Fatal("transaction err: ", err)
+		}
+		if res.GetBalance() != "10.25" {
+			t.Errorf("Expected: %v, recieved: %v", "10.25", res.GetBalance())
+		}
+	})
+
+	t.Run("TestServer_TransactionInsufficientFunds", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrInsufficientFunds)
+
+		_, err := client.Transaction(t.Context(), &walletpb.TransactionRequest{
+			WalletId:      fakeUUID.String(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_WITHDRAW,
+			Amount:        "10",
+		})
+		checkCode(t, err, codes.FailedPrecondition, "INSUFFICIENT_FUNDS")
+	})
+
+	t.Run("TestServer_TransactionLimitExceeded", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, &service.LimitError{Wallet: fakeUUID, Limit: model.LimitDailyDebit})
+
+		_, err := client.Transaction(t.Context(), &walletpb.TransactionRequest{
+			WalletId:      fakeUUID.String(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_WITHDRAW,
+			Amount:        "10",
+		})
+		checkCode(t, err, codes.FailedPrecondition, "DAILY_LIMIT_EXCEEDED")
+	})
+
+	t.Run("TestServer_TransactionInvalid", func(t *testing.T) {
+		invalid := map[string]*walletpb.TransactionRequest{
+			"Amount":   {WalletId: uuid.NewString(), OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT, Amount: "ten"},
+			"Negative": {WalletId: uuid.NewString(), OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT, Amount: "-1"},
+			"Type":     {WalletId: uuid.NewString(), Amount: "1"},
+			"Opening":  {WalletId: uuid.NewString(), OperationType: walletpb.OperationType_OPERATION_TYPE_OPENING, Amount: "1"},
+			"NoTarget": {WalletId: uuid.NewString(), OperationType: walletpb.OperationType_OPERATION_TYPE_TRANSFER, Amount: "1"},
+		}
+		for name, req := range invalid {
+			t.Run(name, func(t *testing.T) {
+				_, err := client.Transaction(t.Context(), req)
+				checkCode(t, err, codes.InvalidArgument, "")
+			})
+		}
+	})
+
+	t.Run("TestServer_TransactionFieldViolations", func(t *testing.T) {
+		_, err := client.Transaction(t.Context(), &walletpb.TransactionRequest{
+			WalletId:      uuid.NewString(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+			Amount:        "-1",
+		})
+		checkCode(t, err, codes.InvalidArgument, service.CodeValidationFailed)
+
+		st := status.Convert(err)
+		if st.Message() != "validation err: amount must be greater than 0" {
+			t.Errorf("Expected: %v, recieved: %v", "validation err: amount must be greater than 0", st.Message())
+		}
+		var violations []*errdetails.BadRequest_FieldViolation
+		for _, d := range st.Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok {
+				violations = br.GetFieldViolations()
+			}
+		}
+		if len(violations) != 1 || violations[0].GetField() != "amount" || violations[0].GetReason() != "gt" || violations[0].GetDescription() != "must be greater than 0" {
+			t.Errorf("Expected: %v, recieved: %v", "amount gt violation", violations)
+		}
+	})
+
+	t.Run("TestServer_History", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		now := time.Now().UTC()
+		entry := func(id int64) model.Transaction {
+			return model.Transaction{ID: id, OperationID: uuid.New(), UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("1"), CreatedAt: now}
+		}
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+		fakeService.EXPECT().Transactions(gomock.Any(), dto.TransactionHistoryRequest{UUID: fakeUUID, Limit: 3}).Return(dto.TransactionHistoryResponse{
+			Transactions: []model.Transaction{entry(5), entry(4)},
+			NextCursor:   "next",
+		}, nil)
+		fakeService.EXPECT().Transactions(gomock.Any(), dto.TransactionHistoryRequest{UUID: fakeUUID, Limit: 1, Cursor: "next"}).Return(dto.TransactionHistoryResponse{
+			Transactions: []model.Transaction{entry(3)},
+			NextCursor:   "more",
+		}, nil)
+
+		stream, err := client.History(t.Context(), &walletpb.HistoryRequest{WalletId: fakeUUID.String(), Limit: 3})
+		if err != nil {
+			t.Fatal("history err: ", err)
+		}
+		var ids []int64
+		for {
+			e, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatal("receive err: ", err)
+			}
+			if e.GetOperationType() != walletpb.OperationType_OPERATION_TYPE_DEPOSIT || !e.GetCreatedAt().AsTime().Equal(now) {
+				t.Errorf("Expected: %v, recieved: %v", "deposit entry", e)
+			}
+			ids = append(ids, e.GetId())
+		}
+		if len(ids) != 3 || ids[0] != 5 || ids[2] != 3 {
+			t.Errorf("Expected: %v, recieved: %v", []int64{5, 4, 3}, ids)
+		}
+	})
+}
+
+func TestServerAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+	owner := "customer-1842"
+	keys := fakeKeys{
+		"reader":   {ID: "dashboard", Scopes: []string{auth.ScopeWalletsRead}},
+		"customer": {ID: owner, Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}, Owner: owner},
+	}
+	client := dial(t, New(fakeService, WithAuthenticator(keys)))
+	withKey := func(ctx context.Context, key string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+	}
+
+	t.Run("TestServerAuth_NoCredentials", func(t *testing.T) {
+		_, err := client.Balance(t.Context(), &walletpb.BalanceRequest{WalletId: uuid.NewString()})
+		checkCode(t, err, codes.Unauthenticated, "")
+	})
+
+	t.Run("TestServerAuth_InvalidKey", func(t *testing.T) {
+		_, err := client.Balance(withKey(t.Context(), "guess"), &walletpb.BalanceRequest{WalletId: uuid.NewString()})
+		checkCode(t, err, codes.Unauthenticated, "")
+	})
+
+	t.Run("TestServerAuth_MissingScope", func(t *testing.T) {
+		_, err := client.Create(withKey(t.Context(), "reader"), &walletpb.CreateRequest{})
+		checkCode(t, err, codes.PermissionDenied, "")
+	})
+
+	t.Run("TestServerAuth_Owner", func(t *testing.T) {
+		fakeService.EXPECT().Create(gomock.Any(), dto.WalletCreateRequest{OwnerID: owner}).Return(model.Wallet{UUID: uuid.New(), OwnerID: &owner}, nil)
+
+		res, err := client.Create(withKey(t.Context(), "customer"), &walletpb.CreateRequest{})
+		if err != nil {
+			t.Fatal("create err: ", err)
+		}
+		if res.GetOwnerId() != owner {
+			t.Errorf("Expected: %v, recieved: %v", owner, res.GetOwnerId())
+		}
+	})
+
+	t.Run("TestServerAuth_OtherOwner", func(t *testing.T) {
+		other := "customer-1"
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &other}, nil)
+
+		_, err := client.Transaction(withKey(t.Context(), "customer"), &walletpb.TransactionRequest{
+			WalletId:      fakeUUID.String(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_WITHDRAW,
+			Amount:        "10",
+		})
+		checkCode(t, err, codes.NotFound, "")
+	})
+}
+
+func TestServerSignatures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+	owner := "customer-1842"
+	secret := []byte("partner-secret")
+	keys := fakeKeys{
+		"partner":  {ID: "partner", Scopes: []string{auth.ScopeWalletsWrite}},
+		"customer": {ID: owner, Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}, Owner: owner},
+	}
+	sig := auth.NewSignatures(map[string]string{"partner": string(secret)}, time.Minute)
+	client := dial(t, New(fakeService, WithAuthenticator(keys), WithSignatures(sig)))
+	withKey := func(ctx context.Context, key string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+	}
+	signed := func(t *testing.T, req *walletpb.TransactionRequest) context.Context {
+		body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+		if err != nil {
+			t.Fatal("marshal err: ", err)
+		}
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		nonce := uuid.NewString()
+		return metadata.AppendToOutgoingContext(withKey(t.Context(), "partner"),
+			"x-client-id", "partner",
+			"x-timestamp", ts,
+			"x-nonce", nonce,
+			"x-signature", auth.SignRequest(secret, http.MethodPost, walletpb.WalletService_Transaction_FullMethodName, ts, nonce, body),
+		)
+	}
+
+	t.Run("TestServerSignatures_Missing", func(t *testing.T) {
+		_, err := client.Transaction(withKey(t.Context(), "partner"), &walletpb.TransactionRequest{
+			WalletId:      uuid.NewString(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+			Amount:        "10",
+		})
+		checkCode(t, err, codes.Unauthenticated, "INVALID_SIGNATURE")
+	})
+
+	t.Run("TestServerSignatures_Tampered", func(t *testing.T) {
+		req := &walletpb.TransactionRequest{
+			WalletId:      uuid.NewString(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+			Amount:        "10",
+		}
+		ctx := signed(t, req)
+		req.Amount = "1000"
+
+		_, err := client.Transaction(ctx, req)
+		checkCode(t, err, codes.Unauthenticated, "INVALID_SIGNATURE")
+	})
+
+	t.Run("TestServerSignatures_Signed", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, Balance: money.MustParse("10")}, nil)
+
+		req := &walletpb.TransactionRequest{
+			WalletId:      fakeUUID.String(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+			Amount:        "10",
+		}
+		_, err := client.Transaction(signed(t, req), req)
+		if err != nil {
+			t.Fatal("transaction err: ", err)
+		}
+	})
+
+	t.Run("TestServerSignatures_EndUser", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, Balance: money.MustParse("10")}, nil)
+
+		_, err := client.Transaction(withKey(t.Context(), "customer"), &walletpb.TransactionRequest{
+			WalletId:      fakeUUID.String(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+			Amount:        "10",
+		})
+		if err != nil {
+			t.Fatal("transaction err: ", err)
+		}
+	})
+}
+
+func TestServerRateLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+	keys := fakeKeys{
+		"first":  {ID: "first", Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}},
+		"second": {ID: "second", Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}},
+		"third":  {ID: "third", Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}},
+	}
+	limits := WithRateLimits(ratelimit.NewMemory(), ratelimit.Limit{Rate: 0.001, Burst: 1}, ratelimit.Limit{Rate: 0.001, Burst: 1})
+	client := dial(t, New(fakeService, WithAuthenticator(keys), limits))
+	withKey := func(ctx context.Context, key string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+	}
+
+	t.Run("TestServerRateLimits_Client", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		_, err := client.Balance(withKey(t.Context(), "first"), &walletpb.BalanceRequest{WalletId: fakeUUID.String()})
+		if err != nil {
+			t.Fatal("balance err: ", err)
+		}
+		_, err = client.Balance(withKey(t.Context(), "first"), &walletpb.BalanceRequest{WalletId: fakeUUID.String()})
+		checkCode(t, err, codes.ResourceExhausted, "RATE_LIMITED")
+	})
+
+	t.Run("TestServerRateLimits_Wallet", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, Balance: money.MustParse("10")}, nil)
+
+		req := &walletpb.TransactionRequest{
+			WalletId:      fakeUUID.String(),
+			OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+			Amount:        "10",
+		}
+		_, err := client.Transaction(withKey(t.Context(), "second"), req)
+		if err != nil {
+			t.Fatal("transaction err: ", err)
+		}
+		_, err = client.Transaction(withKey(t.Context(), "third"), req)
+		checkCode(t, err, codes.ResourceExhausted, "RATE_LIMITED")
+	})
+}
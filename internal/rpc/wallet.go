@@ -0,0 +1,303 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/pkg/money"
+	"cmd/app/main.go/pkg/walletpb"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// historyPageSize is the number of ledger entries read at a time while streaming the history.
+const historyPageSize = 100
+
+// errorDomain is the domain of the error details attached to failed calls.
+const errorDomain = "wallet"
+
+// errorCodes are the status codes of the kinds of service errors. The reason attached
+// to failed calls is the code of the error, the same as in the REST API.
+var errorCodes = map[service.Kind]codes.Code{
+	service.KindInternal:          codes.Internal,
+	service.KindNotFound:          codes.NotFound,
+	service.KindValidation:        codes.InvalidArgument,
+	service.KindInsufficientFunds: codes.FailedPrecondition,
+	service.KindFrozen:            codes.FailedPrecondition,
+	service.KindLimitExceeded:     codes.FailedPrecondition,
+	service.KindConflict:          codes.FailedPrecondition,
+	service.KindUnprocessable:     codes.FailedPrecondition,
+	service.KindUnauthenticated:   codes.Unauthenticated,
+	service.KindForbidden:         codes.PermissionDenied,
+	service.KindRateLimited:       codes.ResourceExhausted,
+	service.KindUnavailable:       codes.Unavailable,
+}
+
+// operationTypes maps the operation types of the API to those of the service.
+var operationTypes = map[walletpb.OperationType]string{
+	walletpb.OperationType_OPERATION_TYPE_OPENING:  "OPENING",
+	walletpb.OperationType_OPERATION_TYPE_DEPOSIT:  "DEPOSIT",
+	walletpb.OperationType_OPERATION_TYPE_WITHDRAW: "WITHDRAW",
+	walletpb.OperationType_OPERATION_TYPE_TRANSFER: "TRANSFER",
+	walletpb.OperationType_OPERATION_TYPE_CAPTURE:  "CAPTURE",
+	walletpb.OperationType_OPERATION_TYPE_REVERSAL: "REVERSAL",
+}
+
+// Create creates a wallet. Wallets created by principals restricted to an owner belong to that owner.
+func (s *Server) Create(ctx context.Context, in *walletpb.CreateRequest) (*walletpb.Wallet, error) {
+	req := dto.WalletCreateRequest{
+		Currency: in.GetCurrency(),
+		OwnerID:  in.GetOwnerId(),
+	}
+	err := s.validator.Struct(req)
+	if err != nil {
+		return nil, validationErr(req, err)
+	}
+
+	p, _ := auth.FromContext(ctx)
+	if p.Owner != "" {
+		if req.OwnerID != "" && req.OwnerID != p.Owner {
+			return nil, status.Error(codes.PermissionDenied, "cannot create wallets for another owner")
+		}
+		req.OwnerID = p.Owner
+	}
+
+	res, err := s.walletService.Create(ctx, req)
+	if err != nil {
+		return nil, statusErr(err)
+	}
+	return toWallet(res), nil
+}
+
+// Balance returns a wallet with its balances.
+func (s *Server) Balance(ctx context.Context, in *walletpb.BalanceRequest) (*walletpb.Wallet, error) {
+	id, err := uuid.Parse(in.GetWalletId())
+	if err != nil {
+		return nil, invalidErr("incorrect wallet uuid", malformed("walletId"))
+	}
+	res, err := s.wallet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toWallet(res), nil
+}
+
+// Transaction deposits to, withdraws from or transfers between wallets and returns the source wallet.
+func (s *Server) Transaction(ctx context.Context, in *walletpb.TransactionRequest) (*walletpb.Wallet, error) {
+	id, err := uuid.Parse(in.GetWalletId())
+	if err != nil {
+		return nil, invalidErr("incorrect wallet uuid", malformed("walletId"))
+	}
+	amount, err := money.Parse(in.GetAmount())
+	if err != nil {
+		return nil, invalidErr("incorrect amount", malformed("amount"))
+	}
+	req := dto.WalletTransactionRequest{
+		UUID:     id,
+		Type:     operationTypes[in.GetOperationType()],
+		Amount:   amount,
+		Currency: in.GetCurrency(),
+	}
+	if in.GetToWalletId() != "" {
+		req.ToUUID, err = uuid.Parse(in.GetToWalletId())
+		if err != nil {
+			return nil, invalidErr("incorrect destination wallet uuid", malformed("toWalletId"))
+		}
+	}
+	err = s.validator.Struct(req)
+	if err != nil {
+		return nil, validationErr(req, err)
+	}
+	if req.Type == "TRANSFER" && req.ToUUID == req.UUID {
+		return nil, invalidErr("cannot transfer to the source wallet",
+			service.FieldError{Field: "toWalletId", Code: "nefield", Message: "must differ from walletId"})
+	}
+
+	if p, _ := auth.FromContext(ctx); p.Owner != "" {
+		_, err = s.wallet(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := s.walletService.Transaction(ctx, req)
+	if err != nil {
+		return nil, statusErr(err)
+	}
+	return toWallet(res), nil
+}
+
+// History streams the ledger entries of a wallet, newest first, reading them a page at a time.
+func (s *Server) History(in *walletpb.HistoryRequest, stream grpc.ServerStreamingServer[walletpb.LedgerEntry]) error {
+	ctx := stream.Context()
+	id, err := uuid.Parse(in.GetWalletId())
+	if err != nil {
+		return invalidErr("incorrect wallet uuid", malformed("walletId"))
+	}
+	if in.GetLimit() < 0 {
+		return invalidErr("incorrect limit", service.FieldError{Field: "limit", Code: "min", Message: "must be at least 0"})
+	}
+	req := dto.TransactionHistoryRequest{
+		UUID:  id,
+		Limit: historyPageSize,
+		Type:  operationTypes[in.GetOperationType()],
+	}
+	if in.From != nil {
+		req.From = in.GetFrom().AsTime()
+	}
+	if in.To != nil {
+		req.To = in.GetTo().AsTime()
+	}
+	err = s.validator.Struct(req)
+	if err != nil {
+		return validationErr(req, err)
+	}
+
+	_, err = s.wallet(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	left := int(in.GetLimit())
+	for {
+		if left > 0 {
+			req.Limit = min(left, historyPageSize)
+		}
+		res, err := s.walletService.Transactions(ctx, req)
+		if err != nil {
+			return statusErr(err)
+		}
+		for _, t := range res.Transactions {
+			err = stream.Send(toLedgerEntry(t))
+			if err != nil {
+				return err
+			}
+		}
+		if left > 0 {
+			left -= len(res.Transactions)
+			if left <= 0 {
+				return nil
+			}
+		}
+		if res.NextCursor == "" {
+			return nil
+		}
+		req.Cursor = res.NextCursor
+	}
+}
+
+// wallet reads a wallet, reporting wallets the principal may not access as not found
+// so that their existence is not disclosed.
+func (s *Server) wallet(ctx context.Context, id uuid.UUID) (model.Wallet, error) {
+	res, err := s.walletService.Balance(ctx, id)
+	if err != nil {
+		return res, statusErr(err)
+	}
+	p, _ := auth.FromContext(ctx)
+	if !p.CanAccess(res.OwnerID) {
+		return res, statusErr(service.ErrWalletNotFound)
+	}
+	return res, nil
+}
+
+// statusErr maps an error of the wallet service to a status carrying the error code as the reason.
+// Unknown resources are reported as unknown wallets and unexpected errors are hidden.
+func statusErr(err error) error {
+	e := service.AsError(err, service.ErrWalletNotFound)
+	switch {
+	case e.Kind == service.KindInternal:
+		return withReason(codes.Internal, service.CodeInternal, "wallet service err")
+	case errors.Is(e, service.ErrWalletExists):
+		return withReason(codes.AlreadyExists, e.Code, e.Message)
+	}
+	return withReason(errorCodes[e.Kind], e.Code, e.Message)
+}
+
+// validationErr reports a request failing validation with err. The invalid fields are named and coded
+// like in the problem details of the REST API.
+func validationErr(req any, err error) error {
+	fields := service.FieldErrors(req, err)
+	msgs := make([]string, 0, len(fields))
+	for _, f := range fields {
+		msgs = append(msgs, strings.TrimSpace(f.Field+" "+f.Message))
+	}
+	return invalidErr("validation err: "+strings.Join(msgs, "; "), fields...)
+}
+
+// malformed describes a field that cannot be parsed.
+func malformed(field string) service.FieldError {
+	return service.FieldError{Field: field, Code: "format", Message: "is malformed"}
+}
+
+// invalidErr reports an invalid argument of a call, listing the invalid fields as the field violations
+// of a BadRequest detail.
+func invalidErr(message string, fields ...service.FieldError) error {
+	if len(fields) == 0 {
+		return withReason(codes.InvalidArgument, service.CodeValidationFailed, message)
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+	for _, f := range fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: f.Field, Reason: f.Code, Description: f.Message})
+	}
+	return withReason(codes.InvalidArgument, service.CodeValidationFailed, message, &errdetails.BadRequest{FieldViolations: violations})
+}
+
+// withReason returns a status with the code and message, carrying the reason in an ErrorInfo detail
+// followed by the extra details.
+func withReason(code codes.Code, reason string, message string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, message)
+	if reason == "" {
+		return st.Err()
+	}
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}}, details...)
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func toWallet(w model.Wallet) *walletpb.Wallet {
+	return &walletpb.Wallet{
+		WalletId:  w.UUID.String(),
+		Balance:   w.Balance.String(),
+		Available: w.Available.String(),
+		Currency:  w.Currency,
+		Status:    w.Status,
+		OwnerId:   w.OwnerID,
+	}
+}
+
+func toLedgerEntry(t model.Transaction) *walletpb.LedgerEntry {
+	e := &walletpb.LedgerEntry{
+		Id:              t.ID,
+		OperationId:     t.OperationID.String(),
+		WalletId:        t.UUID.String(),
+		OperationType:   walletpb.OperationType(walletpb.OperationType_value["OPERATION_TYPE_"+strings.ToUpper(t.Type)]),
+		Amount:          t.Amount.String(),
+		Balance:         t.Balance.String(),
+		CounterCurrency: t.CounterCurrency,
+		Rate:            t.Rate,
+		CreatedAt:       timestamppb.New(t.CreatedAt),
+	}
+	if t.Counterparty != nil {
+		id := t.Counterparty.String()
+		e.CounterpartyWalletId = &id
+	}
+	if t.CounterAmount != nil {
+		amount := t.CounterAmount.String()
+		e.CounterAmount = &amount
+	}
+	return e
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// RequestInfo identifies the request behind a change in the audit log.
+type RequestInfo struct {
+	SourceIP  string
+	RequestID string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying info, which is recorded with the changes made using it.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info carried by ctx, if any.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// WithAudit records every state change in the audit log, in the same database transaction as the change.
+func WithAudit() Option {
+	return func(ws *wallet) {
+		ws.audit = true
+	}
+}
+
+// auditEntry is what a change reports about itself to the audit log.
+// The wallet is left nil for changes not tied to a wallet.
+type auditEntry struct {
+	action string
+	wallet uuid.UUID
+	before any
+	after  any
+}
+
+// audited runs change and, when the audit log is enabled, records the entry it reports in the same
+// database transaction, so that a change is never committed without its audit record. Failed changes
+// are not recorded.
+func (ws *wallet) audited(ctx context.Context, change func(ctx context.Context) (auditEntry, error)) error {
+	if !ws.audit {
+		_, err := change(ctx)
+		return err
+	}
+	return ws.storage.WithTx(ctx, func(ctx context.Context) error {
+		e, err := change(ctx)
+		if err != nil {
+			return err
+		}
+		r, err := auditRecord(ctx, e)
+		if err != nil {
+			return err
+		}
+		return ws.storage.RecordAudit(ctx, r)
+	})
+}
+
+// auditRecord builds the audit record of e, attributing it to the principal and request carried by ctx.
+func auditRecord(ctx context.Context, e auditEntry) (model.AuditRecord, error) {
+	r := model.AuditRecord{
+		Action: e.action,
+	}
+	if e.wallet != uuid.Nil {
+		r.UUID = &e.wallet
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		r.Principal = p.ID
+		r.PrincipalName = p.Name
+	}
+	if info, ok := RequestInfoFromContext(ctx); ok {
+		r.SourceIP = info.SourceIP
+		r.RequestID = info.RequestID
+	}
+
+	var err error
+	if e.before != nil {
+		r.Before, err = json.Marshal(e.before)
+		if err != nil {
+			return r, err
+		}
+	}
+	if e.after != nil {
+		r.After, err = json.Marshal(e.after)
+		if err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// AuditLog returns a page of the audit records matching the request, newest first.
+func (ws *wallet) AuditLog(ctx context.Context, req dto.AuditLogRequest) (dto.AuditLogResponse, error) {
+	var res dto.AuditLogResponse
+
+	beforeID, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return res, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+
+	filter := db.AuditFilter{
+		UUID:      req.UUID,
+		Action:    req.Action,
+		Principal: req.Principal,
+		BeforeID:  beforeID,
+		From:      req.From,
+		To:        req.To,
+		Limit:     limit + 1,
+	}
+	items, err := ws.storage.AuditLog(ctx, filter)
+	if err != nil {
+		log.Println("wallet service audit log err: ", err)
+		return res, err
+	}
+	if items == nil {
+		items = []model.AuditRecord{}
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+		res.NextCursor = encodeCursor(items[limit-1].ID)
+	}
+	res.Records = items
+	return res, nil
+}
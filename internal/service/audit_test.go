@@ -0,0 +1,208 @@
+package service
+
+import (
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/db"
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestWalletServiceAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB, WithAudit())
+
+	runTx := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}
+	requestCtx := func(t *testing.T) context.Context {
+		ctx := auth.WithPrincipal(t.Context(), auth.Principal{ID: "key-1", Name: "backoffice"})
+		return WithRequestInfo(ctx, RequestInfo{SourceIP: "10.0.0.7", RequestID: "req-1"})
+	}
+	marshal := func(t *testing.T, v any) json.RawMessage {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal("marshal err: ", err)
+		}
+		return b
+	}
+
+	t.Run("TestWalletServiceAudit_Deposit", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("30")}
+		before := model.Wallet{UUID: fakeUUID, Balance: money.MustParse("70"), Available: money.MustParse("50"), Currency: "USD", Status: model.WalletActive}
+		after := model.Wallet{UUID: fakeUUID, Balance: money.MustParse("100"), Available: money.MustParse("80"), Currency: "USD", Status: model.WalletActive}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(before, nil)
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().Deposit(gomock.Any(), fakeUUID, fakeReq.Amount).Return(after, nil)
+		fakeDB.EXPECT().RecordAudit(gomock.Any(), model.AuditRecord{
+			Action:        model.AuditDeposit,
+			UUID:          &fakeUUID,
+			Principal:     "key-1",
+			PrincipalName: "backoffice",
+			SourceIP:      "10.0.0.7",
+			RequestID:     "req-1",
+			Before:        marshal(t, before),
+			After:         marshal(t, after),
+		}).Return(nil)
+
+		_, err := ws.Transaction(requestCtx(t), fakeReq)
+		if err != nil {
+			t.Error("transaction err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceAudit_DeleteWebhook", func(t *testing.T) {
+		fakeID := uuid.New()
+		before := model.Webhook{ID: fakeID, URL: "https://example.com/hooks", Secret: "whsec", EventTypes: []string{model.EventWalletCredited}}
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().DeleteWebhook(gomock.Any(), fakeID).Return(before, nil)
+		fakeDB.EXPECT().RecordAudit(gomock.Any(), model.AuditRecord{
+			Action:        model.AuditWebhookDelete,
+			Principal:     "key-1",
+			PrincipalName: "backoffice",
+			SourceIP:      "10.0.0.7",
+			RequestID:     "req-1",
+			Before:        marshal(t, before),
+		}).Return(nil)
+
+		err := ws.DeleteWebhook(requestCtx(t), fakeID)
+		if err != nil {
+			t.Error("delete webhook err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceAudit_Redeliver", func(t *testing.T) {
+		fakeID := uuid.New()
+		after := model.WebhookDelivery{ID: fakeID, WebhookID: uuid.New(), Status: "PENDING"}
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().Redeliver(gomock.Any(), fakeID).Return(after, nil)
+		fakeDB.EXPECT().RecordAudit(gomock.Any(), model.AuditRecord{
+			Action:        model.AuditRedeliver,
+			Principal:     "key-1",
+			PrincipalName: "backoffice",
+			SourceIP:      "10.0.0.7",
+			RequestID:     "req-1",
+			After:         marshal(t, after),
+		}).Return(nil)
+
+		_, err := ws.Redeliver(requestCtx(t), fakeID)
+		if err != nil {
+			t.Error("redeliver err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceAudit_Create", func(t *testing.T) {
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().Create(gomock.Any(), gomock.Any(), false).Return(nil)
+		var recorded model.AuditRecord
+		fakeDB.EXPECT().RecordAudit(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, r model.AuditRecord) error {
+			recorded = r
+			return nil
+		})
+
+		w, err := ws.Create(requestCtx(t), dto.WalletCreateRequest{Currency: "EUR"})
+		if err != nil {
+			t.Error("create err: ", err)
+		}
+		if recorded.Action != model.AuditWalletCreate || recorded.UUID == nil || *recorded.UUID != w.UUID {
+			t.Errorf("Expected: %v %v, recieved: %v %v", model.AuditWalletCreate, w.UUID, recorded.Action, recorded.UUID)
+		}
+		if recorded.Before != nil || !reflect.DeepEqual(recorded.After, marshal(t, w)) {
+			t.Errorf("Expected: %s -> %s, recieved: %s -> %s", "null", marshal(t, w), recorded.Before, recorded.After)
+		}
+	})
+
+	t.Run("TestWalletServiceAudit_Freeze", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		before := model.Wallet{UUID: fakeUUID, Status: model.WalletActive}
+		after := model.Wallet{UUID: fakeUUID, Status: model.WalletFrozen}
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().SetStatus(gomock.Any(), fakeUUID, []string{model.WalletActive}, model.WalletFrozen, "fraud report").Return(before, after, nil)
+		fakeDB.EXPECT().RecordAudit(gomock.Any(), model.AuditRecord{
+			Action:        model.AuditWalletFreeze,
+			UUID:          &fakeUUID,
+			Principal:     "key-1",
+			PrincipalName: "backoffice",
+			SourceIP:      "10.0.0.7",
+			RequestID:     "req-1",
+			Before:        marshal(t, before),
+			After:         marshal(t, after),
+		}).Return(nil)
+
+		_, err := ws.SetStatus(requestCtx(t), dto.WalletStatusRequest{UUID: fakeUUID, Status: model.WalletFrozen, Reason: "fraud report"})
+		if err != nil {
+			t.Error("set status err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceAudit_FailedChange", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("30")}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD", Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().Withdraw(gomock.Any(), fakeUUID, fakeReq.Amount).Return(model.Wallet{}, db.ErrInsufficientFunds)
+
+		_, err := ws.Transaction(requestCtx(t), fakeReq)
+		if !errors.Is(err, ErrInsufficientFunds) {
+			t.Errorf("Expected: %v, recieved: %v", ErrInsufficientFunds, err)
+		}
+	})
+
+	t.Run("TestWalletServiceAudit_RecordFails", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletLimitsRequest{UUID: fakeUUID}
+		fakeErr := errors.New("random db err")
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().Limits(gomock.Any(), fakeUUID).Return(model.WalletLimits{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().SetLimits(gomock.Any(), model.WalletLimits{UUID: fakeUUID}).Return(model.WalletLimits{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().RecordAudit(gomock.Any(), gomock.Any()).Return(fakeErr)
+
+		_, err := ws.SetLimits(requestCtx(t), fakeReq)
+		if !errors.Is(err, fakeErr) {
+			t.Errorf("Expected: %v, recieved: %v", fakeErr, err)
+		}
+	})
+}
+
+func TestWalletServiceAuditLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceAuditLog_NextPage", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.AuditLogRequest{UUID: fakeUUID, Action: model.AuditDeposit, Limit: 2}
+		fakeRecords := []model.AuditRecord{{ID: 9}, {ID: 7}, {ID: 4}}
+		fakeDB.EXPECT().AuditLog(gomock.Any(), db.AuditFilter{UUID: fakeUUID, Action: model.AuditDeposit, Limit: 3}).Return(fakeRecords, nil)
+
+		res, err := ws.AuditLog(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("audit log err: ", err)
+		}
+		correct := dto.AuditLogResponse{Records: fakeRecords[:2], NextCursor: encodeCursor(7)}
+		if !reflect.DeepEqual(res, correct) {
+			t.Errorf("Expected: %v, recieved: %v", correct, res)
+		}
+	})
+
+	t.Run("TestWalletServiceAuditLog_InvalidCursor", func(t *testing.T) {
+		_, err := ws.AuditLog(t.Context(), dto.AuditLogRequest{Cursor: "!"})
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected: %v, recieved: %v", ErrInvalidCursor, err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+)
+
+// RateProvider supplies exchange rates used to convert transfers between currencies.
+type RateProvider interface {
+	// Rate returns how many units of currency to one unit of currency from is worth.
+	Rate(ctx context.Context, from string, to string) (*big.Rat, error)
+}
+
+var (
+	ErrRateUnavailable = newError(KindUnprocessable, "RATE_UNAVAILABLE", "exchange rate unavailable")
+	ErrAmountTooSmall  = newError(KindUnprocessable, "AMOUNT_TOO_SMALL", "amount is too small to convert")
+)
+
+// WithRateProvider enables transfers between wallets in different currencies,
+// converting amounts with rates from p.
+func WithRateProvider(p RateProvider) Option {
+	return func(ws *wallet) {
+		ws.rates = p
+	}
+}
+
+// WithRoundingMode sets how converted amounts are rounded to the destination currency precision.
+func WithRoundingMode(mode money.RoundingMode) Option {
+	return func(ws *wallet) {
+		ws.rounding = mode
+	}
+}
+
+// prepareTransfer builds the storage transfer for a request debiting wallet from.
+// Transfers within one currency credit the requested amount as is. Otherwise the amount
+// is converted with the rate from the rate provider, rounded to RateScale decimal places
+// beforehand, so that the recorded rate is exactly the applied one.
+// Without a rate provider, transfers between currencies fail with ErrCurrencyMismatch.
+func (ws *wallet) prepareTransfer(ctx context.Context, from model.Wallet, req dto.WalletTransactionRequest) (db.Transfer, error) {
+	t := db.Transfer{
+		From:   req.UUID,
+		To:     req.ToUUID,
+		Debit:  req.Amount,
+		Credit: req.Amount,
+	}
+
+	to, err := ws.storage.Balance(ctx, req.ToUUID)
+	if err != nil {
+		return t, err
+	}
+	err = ws.checkStatus(to, false)
+	if err != nil {
+		return t, err
+	}
+	if to.Currency == from.Currency {
+		return t, nil
+	}
+	if ws.rates == nil {
+		return t, ErrCurrencyMismatch
+	}
+
+	rate, err := ws.rates.Rate(ctx, from.Currency, to.Currency)
+	if err != nil {
+		return t, fmt.Errorf("%w: %v", ErrRateUnavailable, err)
+	}
+	rate = money.Round(rate, money.RateScale, money.HalfEven)
+	if rate.Sign() <= 0 {
+		return t, ErrRateUnavailable
+	}
+
+	t.Credit, err = money.Convert(req.Amount, rate, money.Decimals(to.Currency), ws.rounding)
+	if err != nil {
+		return t, err
+	}
+	if !t.Credit.IsPositive() {
+		return t, ErrAmountTooSmall
+	}
+	t.Rate = rate.FloatString(money.RateScale)
+	return t, nil
+}
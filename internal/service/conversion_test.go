@@ -0,0 +1,142 @@
+package service
+
+import (
+	"cmd/app/main.go/internal/db"
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+type fakeRates map[string]*big.Rat
+
+func (f fakeRates) Rate(ctx context.Context, from string, to string) (*big.Rat, error) {
+	rate, ok := f[from+to]
+	if !ok {
+		return nil, errors.New("no rate")
+	}
+	return rate, nil
+}
+
+func TestWalletServiceTransferConversion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	rates := fakeRates{
+		"USDEUR": big.NewRat(92, 100),
+		"USDJPY": new(big.Rat).SetFrac64(1498765432101, 10000000000),
+		"JPYUSD": big.NewRat(1, 100000),
+	}
+	ws := New(fakeDB, WithRateProvider(rates))
+
+	transfer := func(from, to string, amount string) (dto.WalletTransactionRequest, *gomock.Call) {
+		fakeUUID := uuid.New()
+		fakeToUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse(amount),
+			ToUUID: fakeToUUID,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: from}, nil)
+		call := fakeDB.EXPECT().Balance(gomock.Any(), fakeToUUID).Return(model.Wallet{UUID: fakeToUUID, Currency: to}, nil)
+		return fakeReq, call
+	}
+
+	t.Run("TestWalletServiceTransferConversion_SameCurrency", func(t *testing.T) {
+		fakeReq, _ := transfer("EUR", "EUR", "10")
+		fakeT := db.Transfer{From: fakeReq.UUID, To: fakeReq.ToUUID, Debit: fakeReq.Amount, Credit: fakeReq.Amount}
+		fakeDB.EXPECT().Transfer(gomock.Any(), fakeT).Return(model.Wallet{}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transfer err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransferConversion_Converted", func(t *testing.T) {
+		fakeReq, _ := transfer("USD", "EUR", "10.01")
+		fakeT := db.Transfer{
+			From:   fakeReq.UUID,
+			To:     fakeReq.ToUUID,
+			Debit:  money.MustParse("10.01"),
+			Credit: money.MustParse("9.21"),
+			Rate:   "0.9200000000",
+		}
+		fakeDB.EXPECT().Transfer(gomock.Any(), fakeT).Return(model.Wallet{}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transfer err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransferConversion_RateRounded", func(t *testing.T) {
+		fakeReq, _ := transfer("USD", "JPY", "1")
+		fakeT := db.Transfer{
+			From:   fakeReq.UUID,
+			To:     fakeReq.ToUUID,
+			Debit:  money.MustParse("1"),
+			Credit: money.MustParse("150"),
+			Rate:   "149.8765432101",
+		}
+		fakeDB.EXPECT().Transfer(gomock.Any(), fakeT).Return(model.Wallet{}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transfer err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransferConversion_RateUnavailable", func(t *testing.T) {
+		fakeReq, _ := transfer("EUR", "USD", "10")
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrRateUnavailable) {
+			t.Errorf("Expected: %v, recieved: %v", ErrRateUnavailable, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransferConversion_TooSmall", func(t *testing.T) {
+		fakeReq, _ := transfer("JPY", "USD", "1")
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrAmountTooSmall) {
+			t.Errorf("Expected: %v, recieved: %v", ErrAmountTooSmall, err)
+		}
+	})
+}
+
+func TestWalletServiceTransferRoundingMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB, WithRateProvider(fakeRates{"USDEUR": big.NewRat(92, 100)}), WithRoundingMode(money.Down))
+
+	t.Run("TestWalletServiceTransferRoundingMode_Down", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeToUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse("10.01"),
+			ToUUID: fakeToUUID,
+		}
+		fakeT := db.Transfer{
+			From:   fakeUUID,
+			To:     fakeToUUID,
+			Debit:  money.MustParse("10.01"),
+			Credit: money.MustParse("9.20"),
+			Rate:   "0.9200000000",
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeToUUID).Return(model.Wallet{UUID: fakeToUUID, Currency: "EUR"}, nil)
+		fakeDB.EXPECT().Transfer(gomock.Any(), fakeT).Return(model.Wallet{}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transfer err: ", err)
+		}
+	})
+}
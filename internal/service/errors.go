@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Kind classifies the errors of the wallet service by how clients can handle them.
+type Kind int
+
+const (
+	// KindInternal errors are unexpected failures clients can only retry.
+	KindInternal Kind = iota
+	// KindNotFound errors report unknown resources.
+	KindNotFound
+	// KindValidation errors report invalid requests, detailed per field when possible.
+	KindValidation
+	// KindInsufficientFunds errors report debits exceeding the available balance.
+	KindInsufficientFunds
+	// KindFrozen errors report operations refused because the wallet is frozen or closed.
+	KindFrozen
+	// KindLimitExceeded errors report operations exceeding a spending limit of the wallet.
+	KindLimitExceeded
+	// KindConflict errors report operations conflicting with the current state of a resource.
+	KindConflict
+	// KindUnprocessable errors report valid requests the operation cannot be applied to.
+	KindUnprocessable
+	// KindUnauthenticated errors report requests without valid credentials or signature.
+	KindUnauthenticated
+	// KindForbidden errors report requests the principal is not allowed to make.
+	KindForbidden
+	// KindRateLimited errors report requests refused because the client sends too many.
+	KindRateLimited
+	// KindUnavailable errors report features that are disabled or temporarily unavailable.
+	KindUnavailable
+)
+
+// Error is an error of the wallet service clients can handle. Code is a stable machine-readable
+// identifier of the error and Message its description, both safe to send to clients.
+// Validation errors may detail the invalid fields.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying error, such as pgx.ErrNoRows for unknown resources.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// WithMessage returns a copy of e with another message, which still matches e with errors.Is.
+func (e *Error) WithMessage(message string) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: message, Fields: e.Fields, err: e}
+}
+
+// FieldError describes an invalid field of a request. Code is the failed rule, such as required or max.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Codes of errors not tied to a single sentinel.
+const (
+	CodeInternal         = "INTERNAL"
+	CodeValidationFailed = "VALIDATION_FAILED"
+)
+
+func newError(kind Kind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Unknown resources. They wrap pgx.ErrNoRows, which the storage returns for them.
+var (
+	ErrWalletNotFound    = &Error{Kind: KindNotFound, Code: "WALLET_NOT_FOUND", Message: "wallet not found", err: pgx.ErrNoRows}
+	ErrHoldNotFound      = &Error{Kind: KindNotFound, Code: "HOLD_NOT_FOUND", Message: "hold not found", err: pgx.ErrNoRows}
+	ErrOperationNotFound = &Error{Kind: KindNotFound, Code: "OPERATION_NOT_FOUND", Message: "operation not found", err: pgx.ErrNoRows}
+	ErrWebhookNotFound   = &Error{Kind: KindNotFound, Code: "WEBHOOK_NOT_FOUND", Message: "webhook not found", err: pgx.ErrNoRows}
+	ErrDeliveryNotFound  = &Error{Kind: KindNotFound, Code: "DELIVERY_NOT_FOUND", Message: "delivery not found", err: pgx.ErrNoRows}
+)
+
+// Requests refused by the APIs before reaching the wallet service.
+var (
+	ErrUnauthenticated  = newError(KindUnauthenticated, "UNAUTHENTICATED", "authentication required")
+	ErrInvalidSignature = newError(KindUnauthenticated, "INVALID_SIGNATURE", "invalid signature")
+	ErrForbidden        = newError(KindForbidden, "FORBIDDEN", "forbidden")
+	ErrInvalidBody      = newError(KindValidation, "INVALID_BODY", "cannot read request body")
+	ErrRateLimited      = newError(KindRateLimited, "RATE_LIMITED", "rate limit exceeded")
+	ErrTooManyStreams   = newError(KindRateLimited, "TOO_MANY_STREAMS", "too many event streams")
+	ErrUnavailable      = newError(KindUnavailable, "UNAVAILABLE", "service unavailable")
+)
+
+// NewValidationError returns a validation error detailing the invalid fields.
+func NewValidationError(fields []FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidationFailed, Message: "request is invalid", Fields: fields}
+}
+
+// AsError returns the Error err is or wraps. Missing rows are reported as notFound, limit errors
+// with the code of the exceeded limit, and other errors as internal errors hiding their cause.
+func AsError(err error, notFound *Error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound
+	}
+	var le *LimitError
+	if errors.As(err, &le) {
+		return &Error{
+			Kind:    KindLimitExceeded,
+			Code:    limitCodes[le.Limit],
+			Message: fmt.Sprintf(limitMessages[le.Limit], le.Wallet),
+			err:     err,
+		}
+	}
+	return &Error{Kind: KindInternal, Code: CodeInternal, Message: "internal error", err: err}
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// WalletEvents returns up to limit events of a wallet following the one with sequence number after, oldest first,
+// starting with the last overlap events of the wallet up to that one.
+func (ws *wallet) WalletEvents(ctx context.Context, id uuid.UUID, after int64, overlap int, limit int) ([]model.Event, error) {
+	res, err := ws.storage.WalletEvents(ctx, id, after, overlap, limit)
+	if err != nil {
+		log.Println("wallet service wallet events err: ", err)
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// defaultHoldTTL is how long holds created without a TTL stay active unless configured otherwise.
+const defaultHoldTTL = 15 * time.Minute
+
+var (
+	ErrHoldNotActive      = newError(KindConflict, "HOLD_NOT_ACTIVE", "hold is not active")
+	ErrCaptureExceedsHold = newError(KindUnprocessable, "CAPTURE_EXCEEDS_HOLD", "capture amount exceeds the held amount")
+)
+
+// WithHoldTTL sets how long holds created without an explicit TTL stay active.
+func WithHoldTTL(d time.Duration) Option {
+	return func(ws *wallet) {
+		ws.holdTTL = d
+	}
+}
+
+// CreateHold reserves funds on a wallet, reducing its available balance but not its ledger balance.
+// The hold expires after the requested TTL or the default one. It fails with ErrInsufficientFunds
+// if the available balance does not cover the amount.
+func (ws *wallet) CreateHold(ctx context.Context, req dto.HoldCreateRequest) (model.Hold, error) {
+	var res model.Hold
+
+	w, err := ws.checkCurrency(ctx, req.UUID, req.Currency, req.Amount)
+	if err == nil {
+		err = ws.checkStatus(w, true)
+	}
+	if err != nil {
+		log.Println("wallet service create hold err: ", err)
+		return res, err
+	}
+
+	ttl := ws.holdTTL
+	if req.TTL != 0 {
+		ttl = time.Duration(req.TTL) * time.Second
+	}
+
+	err = ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		var err error
+		res, err = ws.storage.CreateHold(ctx, uuid.New(), req.UUID, req.Amount, ttl)
+		return auditEntry{action: model.AuditHoldCreate, wallet: req.UUID, after: res}, err
+	})
+	if errors.Is(err, db.ErrInsufficientFunds) {
+		return res, ErrInsufficientFunds
+	}
+	if err != nil {
+		log.Println("wallet service create hold err: ", err)
+		return res, statusErr(err)
+	}
+	return res, nil
+}
+
+// Hold retrieves a hold by its id.
+func (ws *wallet) Hold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	res, err := ws.storage.Hold(ctx, id)
+	if err != nil {
+		log.Println("wallet service hold err: ", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// CaptureHold takes the requested amount, or the whole held amount when none is given,
+// from the wallet and releases the rest of the hold. Capturing more than the held amount
+// fails with ErrCaptureExceedsHold, capturing a hold that is no longer active
+// fails with ErrHoldNotActive, and captures exceeding a wallet limit with LimitError.
+func (ws *wallet) CaptureHold(ctx context.Context, req dto.HoldCaptureRequest) (model.Hold, error) {
+	h, err := ws.storage.Hold(ctx, req.ID)
+	if err != nil {
+		log.Println("wallet service capture hold err: ", err)
+		return h, err
+	}
+	if h.Status != model.HoldActive {
+		return h, ErrHoldNotActive
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = h.Amount
+	}
+	if amount.Decimals() > money.Decimals(h.Currency) {
+		return h, ErrAmountPrecision
+	}
+	if amount > h.Amount {
+		return h, ErrCaptureExceedsHold
+	}
+
+	w, err := ws.storage.Balance(ctx, h.UUID)
+	if err != nil {
+		log.Println("wallet service capture hold err: ", err)
+		return h, err
+	}
+	err = ws.checkStatus(w, true)
+	if err != nil {
+		return h, err
+	}
+
+	var res model.Hold
+	err = ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		var err error
+		res, err = ws.storage.CaptureHold(ctx, req.ID, amount)
+		return auditEntry{action: model.AuditHoldCapture, wallet: h.UUID, before: h, after: res}, err
+	})
+	if errors.Is(err, db.ErrHoldNotActive) {
+		return res, ErrHoldNotActive
+	}
+	if err != nil {
+		log.Println("wallet service capture hold err: ", err)
+		return res, limitErr(statusErr(err))
+	}
+	return res, nil
+}
+
+// VoidHold releases a hold without taking anything from the wallet.
+// Voiding a hold that is no longer active fails with ErrHoldNotActive.
+func (ws *wallet) VoidHold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	var res model.Hold
+	err := ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		h, err := ws.storage.Hold(ctx, id)
+		if err != nil {
+			return auditEntry{}, err
+		}
+		res, err = ws.storage.VoidHold(ctx, id)
+		return auditEntry{action: model.AuditHoldVoid, wallet: h.UUID, before: h, after: res}, err
+	})
+	if errors.Is(err, db.ErrHoldNotActive) {
+		return res, ErrHoldNotActive
+	}
+	if err != nil {
+		log.Println("wallet service void hold err: ", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// ExpireHolds releases active holds past their expiry and returns how many were released.
+func (ws *wallet) ExpireHolds(ctx context.Context) (int64, error) {
+	n, err := ws.storage.ExpireHolds(ctx)
+	if err != nil {
+		log.Println("wallet service expire holds err: ", err)
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"cmd/app/main.go/internal/db"
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestWalletServiceCreateHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB, WithHoldTTL(time.Hour))
+
+	t.Run("TestWalletServiceCreateHold_Success", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.HoldCreateRequest{
+			UUID:   fakeUUID,
+			Amount: money.MustParse("10"),
+		}
+		fakeHold := model.Hold{ID: uuid.New(), UUID: fakeUUID, Amount: fakeReq.Amount, Status: model.HoldActive}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().CreateHold(gomock.Any(), gomock.Any(), fakeUUID, fakeReq.Amount, time.Hour).Return(fakeHold, nil)
+		hold, err := ws.CreateHold(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("create hold err: ", err)
+		}
+		if hold != fakeHold {
+			t.Errorf("Expected: %v, recieved: %v", fakeHold, hold)
+		}
+	})
+
+	t.Run("TestWalletServiceCreateHold_TTL", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.HoldCreateRequest{
+			UUID:   fakeUUID,
+			Amount: money.MustParse("10"),
+			TTL:    30,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().CreateHold(gomock.Any(), gomock.Any(), fakeUUID, fakeReq.Amount, 30*time.Second).Return(model.Hold{}, nil)
+		_, err := ws.CreateHold(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("create hold err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceCreateHold_InsufficientFunds", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.HoldCreateRequest{
+			UUID:   fakeUUID,
+			Amount: money.MustParse("10"),
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().CreateHold(gomock.Any(), gomock.Any(), fakeUUID, fakeReq.Amount, time.Hour).Return(model.Hold{}, db.ErrInsufficientFunds)
+		_, err := ws.CreateHold(t.Context(), fakeReq)
+		if !errors.Is(err, ErrInsufficientFunds) {
+			t.Errorf("Expected: %v, recieved: %v", ErrInsufficientFunds, err)
+		}
+	})
+
+	t.Run("TestWalletServiceCreateHold_Precision", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.HoldCreateRequest{
+			UUID:   fakeUUID,
+			Amount: money.MustParse("10.5"),
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "JPY"}, nil)
+		_, err := ws.CreateHold(t.Context(), fakeReq)
+		if !errors.Is(err, ErrAmountPrecision) {
+			t.Errorf("Expected: %v, recieved: %v", ErrAmountPrecision, err)
+		}
+	})
+}
+
+func TestWalletServiceCaptureHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	activeHold := func() model.Hold {
+		return model.Hold{
+			ID:       uuid.New(),
+			UUID:     uuid.New(),
+			Currency: "USD",
+			Amount:   money.MustParse("10"),
+			Status:   model.HoldActive,
+		}
+	}
+
+	t.Run("TestWalletServiceCaptureHold_Full", func(t *testing.T) {
+		fakeHold := activeHold()
+		fakeDB.EXPECT().Hold(gomock.Any(), fakeHold.ID).Return(fakeHold, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeHold.UUID).Return(model.Wallet{UUID: fakeHold.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().CaptureHold(gomock.Any(), fakeHold.ID, fakeHold.Amount).Return(fakeHold, nil)
+		_, err := ws.CaptureHold(t.Context(), dto.HoldCaptureRequest{ID: fakeHold.ID})
+		if err != nil {
+			t.Error("capture hold err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceCaptureHold_Partial", func(t *testing.T) {
+		fakeHold := activeHold()
+		fakeAmount := money.MustParse("2.5")
+		fakeDB.EXPECT().Hold(gomock.Any(), fakeHold.ID).Return(fakeHold, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeHold.UUID).Return(model.Wallet{UUID: fakeHold.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().CaptureHold(gomock.Any(), fakeHold.ID, fakeAmount).Return(fakeHold, nil)
+		_, err := ws.CaptureHold(t.Context(), dto.HoldCaptureRequest{ID: fakeHold.ID, Amount: fakeAmount})
+		if err != nil {
+			t.Error("capture hold err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceCaptureHold_Exceeds", func(t *testing.T) {
+		fakeHold := activeHold()
+		fakeDB.EXPECT().Hold(gomock.Any(), fakeHold.ID).Return(fakeHold, nil)
+		_, err := ws.CaptureHold(t.Context(), dto.HoldCaptureRequest{ID: fakeHold.ID, Amount: money.MustParse("10.01")})
+		if !errors.Is(err, ErrCaptureExceedsHold) {
+			t.Errorf("Expected: %v, recieved: %v", ErrCaptureExceedsHold, err)
+		}
+	})
+
+	t.Run("TestWalletServiceCaptureHold_Expired", func(t *testing.T) {
+		fakeHold := activeHold()
+		fakeHold.Status = model.HoldExpired
+		fakeDB.EXPECT().Hold(gomock.Any(), fakeHold.ID).Return(fakeHold, nil)
+		_, err := ws.CaptureHold(t.Context(), dto.HoldCaptureRequest{ID: fakeHold.ID})
+		if !errors.Is(err, ErrHoldNotActive) {
+			t.Errorf("Expected: %v, recieved: %v", ErrHoldNotActive, err)
+		}
+	})
+
+	t.Run("TestWalletServiceCaptureHold_ConcurrentVoid", func(t *testing.T) {
+		fakeHold := activeHold()
+		fakeDB.EXPECT().Hold(gomock.Any(), fakeHold.ID).Return(fakeHold, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeHold.UUID).Return(model.Wallet{UUID: fakeHold.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().CaptureHold(gomock.Any(), fakeHold.ID, fakeHold.Amount).Return(model.Hold{}, db.ErrHoldNotActive)
+		_, err := ws.CaptureHold(t.Context(), dto.HoldCaptureRequest{ID: fakeHold.ID})
+		if !errors.Is(err, ErrHoldNotActive) {
+			t.Errorf("Expected: %v, recieved: %v", ErrHoldNotActive, err)
+		}
+	})
+}
+
+func TestWalletServiceVoidHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceVoidHold_Success", func(t *testing.T) {
+		fakeHold := model.Hold{ID: uuid.New(), Status: model.HoldVoided}
+		fakeDB.EXPECT().Hold(gomock.Any(), fakeHold.ID).Return(model.Hold{ID: fakeHold.ID, Status: model.HoldActive}, nil)
+		fakeDB.EXPECT().VoidHold(gomock.Any(), fakeHold.ID).Return(fakeHold, nil)
+		hold, err := ws.VoidHold(t.Context(), fakeHold.ID)
+		if err != nil {
+			t.Error("void hold err: ", err)
+		}
+		if hold != fakeHold {
+			t.Errorf("Expected: %v, recieved: %v", fakeHold, hold)
+		}
+	})
+
+	t.Run("TestWalletServiceVoidHold_NotActive", func(t *testing.T) {
+		fakeID := uuid.New()
+		fakeDB.EXPECT().Hold(gomock.Any(), fakeID).Return(model.Hold{ID: fakeID, Status: model.HoldExpired}, nil)
+		fakeDB.EXPECT().VoidHold(gomock.Any(), fakeID).Return(model.Hold{}, db.ErrHoldNotActive)
+		_, err := ws.VoidHold(t.Context(), fakeID)
+		if !errors.Is(err, ErrHoldNotActive) {
+			t.Errorf("Expected: %v, recieved: %v", ErrHoldNotActive, err)
+		}
+	})
+}
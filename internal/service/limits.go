@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// LimitError is returned when an operation would exceed a spending limit of a wallet.
+// Limit is one of the model.Limit constants.
+type LimitError struct {
+	Wallet uuid.UUID
+	Limit  string
+}
+
+// limitCodes are the error codes of operations exceeding a wallet limit.
+var limitCodes = map[string]string{
+	model.LimitMaxWithdrawal: "MAX_WITHDRAWAL_EXCEEDED",
+	model.LimitDailyDebit:    "DAILY_LIMIT_EXCEEDED",
+	model.LimitMonthlyDebit:  "MONTHLY_LIMIT_EXCEEDED",
+	model.LimitMaxBalance:    "MAX_BALANCE_EXCEEDED",
+}
+
+// limitMessages describe the limit exceeded by an operation.
+var limitMessages = map[string]string{
+	model.LimitMaxWithdrawal: "amount exceeds the maximum single withdrawal of wallet %s",
+	model.LimitDailyDebit:    "amount exceeds the daily debit limit of wallet %s",
+	model.LimitMonthlyDebit:  "amount exceeds the monthly debit limit of wallet %s",
+	model.LimitMaxBalance:    "amount exceeds the maximum balance of wallet %s",
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("wallet %s limit %s exceeded", e.Wallet, e.Limit)
+}
+
+// limitErr turns a storage limit error into a LimitError and returns other errors unchanged.
+func limitErr(err error) error {
+	var le *db.LimitError
+	if errors.As(err, &le) {
+		return &LimitError{Wallet: le.Wallet, Limit: le.Limit}
+	}
+	return err
+}
+
+// Limits returns the spending limits of a wallet.
+func (ws *wallet) Limits(ctx context.Context, id uuid.UUID) (model.WalletLimits, error) {
+	res, err := ws.storage.Limits(ctx, id)
+	if err != nil {
+		log.Println("wallet service limits err: ", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// SetLimits replaces the spending limits of a wallet. The limits are amounts in the wallet currency
+// and fail with ErrAmountPrecision if they have more decimal places than the currency allows.
+func (ws *wallet) SetLimits(ctx context.Context, req dto.WalletLimitsRequest) (model.WalletLimits, error) {
+	w, err := ws.storage.Balance(ctx, req.UUID)
+	if err != nil {
+		log.Println("wallet service set limits err: ", err)
+		return model.WalletLimits{}, err
+	}
+
+	limits := []*money.Amount{req.MaxWithdrawal, req.DailyDebit, req.MonthlyDebit, req.MaxBalance}
+	for _, l := range limits {
+		if l != nil && l.Decimals() > money.Decimals(w.Currency) {
+			return model.WalletLimits{}, ErrAmountPrecision
+		}
+	}
+
+	var res model.WalletLimits
+	err = ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		before, err := ws.storage.Limits(ctx, req.UUID)
+		if err != nil {
+			return auditEntry{}, err
+		}
+		res, err = ws.storage.SetLimits(ctx, model.WalletLimits{
+			UUID:          req.UUID,
+			MaxWithdrawal: req.MaxWithdrawal,
+			DailyDebit:    req.DailyDebit,
+			MonthlyDebit:  req.MonthlyDebit,
+			MaxBalance:    req.MaxBalance,
+		})
+		return auditEntry{action: model.AuditLimitsSet, wallet: req.UUID, before: before, after: res}, err
+	})
+	if err != nil {
+		log.Println("wallet service set limits err: ", err)
+		return res, err
+	}
+	return res, nil
+}
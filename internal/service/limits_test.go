@@ -0,0 +1,69 @@
+package service
+
+import (
+	"cmd/app/main.go/internal/db"
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestWalletServiceLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceLimits_Withdraw", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("500")}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD", Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Withdraw(gomock.Any(), fakeUUID, fakeReq.Amount).Return(model.Wallet{}, &db.LimitError{Wallet: fakeUUID, Limit: model.LimitDailyDebit})
+
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		var le *LimitError
+		if !errors.As(err, &le) {
+			t.Fatalf("Expected: %T, recieved: %v", le, err)
+		}
+		correct := LimitError{Wallet: fakeUUID, Limit: model.LimitDailyDebit}
+		if *le != correct {
+			t.Errorf("Expected: %v, recieved: %v", correct, *le)
+		}
+	})
+
+	t.Run("TestWalletServiceLimits_Set", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		daily := money.MustParse("1000")
+		fakeReq := dto.WalletLimitsRequest{UUID: fakeUUID, DailyDebit: &daily}
+		fakeLimits := model.WalletLimits{UUID: fakeUUID, Currency: "USD", DailyDebit: &daily}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Limits(gomock.Any(), fakeUUID).Return(model.WalletLimits{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().SetLimits(gomock.Any(), model.WalletLimits{UUID: fakeUUID, DailyDebit: &daily}).Return(fakeLimits, nil)
+
+		limits, err := ws.SetLimits(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("set limits err: ", err)
+		}
+		if !reflect.DeepEqual(limits, fakeLimits) {
+			t.Errorf("Expected: %v, recieved: %v", fakeLimits, limits)
+		}
+	})
+
+	t.Run("TestWalletServiceLimits_SetPrecision", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		maxBalance := money.MustParse("1000.5")
+		fakeReq := dto.WalletLimitsRequest{UUID: fakeUUID, MaxBalance: &maxBalance}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "JPY"}, nil)
+
+		_, err := ws.SetLimits(t.Context(), fakeReq)
+		if !errors.Is(err, ErrAmountPrecision) {
+			t.Errorf("Expected: %v, recieved: %v", ErrAmountPrecision, err)
+		}
+	})
+}
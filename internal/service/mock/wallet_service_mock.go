@@ -7,6 +7,7 @@ package mocks
 import (
 	dto "cmd/app/main.go/internal/dto"
 	model "cmd/app/main.go/internal/model"
+	service "cmd/app/main.go/internal/service"
 	context "context"
 	reflect "reflect"
 
@@ -37,6 +38,21 @@ func (m *MockWallet) EXPECT() *MockWalletMockRecorder {
 	return m.recorder
 }
 
+// AuditLog mocks base method.
+func (m *MockWallet) AuditLog(ctx context.Context, req dto.AuditLogRequest) (dto.AuditLogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLog", ctx, req)
+	ret0, _ := ret[0].(dto.AuditLogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditLog indicates an expected call of AuditLog.
+func (mr *MockWalletMockRecorder) AuditLog(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLog", reflect.TypeOf((*MockWallet)(nil).AuditLog), ctx, req)
+}
+
 // Balance mocks base method.
 func (m *MockWallet) Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, error) {
 	m.ctrl.T.Helper()
@@ -52,19 +68,243 @@ func (mr *MockWalletMockRecorder) Balance(ctx, uuid interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balance", reflect.TypeOf((*MockWallet)(nil).Balance), ctx, uuid)
 }
 
+// CaptureHold mocks base method.
+func (m *MockWallet) CaptureHold(ctx context.Context, req dto.HoldCaptureRequest) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", ctx, req)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockWalletMockRecorder) CaptureHold(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockWallet)(nil).CaptureHold), ctx, req)
+}
+
 // Create mocks base method.
-func (m *MockWallet) Create(ctx context.Context) (uuid.UUID, error) {
+func (m *MockWallet) Create(ctx context.Context, req dto.WalletCreateRequest) (model.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx)
-	ret0, _ := ret[0].(uuid.UUID)
+	ret := m.ctrl.Call(m, "Create", ctx, req)
+	ret0, _ := ret[0].(model.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockWalletMockRecorder) Create(ctx interface{}) *gomock.Call {
+func (mr *MockWalletMockRecorder) Create(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWallet)(nil).Create), ctx, req)
+}
+
+// CreateHold mocks base method.
+func (m *MockWallet) CreateHold(ctx context.Context, req dto.HoldCreateRequest) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", ctx, req)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockWalletMockRecorder) CreateHold(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockWallet)(nil).CreateHold), ctx, req)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWallet) CreateWebhook(ctx context.Context, req dto.WebhookCreateRequest) (model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, req)
+	ret0, _ := ret[0].(model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWalletMockRecorder) CreateWebhook(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWallet)(nil).CreateWebhook), ctx, req)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWallet) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWalletMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWallet)(nil).DeleteWebhook), ctx, id)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockWallet) ExpireHolds(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockWalletMockRecorder) ExpireHolds(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockWallet)(nil).ExpireHolds), ctx)
+}
+
+// Hold mocks base method.
+func (m *MockWallet) Hold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold", ctx, id)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockWalletMockRecorder) Hold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockWallet)(nil).Hold), ctx, id)
+}
+
+// Idempotent mocks base method.
+func (m *MockWallet) Idempotent(ctx context.Context, key, fingerprint string, fn service.IdempotentFunc) (model.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Idempotent", ctx, key, fingerprint, fn)
+	ret0, _ := ret[0].(model.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Idempotent indicates an expected call of Idempotent.
+func (mr *MockWalletMockRecorder) Idempotent(ctx, key, fingerprint, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Idempotent", reflect.TypeOf((*MockWallet)(nil).Idempotent), ctx, key, fingerprint, fn)
+}
+
+// Limits mocks base method.
+func (m *MockWallet) Limits(ctx context.Context, id uuid.UUID) (model.WalletLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limits", ctx, id)
+	ret0, _ := ret[0].(model.WalletLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Limits indicates an expected call of Limits.
+func (mr *MockWalletMockRecorder) Limits(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limits", reflect.TypeOf((*MockWallet)(nil).Limits), ctx, id)
+}
+
+// Operation mocks base method.
+func (m *MockWallet) Operation(ctx context.Context, id uuid.UUID) (model.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Operation", ctx, id)
+	ret0, _ := ret[0].(model.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Operation indicates an expected call of Operation.
+func (mr *MockWalletMockRecorder) Operation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Operation", reflect.TypeOf((*MockWallet)(nil).Operation), ctx, id)
+}
+
+// OwnerWallets mocks base method.
+func (m *MockWallet) OwnerWallets(ctx context.Context, ownerID string) ([]model.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnerWallets", ctx, ownerID)
+	ret0, _ := ret[0].([]model.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OwnerWallets indicates an expected call of OwnerWallets.
+func (mr *MockWalletMockRecorder) OwnerWallets(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnerWallets", reflect.TypeOf((*MockWallet)(nil).OwnerWallets), ctx, ownerID)
+}
+
+// PurgeIdempotencyKeys mocks base method.
+func (m *MockWallet) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeIdempotencyKeys", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeIdempotencyKeys indicates an expected call of PurgeIdempotencyKeys.
+func (mr *MockWalletMockRecorder) PurgeIdempotencyKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeIdempotencyKeys", reflect.TypeOf((*MockWallet)(nil).PurgeIdempotencyKeys), ctx)
+}
+
+// Redeliver mocks base method.
+func (m *MockWallet) Redeliver(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeliver", ctx, id)
+	ret0, _ := ret[0].(model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redeliver indicates an expected call of Redeliver.
+func (mr *MockWalletMockRecorder) Redeliver(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeliver", reflect.TypeOf((*MockWallet)(nil).Redeliver), ctx, id)
+}
+
+// Reverse mocks base method.
+func (m *MockWallet) Reverse(ctx context.Context, req dto.ReversalRequest) (model.Operation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, req)
+	ret0, _ := ret[0].(model.Operation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockWalletMockRecorder) Reverse(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockWallet)(nil).Reverse), ctx, req)
+}
+
+// SetLimits mocks base method.
+func (m *MockWallet) SetLimits(ctx context.Context, req dto.WalletLimitsRequest) (model.WalletLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLimits", ctx, req)
+	ret0, _ := ret[0].(model.WalletLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLimits indicates an expected call of SetLimits.
+func (mr *MockWalletMockRecorder) SetLimits(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLimits", reflect.TypeOf((*MockWallet)(nil).SetLimits), ctx, req)
+}
+
+// SetStatus mocks base method.
+func (m *MockWallet) SetStatus(ctx context.Context, req dto.WalletStatusRequest) (model.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetStatus", ctx, req)
+	ret0, _ := ret[0].(model.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetStatus indicates an expected call of SetStatus.
+func (mr *MockWalletMockRecorder) SetStatus(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWallet)(nil).Create), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStatus", reflect.TypeOf((*MockWallet)(nil).SetStatus), ctx, req)
 }
 
 // Transaction mocks base method.
@@ -81,3 +321,78 @@ func (mr *MockWalletMockRecorder) Transaction(ctx, req interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockWallet)(nil).Transaction), ctx, req)
 }
+
+// Transactions mocks base method.
+func (m *MockWallet) Transactions(ctx context.Context, req dto.TransactionHistoryRequest) (dto.TransactionHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transactions", ctx, req)
+	ret0, _ := ret[0].(dto.TransactionHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Transactions indicates an expected call of Transactions.
+func (mr *MockWalletMockRecorder) Transactions(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transactions", reflect.TypeOf((*MockWallet)(nil).Transactions), ctx, req)
+}
+
+// VoidHold mocks base method.
+func (m *MockWallet) VoidHold(ctx context.Context, id uuid.UUID) (model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidHold", ctx, id)
+	ret0, _ := ret[0].(model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidHold indicates an expected call of VoidHold.
+func (mr *MockWalletMockRecorder) VoidHold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidHold", reflect.TypeOf((*MockWallet)(nil).VoidHold), ctx, id)
+}
+
+// WalletEvents mocks base method.
+func (m *MockWallet) WalletEvents(ctx context.Context, id uuid.UUID, after int64, overlap, limit int) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalletEvents", ctx, id, after, overlap, limit)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WalletEvents indicates an expected call of WalletEvents.
+func (mr *MockWalletMockRecorder) WalletEvents(ctx, id, after, overlap, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletEvents", reflect.TypeOf((*MockWallet)(nil).WalletEvents), ctx, id, after, overlap, limit)
+}
+
+// WebhookDeliveries mocks base method.
+func (m *MockWallet) WebhookDeliveries(ctx context.Context, req dto.WebhookDeliveriesRequest) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDeliveries", ctx, req)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookDeliveries indicates an expected call of WebhookDeliveries.
+func (mr *MockWalletMockRecorder) WebhookDeliveries(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDeliveries", reflect.TypeOf((*MockWallet)(nil).WebhookDeliveries), ctx, req)
+}
+
+// Webhooks mocks base method.
+func (m *MockWallet) Webhooks(ctx context.Context) ([]model.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Webhooks", ctx)
+	ret0, _ := ret[0].([]model.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Webhooks indicates an expected call of Webhooks.
+func (mr *MockWalletMockRecorder) Webhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Webhooks", reflect.TypeOf((*MockWallet)(nil).Webhooks), ctx)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotReversible   = newError(KindUnprocessable, "OPERATION_NOT_REVERSIBLE", "operation cannot be reversed")
+	ErrAlreadyReversed = newError(KindConflict, "ALREADY_REVERSED", "operation already reversed")
+	ErrReversalExceeds = newError(KindUnprocessable, "REVERSAL_EXCEEDS_OPERATION", "reversal exceeds the operation amount")
+)
+
+// Operation retrieves a money-moving operation by its id.
+func (ws *wallet) Operation(ctx context.Context, id uuid.UUID) (model.Operation, error) {
+	res, err := ws.storage.Operation(ctx, id)
+	if err != nil {
+		log.Println("wallet service operation err: ", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// Reverse creates a compensating operation for a deposit, withdrawal, capture or transfer.
+// The requested amount, or the whole not yet reversed amount when none is given, is expressed
+// in the currency of the operation. For transfers between currencies the amount taken back
+// from the credited wallet is proportional to the original legs, and the last reversal takes
+// back exactly what remains. Reversals of operations that were already fully reversed fail with
+// ErrAlreadyReversed and reversals above the remaining amount fail with ErrReversalExceeds.
+func (ws *wallet) Reverse(ctx context.Context, req dto.ReversalRequest) (model.Operation, error) {
+	op, err := ws.storage.Operation(ctx, req.ID)
+	if err != nil {
+		log.Println("wallet service reverse err: ", err)
+		return op, err
+	}
+
+	switch op.Type {
+	case "DEPOSIT", "WITHDRAW", "CAPTURE", "TRANSFER":
+	default:
+		return op, ErrNotReversible
+	}
+
+	remaining := op.Amount - op.Reversed
+	if remaining == 0 {
+		return op, ErrAlreadyReversed
+	}
+	amount := req.Amount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount.Decimals() > money.Decimals(op.Currency) {
+		return op, ErrAmountPrecision
+	}
+	if amount > remaining {
+		return op, ErrReversalExceeds
+	}
+
+	err = ws.checkReversalStatus(ctx, op)
+	if err != nil {
+		return op, err
+	}
+
+	r := db.Reversal{
+		ID:     uuid.New(),
+		Of:     op.ID,
+		Amount: amount,
+	}
+	if op.CounterAmount != nil {
+		r.CounterAmount, err = ws.counterReversal(op, amount)
+		if err != nil {
+			return op, err
+		}
+	}
+
+	var res model.Operation
+	err = ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		var err error
+		res, err = ws.storage.Reverse(ctx, r)
+		return auditEntry{action: model.AuditReversal, wallet: op.UUID, before: op, after: res}, err
+	})
+	if errors.Is(err, db.ErrAlreadyReversed) {
+		return res, ErrAlreadyReversed
+	}
+	if errors.Is(err, db.ErrReversalExceeds) {
+		return res, ErrReversalExceeds
+	}
+	if errors.Is(err, db.ErrInsufficientFunds) {
+		return res, ErrInsufficientFunds
+	}
+	if err != nil {
+		log.Println("wallet service reverse err: ", err)
+		return res, statusErr(err)
+	}
+	return res, nil
+}
+
+// checkReversalStatus makes sure the status of the wallets a reversal of op debits and credits allows it.
+func (ws *wallet) checkReversalStatus(ctx context.Context, op model.Operation) error {
+	w, err := ws.storage.Balance(ctx, op.UUID)
+	if err != nil {
+		return err
+	}
+	err = ws.checkStatus(w, op.Type == "DEPOSIT")
+	if err != nil || op.Counterparty == nil {
+		return err
+	}
+
+	counterparty, err := ws.storage.Balance(ctx, *op.Counterparty)
+	if err != nil {
+		return err
+	}
+	return ws.checkStatus(counterparty, true)
+}
+
+// counterReversal returns the amount a transfer reversal takes back from the credited wallet.
+func (ws *wallet) counterReversal(op model.Operation, amount money.Amount) (money.Amount, error) {
+	if amount == op.Amount-op.Reversed {
+		return *op.CounterAmount - op.CounterReversed, nil
+	}
+	rate := new(big.Rat).Quo(op.CounterAmount.Rat(), op.Amount.Rat())
+	res, err := money.Convert(amount, rate, money.Decimals(*op.CounterCurrency), ws.rounding)
+	if err != nil {
+		return res, err
+	}
+	if !res.IsPositive() {
+		return res, ErrAmountTooSmall
+	}
+	return res, nil
+}
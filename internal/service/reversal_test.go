@@ -0,0 +1,174 @@
+package service
+
+import (
+	"cmd/app/main.go/internal/db"
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestWalletServiceReverse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	deposit := func() model.Operation {
+		return model.Operation{
+			ID:       uuid.New(),
+			Type:     "DEPOSIT",
+			UUID:     uuid.New(),
+			Currency: "USD",
+			Amount:   money.MustParse("100"),
+		}
+	}
+
+	transfer := func() model.Operation {
+		counterparty := uuid.New()
+		counterCurrency := "EUR"
+		counterAmount := money.MustParse("92")
+		return model.Operation{
+			ID:              uuid.New(),
+			Type:            "TRANSFER",
+			UUID:            uuid.New(),
+			Currency:        "USD",
+			Amount:          money.MustParse("100"),
+			Counterparty:    &counterparty,
+			CounterCurrency: &counterCurrency,
+			CounterAmount:   &counterAmount,
+		}
+	}
+
+	t.Run("TestWalletServiceReverse_Full", func(t *testing.T) {
+		fakeOp := deposit()
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeOp.UUID).Return(model.Wallet{UUID: fakeOp.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Reverse(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, r db.Reversal) (model.Operation, error) {
+			if r.Of != fakeOp.ID || r.Amount != fakeOp.Amount {
+				t.Errorf("Expected: %v, recieved: %v", fakeOp.Amount, r.Amount)
+			}
+			return model.Operation{ID: r.ID, Type: "REVERSAL", ReversalOf: &r.Of}, nil
+		})
+		rev, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID})
+		if err != nil {
+			t.Error("reverse err: ", err)
+		}
+		if rev.ReversalOf == nil || *rev.ReversalOf != fakeOp.ID {
+			t.Errorf("Expected: %v, recieved: %v", fakeOp.ID, rev.ReversalOf)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_Partial", func(t *testing.T) {
+		fakeOp := deposit()
+		fakeOp.Reversed = money.MustParse("40")
+		fakeAmount := money.MustParse("60")
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeOp.UUID).Return(model.Wallet{UUID: fakeOp.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Reverse(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, r db.Reversal) (model.Operation, error) {
+			if r.Amount != fakeAmount {
+				t.Errorf("Expected: %v, recieved: %v", fakeAmount, r.Amount)
+			}
+			return model.Operation{}, nil
+		})
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID, Amount: fakeAmount})
+		if err != nil {
+			t.Error("reverse err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_Exceeds", func(t *testing.T) {
+		fakeOp := deposit()
+		fakeOp.Reversed = money.MustParse("40")
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID, Amount: money.MustParse("60.01")})
+		if !errors.Is(err, ErrReversalExceeds) {
+			t.Errorf("Expected: %v, recieved: %v", ErrReversalExceeds, err)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_AlreadyReversed", func(t *testing.T) {
+		fakeOp := deposit()
+		fakeOp.Reversed = fakeOp.Amount
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID})
+		if !errors.Is(err, ErrAlreadyReversed) {
+			t.Errorf("Expected: %v, recieved: %v", ErrAlreadyReversed, err)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_ConcurrentReversal", func(t *testing.T) {
+		fakeOp := deposit()
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeOp.UUID).Return(model.Wallet{UUID: fakeOp.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Reverse(gomock.Any(), gomock.Any()).Return(model.Operation{}, db.ErrAlreadyReversed)
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID})
+		if !errors.Is(err, ErrAlreadyReversed) {
+			t.Errorf("Expected: %v, recieved: %v", ErrAlreadyReversed, err)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_NotReversible", func(t *testing.T) {
+		fakeOp := deposit()
+		fakeOp.Type = "REVERSAL"
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID})
+		if !errors.Is(err, ErrNotReversible) {
+			t.Errorf("Expected: %v, recieved: %v", ErrNotReversible, err)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_FrozenCounterparty", func(t *testing.T) {
+		fakeOp := transfer()
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeOp.UUID).Return(model.Wallet{UUID: fakeOp.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), *fakeOp.Counterparty).Return(model.Wallet{UUID: *fakeOp.Counterparty, Status: model.WalletFrozen}, nil)
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID})
+		if !errors.Is(err, ErrWalletFrozen) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletFrozen, err)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_TransferPartial", func(t *testing.T) {
+		fakeOp := transfer()
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeOp.UUID).Return(model.Wallet{UUID: fakeOp.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), *fakeOp.Counterparty).Return(model.Wallet{UUID: *fakeOp.Counterparty, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Reverse(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, r db.Reversal) (model.Operation, error) {
+			want := money.MustParse("9.25")
+			if r.CounterAmount != want {
+				t.Errorf("Expected: %v, recieved: %v", want, r.CounterAmount)
+			}
+			return model.Operation{}, nil
+		})
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID, Amount: money.MustParse("10.05")})
+		if err != nil {
+			t.Error("reverse err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceReverse_TransferRemainder", func(t *testing.T) {
+		fakeOp := transfer()
+		fakeOp.Reversed = money.MustParse("10.05")
+		fakeOp.CounterReversed = money.MustParse("9.25")
+		fakeDB.EXPECT().Operation(gomock.Any(), fakeOp.ID).Return(fakeOp, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeOp.UUID).Return(model.Wallet{UUID: fakeOp.UUID, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), *fakeOp.Counterparty).Return(model.Wallet{UUID: *fakeOp.Counterparty, Status: model.WalletActive}, nil)
+		fakeDB.EXPECT().Reverse(gomock.Any(), gomock.Any()).DoAndReturn(func(_ any, r db.Reversal) (model.Operation, error) {
+			want := money.MustParse("82.75")
+			if r.Amount != money.MustParse("89.95") || r.CounterAmount != want {
+				t.Errorf("Expected: %v, recieved: %v", want, r.CounterAmount)
+			}
+			return model.Operation{}, nil
+		})
+		_, err := ws.Reverse(t.Context(), dto.ReversalRequest{ID: fakeOp.ID})
+		if err != nil {
+			t.Error("reverse err: ", err)
+		}
+	})
+}
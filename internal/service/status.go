@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+)
+
+var (
+	ErrWalletFrozen            = newError(KindFrozen, "WALLET_FROZEN", "wallet is frozen")
+	ErrWalletClosed            = newError(KindFrozen, "WALLET_CLOSED", "wallet is closed")
+	ErrInvalidStatusTransition = newError(KindConflict, "INVALID_STATUS_TRANSITION", "invalid wallet status transition")
+	ErrBalanceNotZero          = newError(KindConflict, "BALANCE_NOT_ZERO", "wallet balance is not zero")
+)
+
+// statusTransitions lists the statuses a wallet may be moved to a status from.
+// Closed wallets stay closed.
+var statusTransitions = map[string][]string{
+	model.WalletActive: {model.WalletFrozen},
+	model.WalletFrozen: {model.WalletActive},
+	model.WalletClosed: {model.WalletActive, model.WalletFrozen},
+}
+
+// statusActions are the audited actions moving a wallet to a status.
+var statusActions = map[string]string{
+	model.WalletActive: model.AuditWalletUnfreeze,
+	model.WalletFrozen: model.AuditWalletFreeze,
+	model.WalletClosed: model.AuditWalletClose,
+}
+
+// WithFrozenCredits sets whether frozen wallets accept credits. Debits are always rejected.
+func WithFrozenCredits(allow bool) Option {
+	return func(ws *wallet) {
+		ws.frozenCredits = allow
+	}
+}
+
+// SetStatus freezes, unfreezes or closes a wallet, recording the reason of the change.
+// Active wallets can be frozen, frozen ones unfrozen, and both closed, which requires
+// a zero balance. Other changes fail with ErrInvalidStatusTransition, and closing a wallet
+// that still holds funds fails with ErrBalanceNotZero.
+func (ws *wallet) SetStatus(ctx context.Context, req dto.WalletStatusRequest) (model.Wallet, error) {
+	var res model.Wallet
+	err := ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		var before model.Wallet
+		var err error
+		before, res, err = ws.storage.SetStatus(ctx, req.UUID, statusTransitions[req.Status], req.Status, req.Reason)
+		return auditEntry{action: statusActions[req.Status], wallet: req.UUID, before: before, after: res}, err
+	})
+	if errors.Is(err, db.ErrStatusConflict) {
+		return res, ErrInvalidStatusTransition
+	}
+	if errors.Is(err, db.ErrBalanceNotZero) {
+		return res, ErrBalanceNotZero
+	}
+	if err != nil {
+		log.Println("wallet service set status err: ", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// checkStatus makes sure the wallet status allows debiting or crediting it.
+// Closed wallets reject everything and frozen ones reject debits, and credits
+// unless frozen wallets are configured to accept them. The storage checks the status
+// again under the row lock, which statusErr reports in the same way.
+func (ws *wallet) checkStatus(w model.Wallet, debit bool) error {
+	switch w.Status {
+	case model.WalletClosed:
+		return ErrWalletClosed
+	case model.WalletFrozen:
+		if debit || !ws.frozenCredits {
+			return ErrWalletFrozen
+		}
+	}
+	return nil
+}
+
+// statusErr converts a balance update refused by the storage because of the wallet status
+// into ErrWalletFrozen or ErrWalletClosed. Other errors are returned as is.
+func statusErr(err error) error {
+	if errors.Is(err, db.ErrWalletFrozen) {
+		return ErrWalletFrozen
+	}
+	if errors.Is(err, db.ErrWalletClosed) {
+		return ErrWalletClosed
+	}
+	return err
+}
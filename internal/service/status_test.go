@@ -0,0 +1,131 @@
+package service
+
+import (
+	"cmd/app/main.go/internal/db"
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestWalletServiceSetStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceSetStatus_Freeze", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletStatusRequest{UUID: fakeUUID, Status: model.WalletFrozen, Reason: "fraud report"}
+		fakeWallet := model.Wallet{UUID: fakeUUID, Status: model.WalletFrozen}
+		fakeDB.EXPECT().SetStatus(gomock.Any(), fakeUUID, []string{model.WalletActive}, model.WalletFrozen, "fraud report").Return(model.Wallet{UUID: fakeUUID, Status: model.WalletActive}, fakeWallet, nil)
+		wallet, err := ws.SetStatus(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("set status err: ", err)
+		}
+		if wallet != fakeWallet {
+			t.Errorf("Expected: %v, recieved: %v", fakeWallet, wallet)
+		}
+	})
+
+	t.Run("TestWalletServiceSetStatus_Close", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletStatusRequest{UUID: fakeUUID, Status: model.WalletClosed, Reason: "customer request"}
+		fakeDB.EXPECT().SetStatus(gomock.Any(), fakeUUID, []string{model.WalletActive, model.WalletFrozen}, model.WalletClosed, "customer request").Return(model.Wallet{}, model.Wallet{}, db.ErrBalanceNotZero)
+		_, err := ws.SetStatus(t.Context(), fakeReq)
+		if !errors.Is(err, ErrBalanceNotZero) {
+			t.Errorf("Expected: %v, recieved: %v", ErrBalanceNotZero, err)
+		}
+	})
+
+	t.Run("TestWalletServiceSetStatus_InvalidTransition", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletStatusRequest{UUID: fakeUUID, Status: model.WalletActive, Reason: "cleared"}
+		fakeDB.EXPECT().SetStatus(gomock.Any(), fakeUUID, []string{model.WalletFrozen}, model.WalletActive, "cleared").Return(model.Wallet{}, model.Wallet{}, db.ErrStatusConflict)
+		_, err := ws.SetStatus(t.Context(), fakeReq)
+		if !errors.Is(err, ErrInvalidStatusTransition) {
+			t.Errorf("Expected: %v, recieved: %v", ErrInvalidStatusTransition, err)
+		}
+	})
+}
+
+func TestWalletServiceTransactionStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+	strict := New(fakeDB, WithFrozenCredits(false))
+
+	request := func(opType string, status string) dto.WalletTransactionRequest {
+		fakeUUID := uuid.New()
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD", Status: status}, nil)
+		return dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   opType,
+			Amount: money.MustParse("10"),
+		}
+	}
+
+	t.Run("TestWalletServiceTransactionStatus_FrozenDebit", func(t *testing.T) {
+		_, err := ws.Transaction(t.Context(), request("WITHDRAW", model.WalletFrozen))
+		if !errors.Is(err, ErrWalletFrozen) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletFrozen, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionStatus_FrozenCredit", func(t *testing.T) {
+		fakeReq := request("DEPOSIT", model.WalletFrozen)
+		fakeDB.EXPECT().Deposit(gomock.Any(), fakeReq.UUID, fakeReq.Amount).Return(model.Wallet{}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transaction err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionStatus_FrozenCreditRejected", func(t *testing.T) {
+		_, err := strict.Transaction(t.Context(), request("DEPOSIT", model.WalletFrozen))
+		if !errors.Is(err, ErrWalletFrozen) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletFrozen, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionStatus_Closed", func(t *testing.T) {
+		_, err := ws.Transaction(t.Context(), request("DEPOSIT", model.WalletClosed))
+		if !errors.Is(err, ErrWalletClosed) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletClosed, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionStatus_TransferToClosed", func(t *testing.T) {
+		fakeReq := request("TRANSFER", model.WalletActive)
+		fakeReq.ToUUID = uuid.New()
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeReq.ToUUID).Return(model.Wallet{UUID: fakeReq.ToUUID, Currency: "USD", Status: model.WalletClosed}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrWalletClosed) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletClosed, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionStatus_FrozenConcurrently", func(t *testing.T) {
+		fakeReq := request("WITHDRAW", model.WalletActive)
+		fakeDB.EXPECT().Withdraw(gomock.Any(), fakeReq.UUID, fakeReq.Amount).Return(model.Wallet{}, db.ErrWalletFrozen)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrWalletFrozen) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletFrozen, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionStatus_ClosedConcurrently", func(t *testing.T) {
+		fakeReq := request("DEPOSIT", model.WalletActive)
+		fakeDB.EXPECT().Deposit(gomock.Any(), fakeReq.UUID, fakeReq.Amount).Return(model.Wallet{}, db.ErrWalletClosed)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrWalletClosed) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletClosed, err)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"cmd/app/main.go/internal/dto"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ruleMessages describe the validation rules failed by fields. %s is the parameter of the rule.
+var ruleMessages = map[string]string{
+	"required":        "is required",
+	"required_if":     "is required for the operation",
+	"excluded_unless": "is not allowed for the operation",
+	"oneof":           "must be one of %s",
+	"gt":              "must be greater than %s",
+	"min":             "must be at least %s",
+	"max":             "must be at most %s",
+	"uuid":            "must be a UUID",
+	"iso4217":         "must be an ISO 4217 currency code",
+	"http_url":        "must be an HTTP or HTTPS URL",
+	"gtfield":         "must be after %s",
+	"nefield":         "must differ from %s",
+	"precision":       "must not have more than %s decimal places",
+}
+
+// FieldErrors describes the fields of req that failed binding or validation with err, named as in
+// the request, so that every API reports invalid fields with the same names and codes.
+func FieldErrors(req any, err error) []FieldError {
+	t := reflect.TypeOf(req)
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make([]FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			message, ok := ruleMessages[fe.Tag()]
+			if !ok {
+				message = "is invalid"
+			}
+			param := fe.Param()
+			if fe.Tag() == "gtfield" || fe.Tag() == "nefield" {
+				param = requestName(t, param)
+			}
+			if strings.Contains(message, "%s") {
+				message = fmt.Sprintf(message, param)
+			}
+			fields = append(fields, FieldError{Field: requestName(t, fe.StructField()), Code: fe.Tag(), Message: message})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{Field: typeErr.Field, Code: "type", Message: "has the wrong type"}}
+	}
+	if errors.Is(err, dto.ErrWalletIDMismatch) {
+		return []FieldError{{Field: "walletId", Code: "eqfield", Message: "must equal valletId"}}
+	}
+	return []FieldError{{Code: "malformed", Message: "request is malformed"}}
+}
+
+// requestName returns the name of a field of the request type t in JSON bodies or query strings.
+// Fields taken from the path are named after the field.
+func requestName(t reflect.Type, field string) string {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name, index, _ := strings.Cut(field, "[")
+	if index != "" {
+		index = "[" + index
+	}
+	if t != nil && t.Kind() == reflect.Struct {
+		if f, ok := t.FieldByName(name); ok {
+			for _, key := range []string{"json", "form"} {
+				tag, _, _ := strings.Cut(f.Tag.Get(key), ",")
+				if tag != "" && tag != "-" {
+					return tag + index
+				}
+			}
+		}
+	}
+	return strings.ToLower(name[:1]) + name[1:] + index
+}
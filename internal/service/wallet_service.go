@@ -2,65 +2,226 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"cmd/app/main.go/internal/db"
 	"cmd/app/main.go/internal/dto"
 	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
 
 	"github.com/google/uuid"
 )
 
 type Wallet interface {
-	Create(ctx context.Context) (uuid.UUID, error)
+	Create(ctx context.Context, req dto.WalletCreateRequest) (model.Wallet, error)
 	Transaction(ctx context.Context, req dto.WalletTransactionRequest) (model.Wallet, error)
 	Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, error)
+	OwnerWallets(ctx context.Context, ownerID string) ([]model.Wallet, error)
+	Transactions(ctx context.Context, req dto.TransactionHistoryRequest) (dto.TransactionHistoryResponse, error)
+	Idempotent(ctx context.Context, key string, fingerprint string, fn IdempotentFunc) (model.IdempotencyRecord, error)
+	PurgeIdempotencyKeys(ctx context.Context) (int64, error)
+	CreateHold(ctx context.Context, req dto.HoldCreateRequest) (model.Hold, error)
+	Hold(ctx context.Context, id uuid.UUID) (model.Hold, error)
+	CaptureHold(ctx context.Context, req dto.HoldCaptureRequest) (model.Hold, error)
+	VoidHold(ctx context.Context, id uuid.UUID) (model.Hold, error)
+	ExpireHolds(ctx context.Context) (int64, error)
+	SetStatus(ctx context.Context, req dto.WalletStatusRequest) (model.Wallet, error)
+	Operation(ctx context.Context, id uuid.UUID) (model.Operation, error)
+	Reverse(ctx context.Context, req dto.ReversalRequest) (model.Operation, error)
+	Limits(ctx context.Context, id uuid.UUID) (model.WalletLimits, error)
+	SetLimits(ctx context.Context, req dto.WalletLimitsRequest) (model.WalletLimits, error)
+	AuditLog(ctx context.Context, req dto.AuditLogRequest) (dto.AuditLogResponse, error)
+	CreateWebhook(ctx context.Context, req dto.WebhookCreateRequest) (model.Webhook, error)
+	Webhooks(ctx context.Context) ([]model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	WebhookDeliveries(ctx context.Context, req dto.WebhookDeliveriesRequest) ([]model.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, error)
+	WalletEvents(ctx context.Context, id uuid.UUID, after int64, overlap int, limit int) ([]model.Event, error)
 }
 
+// IdempotentFunc performs the operation guarded by an idempotency key and returns the response to store.
+// A non-nil error means the outcome must not be stored, so that the request can be retried.
+type IdempotentFunc func(ctx context.Context) (status int, body []byte, err error)
+
+// defaultIdempotencyRetention is how long idempotency keys are kept unless configured otherwise.
+const defaultIdempotencyRetention = 24 * time.Hour
+
+// defaultCurrency is the currency of wallets created without one unless configured otherwise.
+const defaultCurrency = "USD"
+
+// defaultHistoryLimit is the page size used when the client does not ask for one.
+const defaultHistoryLimit = 20
+
+var (
+	ErrInvalidCursor        = newError(KindValidation, "INVALID_CURSOR", "invalid cursor")
+	ErrInsufficientFunds    = newError(KindInsufficientFunds, "INSUFFICIENT_FUNDS", "insufficient funds")
+	ErrIdempotencyKeyReused = newError(KindUnprocessable, "IDEMPOTENCY_KEY_REUSED", "idempotency key reused with a different payload")
+	ErrCurrencyMismatch     = newError(KindUnprocessable, "CURRENCY_MISMATCH", "currency mismatch")
+	ErrAmountPrecision      = &Error{Kind: KindValidation, Code: "AMOUNT_PRECISION", Message: "amount has too many decimal places for the currency", Fields: []FieldError{{Field: "amount", Code: "precision", Message: "has too many decimal places for the currency"}}}
+	ErrWalletExists         = newError(KindConflict, "WALLET_EXISTS", "owner already has a wallet in the currency")
+)
+
 type wallet struct {
-	storage db.Storage
+	storage              db.Storage
+	idempotencyRetention time.Duration
+	defaultCurrency      string
+	rates                RateProvider
+	rounding             money.RoundingMode
+	holdTTL              time.Duration
+	frozenCredits        bool
+	uniqueOwnerCurrency  bool
+	audit                bool
+}
+
+// Option customizes the wallet service created by New.
+type Option func(*wallet)
+
+// WithIdempotencyRetention sets how long idempotency keys are remembered.
+func WithIdempotencyRetention(d time.Duration) Option {
+	return func(ws *wallet) {
+		ws.idempotencyRetention = d
+	}
 }
 
-func New(s db.Storage) Wallet {
-	return &wallet{
-		storage: s,
+// WithDefaultCurrency sets the ISO 4217 currency of wallets created without an explicit one.
+func WithDefaultCurrency(currency string) Option {
+	return func(ws *wallet) {
+		ws.defaultCurrency = currency
 	}
 }
 
+// WithUniqueOwnerCurrency limits owners to at most one wallet per currency.
+func WithUniqueOwnerCurrency(unique bool) Option {
+	return func(ws *wallet) {
+		ws.uniqueOwnerCurrency = unique
+	}
+}
+
+func New(s db.Storage, opts ...Option) Wallet {
+	ws := &wallet{
+		storage:              s,
+		idempotencyRetention: defaultIdempotencyRetention,
+		defaultCurrency:      defaultCurrency,
+		holdTTL:              defaultHoldTTL,
+		frozenCredits:        true,
+	}
+	for _, opt := range opts {
+		opt(ws)
+	}
+	return ws
+}
+
 // Create generates a new wallet with a unique identifier and saves it to persistent storage.
-// It generates a UUID, attempts to store the wallet, and returns the UUID upon success or an error otherwise.
-func (ws *wallet) Create(ctx context.Context) (uuid.UUID, error) {
-	uuid := uuid.New()
-	err := ws.storage.Create(ctx, uuid)
+// The wallet uses the requested currency or the default one when none is given, and belongs
+// to the requested owner, if any. When owners are limited to one wallet per currency,
+// creating another one fails with ErrWalletExists.
+// It returns the created wallet upon success or an error otherwise.
+func (ws *wallet) Create(ctx context.Context, req dto.WalletCreateRequest) (model.Wallet, error) {
+	res := model.Wallet{
+		UUID:     uuid.New(),
+		Currency: req.Currency,
+		Status:   model.WalletActive,
+	}
+	if res.Currency == "" {
+		res.Currency = ws.defaultCurrency
+	}
+	if req.OwnerID != "" {
+		res.OwnerID = &req.OwnerID
+	}
+	err := ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		err := ws.storage.Create(ctx, res, ws.uniqueOwnerCurrency)
+		return auditEntry{action: model.AuditWalletCreate, wallet: res.UUID, after: res}, err
+	})
+	if errors.Is(err, db.ErrWalletExists) {
+		return res, ErrWalletExists
+	}
 	if err != nil {
 		log.Println(err)
-		return uuid, fmt.Errorf("service create wallet error")
+		return res, fmt.Errorf("service create wallet error")
 	}
-	return uuid, nil
+	return res, nil
 }
 
-// Transaction performs deposit or withdrawal operations on a wallet based on the request type.
-// It determines the action (deposit or withdraw) and delegates the task to the storage layer accordingly.
-// Any errors encountered during the process are logged and returned.
+// Transaction performs deposit, withdrawal or transfer operations on a wallet based on the request type.
+// It determines the action and delegates the task to the storage layer accordingly.
+// For transfers the returned wallet is the debited one.
+// Any errors encountered during the process are logged and returned,
+// operations that would overdraw the debited wallet fail with ErrInsufficientFunds,
+// operations the wallet status does not allow fail with ErrWalletFrozen or ErrWalletClosed,
+// and operations exceeding a limit of a wallet fail with LimitError.
 func (ws *wallet) Transaction(ctx context.Context, req dto.WalletTransactionRequest) (model.Wallet, error) {
 	var res model.Wallet
-	var err error
-
-	switch req.Type {
-	case "DEPOSIT":
-		res, err = ws.storage.Deposit(ctx, req.UUID, req.Amount)
 
-	case "WITHDRAW":
-		res, err = ws.storage.Withdraw(ctx, req.UUID, req.Amount)
+	w, err := ws.checkCurrency(ctx, req.UUID, req.Currency, req.Amount)
+	if err == nil {
+		err = ws.checkStatus(w, req.Type != "DEPOSIT")
 	}
 	if err != nil {
 		log.Println("wallet service transaction err: ", err)
 		return res, err
 	}
+
+	err = ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		var err error
+		var delta money.Amount
+
+		switch req.Type {
+		case "DEPOSIT":
+			res, err = ws.storage.Deposit(ctx, req.UUID, req.Amount)
+			delta = req.Amount
+
+		case "WITHDRAW":
+			res, err = ws.storage.Withdraw(ctx, req.UUID, req.Amount)
+			delta = -req.Amount
+
+		case "TRANSFER":
+			var t db.Transfer
+			t, err = ws.prepareTransfer(ctx, w, req)
+			if err == nil {
+				res, err = ws.storage.Transfer(ctx, t)
+			}
+			delta = -t.Debit
+		}
+
+		// the wallet state before the change follows from the one after it, read under the row lock
+		before := res
+		before.Balance -= delta
+		before.Available -= delta
+		return auditEntry{action: req.Type, wallet: req.UUID, before: before, after: res}, err
+	})
+	if errors.Is(err, db.ErrInsufficientFunds) {
+		return res, ErrInsufficientFunds
+	}
+	if err != nil {
+		log.Println("wallet service transaction err: ", err)
+		return res, limitErr(statusErr(err))
+	}
 	return res, nil
 }
 
+// checkCurrency makes sure the operation is expressed in the currency of the wallet it debits or credits
+// and returns that wallet. It fails with ErrCurrencyMismatch if the declared currency differs from
+// the wallet currency, and with ErrAmountPrecision if the amount has more decimal places than
+// the currency allows. An empty currency is not checked.
+func (ws *wallet) checkCurrency(ctx context.Context, id uuid.UUID, currency string, amount money.Amount) (model.Wallet, error) {
+	w, err := ws.storage.Balance(ctx, id)
+	if err != nil {
+		return w, err
+	}
+	if currency != "" && currency != w.Currency {
+		return w, ErrCurrencyMismatch
+	}
+	if amount.Decimals() > money.Decimals(w.Currency) {
+		return w, ErrAmountPrecision
+	}
+	return w, nil
+}
+
 // Balance retrieves the current balance of a wallet identified by its UUID.
 // It forwards the request to the storage layer and returns the retrieved balance or an error.
 func (ws *wallet) Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, error) {
@@ -71,3 +232,135 @@ func (ws *wallet) Balance(ctx context.Context, uuid uuid.UUID) (model.Wallet, er
 	}
 	return res, nil
 }
+
+// OwnerWallets returns the wallets of an owner along with their balances.
+// An owner without wallets gets an empty list.
+func (ws *wallet) OwnerWallets(ctx context.Context, ownerID string) ([]model.Wallet, error) {
+	res, err := ws.storage.OwnerWallets(ctx, ownerID)
+	if err != nil {
+		log.Println("wallet service owner wallets err: ", err)
+		return nil, err
+	}
+	if res == nil {
+		res = []model.Wallet{}
+	}
+	return res, nil
+}
+
+// Transactions returns a page of the wallet's ledger entries, newest first.
+// It makes sure the wallet exists, decodes the opaque cursor into a ledger position
+// and fetches one extra entry to find out whether a next page is available.
+func (ws *wallet) Transactions(ctx context.Context, req dto.TransactionHistoryRequest) (dto.TransactionHistoryResponse, error) {
+	var res dto.TransactionHistoryResponse
+
+	beforeID, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return res, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+
+	_, err = ws.storage.Balance(ctx, req.UUID)
+	if err != nil {
+		log.Println("wallet service transactions err: ", err)
+		return res, err
+	}
+
+	filter := db.TransactionFilter{
+		UUID:     req.UUID,
+		BeforeID: beforeID,
+		Type:     req.Type,
+		From:     req.From,
+		To:       req.To,
+		Limit:    limit + 1,
+	}
+	items, err := ws.storage.Transactions(ctx, filter)
+	if err != nil {
+		log.Println("wallet service transactions err: ", err)
+		return res, err
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+		res.NextCursor = encodeCursor(items[limit-1].ID)
+	}
+	res.Transactions = items
+	return res, nil
+}
+
+// encodeCursor turns a ledger entry id into an opaque pagination cursor.
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodeCursor extracts the ledger entry id from a cursor produced by encodeCursor.
+// An empty cursor means the first page and decodes to zero.
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// Idempotent runs fn at most once per idempotency key within the retention window.
+// The key is claimed, fn is executed and its response is stored in one database transaction,
+// so the operation performed by fn and the stored response are committed together.
+// Repeated requests get the stored response back with Replayed set, unless their payload
+// fingerprint differs from the original one, in which case ErrIdempotencyKeyReused is returned.
+func (ws *wallet) Idempotent(ctx context.Context, key string, fingerprint string, fn IdempotentFunc) (model.IdempotencyRecord, error) {
+	var res model.IdempotencyRecord
+	err := ws.storage.WithTx(ctx, func(ctx context.Context) error {
+		claimed, err := ws.storage.ClaimIdempotencyKey(ctx, key, fingerprint, ws.idempotencyRetention)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			res, err = ws.storage.IdempotencyRecord(ctx, key)
+			if err != nil {
+				return err
+			}
+			if res.Fingerprint != fingerprint {
+				return ErrIdempotencyKeyReused
+			}
+			res.Replayed = true
+			return nil
+		}
+
+		status, body, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		res = model.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			Status:      status,
+			Body:        body,
+		}
+		return ws.storage.SaveIdempotencyResponse(ctx, key, status, body)
+	})
+	if err != nil && !errors.Is(err, ErrIdempotencyKeyReused) {
+		log.Println("wallet service idempotent err: ", err)
+	}
+	return res, err
+}
+
+// PurgeIdempotencyKeys deletes the idempotency keys past the retention window and returns how many were deleted.
+func (ws *wallet) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	n, err := ws.storage.PurgeIdempotencyKeys(ctx, ws.idempotencyRetention)
+	if err != nil {
+		log.Println("wallet service purge idempotency keys err: ", err)
+		return n, err
+	}
+	return n, nil
+}
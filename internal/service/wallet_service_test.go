@@ -1,14 +1,20 @@
 package service
 
 import (
+	"cmd/app/main.go/internal/db"
 	mocks "cmd/app/main.go/internal/db/mock"
 	"cmd/app/main.go/internal/dto"
 	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 func TestWalletServiceCreate(t *testing.T) {
@@ -18,16 +24,54 @@ func TestWalletServiceCreate(t *testing.T) {
 	ws := New(fakeDB)
 
 	t.Run("TestWalletServiceCreate_Success", func(t *testing.T) {
-		fakeDB.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
-		_, err := ws.Create(t.Context())
+		fakeDB.EXPECT().Create(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, w model.Wallet, _ bool) error {
+			if w.Currency != "USD" || w.OwnerID != nil {
+				t.Errorf("Expected: %v, recieved: %v", "USD", w)
+			}
+			return nil
+		})
+		wallet, err := ws.Create(t.Context(), dto.WalletCreateRequest{})
 		if err != nil {
 			t.Error("create err")
 		}
+		if wallet.Currency != "USD" {
+			t.Errorf("Expected: %v, recieved: %v", "USD", wallet.Currency)
+		}
+	})
+
+	t.Run("TestWalletServiceCreate_Currency", func(t *testing.T) {
+		fakeDB.EXPECT().Create(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, w model.Wallet, _ bool) error {
+			if w.Currency != "JPY" {
+				t.Errorf("Expected: %v, recieved: %v", "JPY", w.Currency)
+			}
+			return nil
+		})
+		wallet, err := ws.Create(t.Context(), dto.WalletCreateRequest{Currency: "JPY"})
+		if err != nil {
+			t.Error("create err")
+		}
+		if wallet.Currency != "JPY" {
+			t.Errorf("Expected: %v, recieved: %v", "JPY", wallet.Currency)
+		}
+	})
+
+	t.Run("TestWalletServiceCreate_Owner", func(t *testing.T) {
+		unique := New(fakeDB, WithUniqueOwnerCurrency(true))
+		fakeDB.EXPECT().Create(gomock.Any(), gomock.Any(), true).DoAndReturn(func(_ context.Context, w model.Wallet, _ bool) error {
+			if w.OwnerID == nil || *w.OwnerID != "user-42" {
+				t.Errorf("Expected: %v, recieved: %v", "user-42", w.OwnerID)
+			}
+			return db.ErrWalletExists
+		})
+		_, err := unique.Create(t.Context(), dto.WalletCreateRequest{OwnerID: "user-42"})
+		if !errors.Is(err, ErrWalletExists) {
+			t.Errorf("Expected: %v, recieved: %v", ErrWalletExists, err)
+		}
 	})
 
 	t.Run("TestWalletServiceCreate_Fail", func(t *testing.T) {
-		fakeDB.EXPECT().Create(gomock.Any(), gomock.Any()).Return(fmt.Errorf("db random err"))
-		_, err := ws.Create(t.Context())
+		fakeDB.EXPECT().Create(gomock.Any(), gomock.Any(), false).Return(fmt.Errorf("db random err"))
+		_, err := ws.Create(t.Context(), dto.WalletCreateRequest{})
 		expErr := fmt.Errorf("service create wallet error")
 		if err.Error() != expErr.Error() {
 			t.Errorf("create err. Expected: %v, recieved: %v", expErr, err)
@@ -35,6 +79,36 @@ func TestWalletServiceCreate(t *testing.T) {
 	})
 }
 
+func TestWalletServiceOwnerWallets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceOwnerWallets_Success", func(t *testing.T) {
+		fakeWallets := []model.Wallet{{UUID: uuid.New(), Currency: "USD"}, {UUID: uuid.New(), Currency: "EUR"}}
+		fakeDB.EXPECT().OwnerWallets(gomock.Any(), "user-42").Return(fakeWallets, nil)
+		wallets, err := ws.OwnerWallets(t.Context(), "user-42")
+		if err != nil {
+			t.Error("owner wallets err: ", err)
+		}
+		if len(wallets) != len(fakeWallets) {
+			t.Errorf("Expected: %v, recieved: %v", fakeWallets, wallets)
+		}
+	})
+
+	t.Run("TestWalletServiceOwnerWallets_Empty", func(t *testing.T) {
+		fakeDB.EXPECT().OwnerWallets(gomock.Any(), "nobody").Return(nil, nil)
+		wallets, err := ws.OwnerWallets(t.Context(), "nobody")
+		if err != nil {
+			t.Error("owner wallets err: ", err)
+		}
+		if wallets == nil || len(wallets) != 0 {
+			t.Errorf("Expected: %v, recieved: %v", []model.Wallet{}, wallets)
+		}
+	})
+}
+
 func TestWalletServiceBalance(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -45,7 +119,7 @@ func TestWalletServiceBalance(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, nil)
 		wallet, err := ws.Balance(t.Context(), fakeUUID)
@@ -61,7 +135,7 @@ func TestWalletServiceBalance(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 		fakeErr := fmt.Errorf("random db err")
 		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, fakeErr)
@@ -82,13 +156,14 @@ func TestWalletServiceTransaction(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 		fakeReq := dto.WalletTransactionRequest{
 			UUID:   fakeUUID,
 			Type:   "DEPOSIT",
-			Amount: 100,
+			Amount: money.MustParse("100"),
 		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
 		fakeDB.EXPECT().Deposit(gomock.Any(), fakeUUID, fakeReq.Amount).Return(fakeWallet, nil)
 		wallet, err := ws.Transaction(t.Context(), fakeReq)
 		if err != nil {
@@ -103,14 +178,15 @@ func TestWalletServiceTransaction(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 		fakeReq := dto.WalletTransactionRequest{
 			UUID:   fakeUUID,
 			Type:   "DEPOSIT",
-			Amount: 100,
+			Amount: money.MustParse("100"),
 		}
 		fakeErr := fmt.Errorf("random db err")
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
 		fakeDB.EXPECT().Deposit(gomock.Any(), fakeUUID, fakeReq.Amount).Return(fakeWallet, fakeErr)
 		_, err := ws.Transaction(t.Context(), fakeReq)
 		if err.Error() != fakeErr.Error() {
@@ -122,13 +198,14 @@ func TestWalletServiceTransaction(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 		fakeReq := dto.WalletTransactionRequest{
 			UUID:   fakeUUID,
 			Type:   "WITHDRAW",
-			Amount: 100,
+			Amount: money.MustParse("100"),
 		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
 		fakeDB.EXPECT().Withdraw(gomock.Any(), fakeUUID, fakeReq.Amount).Return(fakeWallet, nil)
 		wallet, err := ws.Transaction(t.Context(), fakeReq)
 		if err != nil {
@@ -139,18 +216,34 @@ func TestWalletServiceTransaction(t *testing.T) {
 		}
 	})
 
+	t.Run("TestWalletServiceTransactionWithdraw_InsufficientFunds", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "WITHDRAW",
+			Amount: money.MustParse("100"),
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Withdraw(gomock.Any(), fakeUUID, fakeReq.Amount).Return(model.Wallet{}, db.ErrInsufficientFunds)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrInsufficientFunds) {
+			t.Errorf("Expected: %v, recieved: %v", ErrInsufficientFunds, err)
+		}
+	})
+
 	t.Run("TestWalletServiceTransactionWithdraw_Fail", func(t *testing.T) {
 		fakeUUID := uuid.New()
 		fakeWallet := model.Wallet{
 			UUID:    fakeUUID,
-			Balance: 100,
+			Balance: money.MustParse("100"),
 		}
 		fakeReq := dto.WalletTransactionRequest{
 			UUID:   fakeUUID,
 			Type:   "WITHDRAW",
-			Amount: 100,
+			Amount: money.MustParse("100"),
 		}
 		fakeErr := fmt.Errorf("random db err")
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
 		fakeDB.EXPECT().Withdraw(gomock.Any(), fakeUUID, fakeReq.Amount).Return(fakeWallet, fakeErr)
 		_, err := ws.Transaction(t.Context(), fakeReq)
 		if err.Error() != fakeErr.Error() {
@@ -158,3 +251,315 @@ func TestWalletServiceTransaction(t *testing.T) {
 		}
 	})
 }
+
+func TestWalletServiceTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceTransactions_Success", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeWallet := model.Wallet{
+			UUID:    fakeUUID,
+			Balance: money.MustParse("100"),
+		}
+		fakeItems := []model.Transaction{
+			{ID: 3, UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("50"), Balance: money.MustParse("100")},
+			{ID: 2, UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("50"), Balance: money.MustParse("50")},
+			{ID: 1, UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("0"), Balance: money.MustParse("0")},
+		}
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID:   fakeUUID,
+			Cursor: encodeCursor(4),
+			Limit:  2,
+		}
+		fakeFilter := db.TransactionFilter{
+			UUID:     fakeUUID,
+			BeforeID: 4,
+			Limit:    3,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, nil)
+		fakeDB.EXPECT().Transactions(gomock.Any(), fakeFilter).Return(fakeItems, nil)
+		res, err := ws.Transactions(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transactions err: ", err)
+		}
+		if len(res.Transactions) != 2 {
+			t.Errorf("Expected: %d transactions, recieved: %d", 2, len(res.Transactions))
+		}
+		if res.NextCursor != encodeCursor(2) {
+			t.Errorf("Expected: %v, recieved: %v", encodeCursor(2), res.NextCursor)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactions_LastPage", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeWallet := model.Wallet{
+			UUID:    fakeUUID,
+			Balance: money.MustParse("100"),
+		}
+		fakeItems := []model.Transaction{
+			{ID: 1, UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("100"), Balance: money.MustParse("100")},
+		}
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID: fakeUUID,
+		}
+		fakeFilter := db.TransactionFilter{
+			UUID:  fakeUUID,
+			Limit: defaultHistoryLimit + 1,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, nil)
+		fakeDB.EXPECT().Transactions(gomock.Any(), fakeFilter).Return(fakeItems, nil)
+		res, err := ws.Transactions(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transactions err: ", err)
+		}
+		if res.NextCursor != "" {
+			t.Errorf("Expected empty cursor, recieved: %v", res.NextCursor)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactions_InvalidCursor", func(t *testing.T) {
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID:   uuid.New(),
+			Cursor: "not a cursor",
+		}
+		_, err := ws.Transactions(t.Context(), fakeReq)
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected: %v, recieved: %v", ErrInvalidCursor, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactions_WalletNotFound", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.TransactionHistoryRequest{
+			UUID: fakeUUID,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{}, pgx.ErrNoRows)
+		_, err := ws.Transactions(t.Context(), fakeReq)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("Expected: %v, recieved: %v", pgx.ErrNoRows, err)
+		}
+	})
+}
+
+func TestWalletServiceTransfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceTransfer_Success", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeToUUID := uuid.New()
+		fakeWallet := model.Wallet{
+			UUID:    fakeUUID,
+			Balance: money.MustParse("100"),
+		}
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse("100"),
+			ToUUID: fakeToUUID,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeToUUID).Return(model.Wallet{UUID: fakeToUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Transfer(gomock.Any(), db.Transfer{From: fakeUUID, To: fakeToUUID, Debit: fakeReq.Amount, Credit: fakeReq.Amount}).Return(fakeWallet, nil)
+		wallet, err := ws.Transaction(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transfer err")
+		}
+		if wallet != fakeWallet {
+			t.Errorf("Expected: %v, recieved: %v", fakeWallet, wallet)
+		}
+	})
+
+	t.Run("TestWalletServiceTransfer_Fail", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeToUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse("100"),
+			ToUUID: fakeToUUID,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeToUUID).Return(model.Wallet{UUID: fakeToUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Transfer(gomock.Any(), db.Transfer{From: fakeUUID, To: fakeToUUID, Debit: fakeReq.Amount, Credit: fakeReq.Amount}).Return(model.Wallet{}, pgx.ErrNoRows)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, pgx.ErrNoRows) {
+			t.Errorf("Expected: %v, recieved: %v", pgx.ErrNoRows, err)
+		}
+	})
+}
+
+func TestWalletServiceIdempotent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB, WithIdempotencyRetention(time.Hour))
+
+	runTx := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}
+
+	t.Run("TestWalletServiceIdempotent_FirstRequest", func(t *testing.T) {
+		fakeBody := []byte(`{"success":true}`)
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().ClaimIdempotencyKey(gomock.Any(), "key-1", "fp", time.Hour).Return(true, nil)
+		fakeDB.EXPECT().SaveIdempotencyResponse(gomock.Any(), "key-1", 200, fakeBody).Return(nil)
+
+		calls := 0
+		rec, err := ws.Idempotent(t.Context(), "key-1", "fp", func(ctx context.Context) (int, []byte, error) {
+			calls++
+			return 200, fakeBody, nil
+		})
+		if err != nil {
+			t.Error("idempotent err: ", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected fn to be called once, called: %d", calls)
+		}
+		if rec.Replayed || rec.Status != 200 || string(rec.Body) != string(fakeBody) {
+			t.Errorf("Expected fresh response, recieved: %v", rec)
+		}
+	})
+
+	t.Run("TestWalletServiceIdempotent_Replay", func(t *testing.T) {
+		fakeRec := model.IdempotencyRecord{
+			Key:         "key-2",
+			Fingerprint: "fp",
+			Status:      200,
+			Body:        []byte(`{"success":true}`),
+		}
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().ClaimIdempotencyKey(gomock.Any(), "key-2", "fp", time.Hour).Return(false, nil)
+		fakeDB.EXPECT().IdempotencyRecord(gomock.Any(), "key-2").Return(fakeRec, nil)
+
+		rec, err := ws.Idempotent(t.Context(), "key-2", "fp", func(ctx context.Context) (int, []byte, error) {
+			t.Error("fn must not be called on replay")
+			return 0, nil, nil
+		})
+		if err != nil {
+			t.Error("idempotent err: ", err)
+		}
+		if !rec.Replayed || rec.Status != fakeRec.Status {
+			t.Errorf("Expected replayed response, recieved: %v", rec)
+		}
+	})
+
+	t.Run("TestWalletServiceIdempotent_KeyReused", func(t *testing.T) {
+		fakeRec := model.IdempotencyRecord{
+			Key:         "key-3",
+			Fingerprint: "other",
+			Status:      200,
+		}
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().ClaimIdempotencyKey(gomock.Any(), "key-3", "fp", time.Hour).Return(false, nil)
+		fakeDB.EXPECT().IdempotencyRecord(gomock.Any(), "key-3").Return(fakeRec, nil)
+
+		_, err := ws.Idempotent(t.Context(), "key-3", "fp", func(ctx context.Context) (int, []byte, error) {
+			t.Error("fn must not be called for a reused key")
+			return 0, nil, nil
+		})
+		if !errors.Is(err, ErrIdempotencyKeyReused) {
+			t.Errorf("Expected: %v, recieved: %v", ErrIdempotencyKeyReused, err)
+		}
+	})
+
+	t.Run("TestWalletServiceIdempotent_NotStored", func(t *testing.T) {
+		fakeErr := fmt.Errorf("random db err")
+		fakeDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(runTx)
+		fakeDB.EXPECT().ClaimIdempotencyKey(gomock.Any(), "key-4", "fp", time.Hour).Return(true, nil)
+
+		_, err := ws.Idempotent(t.Context(), "key-4", "fp", func(ctx context.Context) (int, []byte, error) {
+			return 500, nil, fakeErr
+		})
+		if !errors.Is(err, fakeErr) {
+			t.Errorf("Expected: %v, recieved: %v", fakeErr, err)
+		}
+	})
+
+	t.Run("TestWalletServiceIdempotent_Purge", func(t *testing.T) {
+		fakeDB.EXPECT().PurgeIdempotencyKeys(gomock.Any(), time.Hour).Return(int64(3), nil)
+
+		n, err := ws.PurgeIdempotencyKeys(t.Context())
+		if err != nil {
+			t.Error("purge idempotency keys err: ", err)
+		}
+		if n != 3 {
+			t.Errorf("Expected: %v, recieved: %v", 3, n)
+		}
+	})
+}
+
+func TestWalletServiceTransactionCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	ws := New(fakeDB)
+
+	t.Run("TestWalletServiceTransactionCurrency_Mismatch", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:     fakeUUID,
+			Type:     "DEPOSIT",
+			Amount:   money.MustParse("100"),
+			Currency: "EUR",
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("Expected: %v, recieved: %v", ErrCurrencyMismatch, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionCurrency_Precision", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "DEPOSIT",
+			Amount: money.MustParse("100.5"),
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "JPY"}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrAmountPrecision) {
+			t.Errorf("Expected: %v, recieved: %v", ErrAmountPrecision, err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionCurrency_ThreeDecimals", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeWallet := model.Wallet{UUID: fakeUUID, Currency: "BHD"}
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:     fakeUUID,
+			Type:     "DEPOSIT",
+			Amount:   money.MustParse("1.125"),
+			Currency: "BHD",
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(fakeWallet, nil)
+		fakeDB.EXPECT().Deposit(gomock.Any(), fakeUUID, fakeReq.Amount).Return(fakeWallet, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if err != nil {
+			t.Error("transaction err: ", err)
+		}
+	})
+
+	t.Run("TestWalletServiceTransactionCurrency_TransferMismatch", func(t *testing.T) {
+		fakeUUID := uuid.New()
+		fakeToUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{
+			UUID:   fakeUUID,
+			Type:   "TRANSFER",
+			Amount: money.MustParse("100"),
+			ToUUID: fakeToUUID,
+		}
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, Currency: "USD"}, nil)
+		fakeDB.EXPECT().Balance(gomock.Any(), fakeToUUID).Return(model.Wallet{UUID: fakeToUUID, Currency: "EUR"}, nil)
+		_, err := ws.Transaction(t.Context(), fakeReq)
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("Expected: %v, recieved: %v", ErrCurrencyMismatch, err)
+		}
+	})
+}
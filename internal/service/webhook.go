@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"cmd/app/main.go/internal/db"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhook registers an endpoint receiving the events of the requested types.
+func (ws *wallet) CreateWebhook(ctx context.Context, req dto.WebhookCreateRequest) (model.Webhook, error) {
+	var res model.Webhook
+	err := ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		var err error
+		res, err = ws.storage.CreateWebhook(ctx, model.Webhook{
+			ID:         uuid.New(),
+			URL:        req.URL,
+			Secret:     req.Secret,
+			EventTypes: req.EventTypes,
+		})
+		return auditEntry{action: model.AuditWebhookCreate, after: res}, err
+	})
+	if err != nil {
+		log.Println("wallet service create webhook err: ", err)
+		return res, err
+	}
+	return res, nil
+}
+
+// Webhooks returns the registered webhooks.
+func (ws *wallet) Webhooks(ctx context.Context) ([]model.Webhook, error) {
+	res, err := ws.storage.Webhooks(ctx)
+	if err != nil {
+		log.Println("wallet service webhooks err: ", err)
+		return nil, err
+	}
+	if res == nil {
+		res = []model.Webhook{}
+	}
+	return res, nil
+}
+
+// DeleteWebhook removes a webhook, cancelling its pending deliveries.
+func (ws *wallet) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	err := ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		before, err := ws.storage.DeleteWebhook(ctx, id)
+		return auditEntry{action: model.AuditWebhookDelete, before: before}, err
+	})
+	if err != nil {
+		log.Println("wallet service delete webhook err: ", err)
+		return err
+	}
+	return nil
+}
+
+// WebhookDeliveries returns the latest deliveries of a webhook, newest first.
+func (ws *wallet) WebhookDeliveries(ctx context.Context, req dto.WebhookDeliveriesRequest) ([]model.WebhookDelivery, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultHistoryLimit
+	}
+	res, err := ws.storage.WebhookDeliveries(ctx, db.DeliveryFilter{
+		WebhookID: req.WebhookID,
+		Status:    req.Status,
+		Limit:     limit,
+	})
+	if err != nil {
+		log.Println("wallet service webhook deliveries err: ", err)
+		return nil, err
+	}
+	if res == nil {
+		res = []model.WebhookDelivery{}
+	}
+	return res, nil
+}
+
+// Redeliver schedules a delivery, dead or not, to be attempted again right away with a fresh attempt budget.
+func (ws *wallet) Redeliver(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, error) {
+	var res model.WebhookDelivery
+	err := ws.audited(ctx, func(ctx context.Context) (auditEntry, error) {
+		var err error
+		res, err = ws.storage.Redeliver(ctx, id)
+		return auditEntry{action: model.AuditRedeliver, after: res}, err
+	})
+	if err != nil {
+		log.Println("wallet service redeliver err: ", err)
+		return res, err
+	}
+	return res, nil
+}
@@ -0,0 +1,342 @@
+package socket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/internal/stream"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+// Machine-readable codes of the errors raised by the connection, the same as those of the REST API.
+// Errors of the wallet service carry their own codes.
+const (
+	codeForbidden            = "FORBIDDEN"
+	codeInvalidSignature     = "INVALID_SIGNATURE"
+	codeRateLimited          = "RATE_LIMITED"
+	codeTooManyStreams       = "TOO_MANY_STREAMS"
+	codeTooManySubscriptions = "TOO_MANY_SUBSCRIPTIONS"
+	codeUnavailable          = "UNAVAILABLE"
+)
+
+// reasonDropped tells the client a subscription was ended by the server, which happens when the
+// client falls behind or the server loses track of events. Events may have been missed meanwhile.
+const reasonDropped = "DROPPED"
+
+// conn serves a WebSocket connection. Commands are read one at a time: subscriptions are handled
+// in order while operations run concurrently up to the in-flight limit. Every message is written by
+// a single writer from a bounded queue.
+type conn struct {
+	server *Server
+	ws     *websocket.Conn
+	ctx    context.Context
+	client string
+
+	out      chan any
+	slots    chan struct{}
+	inflight sync.WaitGroup
+	pushers  sync.WaitGroup
+
+	mu   sync.Mutex
+	subs map[uuid.UUID]*stream.Subscription
+}
+
+func newConn(s *Server, ws *websocket.Conn) *conn {
+	return &conn{
+		server: s,
+		ws:     ws,
+		ctx:    ws.Request().Context(),
+		client: "socket:" + uuid.NewString(),
+		out:    make(chan any, s.buffer),
+		slots:  make(chan struct{}, s.maxInFlight),
+		subs:   make(map[uuid.UUID]*stream.Subscription),
+	}
+}
+
+// run serves the connection until it stops being read, then lets the operations in flight
+// answer before closing it.
+func (c *conn) run() {
+	written := make(chan struct{})
+	go func() {
+		c.write()
+		close(written)
+	}()
+
+	c.read()
+	c.inflight.Wait()
+
+	c.mu.Lock()
+	subs := c.subs
+	c.subs = make(map[uuid.UUID]*stream.Subscription)
+	c.mu.Unlock()
+	for _, sub := range subs {
+		sub.Close()
+	}
+	c.pushers.Wait()
+
+	close(c.out)
+	<-written
+	c.ws.Close()
+}
+
+// stopReading makes the pending read fail, ending the connection once the operations in flight are answered.
+func (c *conn) stopReading() {
+	c.ws.SetReadDeadline(time.Now())
+}
+
+// abort makes every pending read and write fail, ending the connection at once.
+func (c *conn) abort() {
+	c.ws.SetDeadline(time.Now())
+}
+
+func (c *conn) read() {
+	for {
+		var cmd Command
+		err := websocket.JSON.Receive(c.ws, &cmd)
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, websocket.ErrFrameTooLarge) {
+			c.reply(failure(cmd.ID, service.CodeValidationFailed, fmt.Sprint("validation err: ", err)))
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		err = c.server.validator.Struct(cmd)
+		if err != nil {
+			c.reply(failure(cmd.ID, service.CodeValidationFailed, fmt.Sprint("validation err: ", err)))
+			continue
+		}
+
+		switch cmd.Type {
+		case CommandSubscribe:
+			c.reply(c.subscribe(cmd))
+		case CommandUnsubscribe:
+			c.reply(c.unsubscribe(cmd))
+		default:
+			c.slots <- struct{}{}
+			c.inflight.Add(1)
+			go func() {
+				defer func() {
+					<-c.slots
+					c.inflight.Done()
+				}()
+				c.reply(c.transaction(cmd))
+			}()
+		}
+	}
+}
+
+// write sends the queued messages until the queue is closed. Once a write fails the connection
+// is aborted and the remaining messages are discarded.
+func (c *conn) write() {
+	failed := false
+	for m := range c.out {
+		if failed {
+			continue
+		}
+		c.ws.SetWriteDeadline(time.Now().Add(c.server.writeTimeout))
+		err := websocket.JSON.Send(c.ws, m)
+		if err != nil {
+			failed = true
+			c.abort()
+		}
+	}
+}
+
+// reply queues the result of a command, waiting for room in the queue.
+func (c *conn) reply(r Result) {
+	c.out <- r
+}
+
+// push queues a message without waiting. A client too slow to receive its events is disconnected.
+func (c *conn) push(p Push) {
+	select {
+	case c.out <- p:
+	default:
+		c.abort()
+	}
+}
+
+// subscribe starts pushing the events of the wallet and replies with the wallet.
+// Subscribing again to a wallet only replies with the wallet.
+func (c *conn) subscribe(cmd Command) Result {
+	if res, ok := c.authorize(cmd, auth.ScopeWalletsRead); !ok {
+		return res
+	}
+
+	c.mu.Lock()
+	_, subscribed := c.subs[cmd.WalletID]
+	n := len(c.subs)
+	c.mu.Unlock()
+	if !subscribed && n >= c.server.maxSubscriptions {
+		return failure(cmd.ID, codeTooManySubscriptions, "too many subscriptions")
+	}
+
+	// Subscribing before reading the wallet ensures no event falls in between.
+	var sub *stream.Subscription
+	if !subscribed {
+		var err error
+		sub, err = c.server.broker.Subscribe(cmd.WalletID, c.client)
+		if errors.Is(err, stream.ErrTooManyStreams) {
+			return failure(cmd.ID, codeTooManyStreams, err.Error())
+		}
+		if err != nil {
+			return failure(cmd.ID, codeUnavailable, "event streams are unavailable")
+		}
+	}
+
+	w, res, ok := c.wallet(cmd)
+	if !ok {
+		if sub != nil {
+			sub.Close()
+		}
+		return res
+	}
+
+	if sub != nil {
+		c.mu.Lock()
+		c.subs[cmd.WalletID] = sub
+		c.mu.Unlock()
+		c.pushers.Add(1)
+		go c.forward(cmd.WalletID, sub)
+	}
+	return success(cmd.ID, w)
+}
+
+// unsubscribe stops pushing the events of the wallet.
+func (c *conn) unsubscribe(cmd Command) Result {
+	c.mu.Lock()
+	sub, ok := c.subs[cmd.WalletID]
+	delete(c.subs, cmd.WalletID)
+	c.mu.Unlock()
+
+	if ok {
+		sub.Close()
+	}
+	return success(cmd.ID, "unsubscribed")
+}
+
+// forward pushes the events of a subscription until it ends, telling the client
+// if it was not ended by the client.
+func (c *conn) forward(id uuid.UUID, sub *stream.Subscription) {
+	defer c.pushers.Done()
+	for {
+		select {
+		case e := <-sub.Events():
+			c.push(Push{Type: MessageEvent, WalletID: id, Event: &e})
+		case <-sub.Done():
+			c.mu.Lock()
+			dropped := c.subs[id] == sub
+			if dropped {
+				delete(c.subs, id)
+			}
+			c.mu.Unlock()
+			if dropped {
+				c.push(Push{Type: MessageUnsubscribed, WalletID: id, Reason: reasonDropped})
+			}
+			return
+		}
+	}
+}
+
+// transaction deposits to or withdraws from the wallet and replies with the wallet.
+// The command id is recorded in the audit log along with the id of the connection request.
+func (c *conn) transaction(cmd Command) Result {
+	if res, ok := c.authorize(cmd, auth.ScopeWalletsWrite); !ok {
+		return res
+	}
+
+	req := dto.WalletTransactionRequest{
+		UUID:   cmd.WalletID,
+		Type:   strings.ToUpper(cmd.Type),
+		Amount: cmd.Amount,
+	}
+	err := c.server.validator.Struct(req)
+	if err != nil {
+		return failure(cmd.ID, service.CodeValidationFailed, fmt.Sprint("validation err: ", err))
+	}
+
+	p, _ := auth.FromContext(c.ctx)
+	if p.Owner == "" && c.server.signed {
+		return failure(cmd.ID, codeInvalidSignature, "operations must be signed, send them to POST /api/v1/wallet")
+	}
+	if p.Owner != "" {
+		if _, res, ok := c.wallet(cmd); !ok {
+			return res
+		}
+	}
+
+	if c.server.limiter != nil {
+		res, err := c.server.limiter.Allow(c.ctx, "wallet:"+cmd.WalletID.String(), c.server.walletLimit)
+		if err == nil && !res.Allowed {
+			return failure(cmd.ID, codeRateLimited, "rate limit exceeded")
+		}
+	}
+
+	ctx := c.ctx
+	if info, ok := service.RequestInfoFromContext(ctx); ok {
+		info.RequestID += ":" + cmd.ID
+		ctx = service.WithRequestInfo(ctx, info)
+	}
+
+	w, err := c.server.walletService.Transaction(ctx, req)
+	if err != nil {
+		return serviceFailure(cmd.ID, err)
+	}
+	return success(cmd.ID, w)
+}
+
+// authorize reports whether the principal of the connection was granted scope.
+// The result to reply with is returned if it was not. Without authentication every command is allowed.
+func (c *conn) authorize(cmd Command, scope string) (Result, bool) {
+	p, ok := auth.FromContext(c.ctx)
+	if ok && !p.HasScope(scope) {
+		return failure(cmd.ID, codeForbidden, "missing scope "+scope), false
+	}
+	return Result{}, true
+}
+
+// wallet reads the wallet of the command. Wallets the principal may not access are reported as not found.
+// The result to reply with is returned if the wallet cannot be read.
+func (c *conn) wallet(cmd Command) (model.Wallet, Result, bool) {
+	w, err := c.server.walletService.Balance(c.ctx, cmd.WalletID)
+	if err != nil {
+		return w, serviceFailure(cmd.ID, err), false
+	}
+	p, _ := auth.FromContext(c.ctx)
+	if !p.CanAccess(w.OwnerID) {
+		return w, serviceFailure(cmd.ID, service.ErrWalletNotFound), false
+	}
+	return w, Result{}, true
+}
+
+func success(id string, message any) Result {
+	return Result{ID: id, Type: MessageResult, Success: true, Message: message}
+}
+
+func failure(id string, code string, message string) Result {
+	return Result{ID: id, Type: MessageResult, Code: code, Message: message}
+}
+
+// serviceFailure maps an error of the wallet service to a result. Unknown resources are reported
+// as unknown wallets and unexpected errors are hidden.
+func serviceFailure(id string, err error) Result {
+	e := service.AsError(err, service.ErrWalletNotFound)
+	if e.Kind == service.KindInternal {
+		return failure(id, service.CodeInternal, "wallet service err")
+	}
+	return failure(id, e.Code, e.Message)
+}
@@ -0,0 +1,56 @@
+package socket
+
+import (
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Commands a client may send.
+const (
+	CommandSubscribe   = "subscribe"
+	CommandUnsubscribe = "unsubscribe"
+	CommandDeposit     = "deposit"
+	CommandWithdraw    = "withdraw"
+)
+
+// Messages the server sends.
+const (
+	MessageResult       = "result"
+	MessageEvent        = "event"
+	MessageUnsubscribed = "unsubscribed"
+)
+
+// Reasons for ending a subscription the client did not cancel.
+const (
+	reasonLagging  = "LAGGING"
+	reasonShutdown = "SHUTDOWN"
+)
+
+// Command is a message sent by the client. ID correlates the command with its result.
+type Command struct {
+	ID       string       `json:"id" validate:"required,max=64"`
+	Type     string       `json:"type" validate:"required,oneof=subscribe unsubscribe deposit withdraw"`
+	WalletID uuid.UUID    `json:"walletId" validate:"required"`
+	Amount   money.Amount `json:"amount"`
+}
+
+// Result answers the command with the same ID. It has the shape of the REST responses:
+// Message carries the wallet on success and a description of the error with Code otherwise.
+type Result struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Success bool   `json:"success"`
+	Code    string `json:"code,omitempty"`
+	Message any    `json:"message"`
+}
+
+// Push is a message the server sends on its own: an event of a subscribed wallet,
+// or the end of a subscription with its reason.
+type Push struct {
+	Type     string       `json:"type"`
+	WalletID uuid.UUID    `json:"walletId"`
+	Event    *model.Event `json:"event,omitempty"`
+	Reason   string       `json:"reason,omitempty"`
+}
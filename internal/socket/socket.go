@@ -0,0 +1,187 @@
+// Package socket serves the wallet API over WebSocket connections carrying JSON messages. A client
+// sends commands, each with an id echoed in its reply, and receives on the same connection the events
+// of the wallets it subscribed to.
+package socket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"cmd/app/main.go/internal/ratelimit"
+	"cmd/app/main.go/internal/service"
+	"cmd/app/main.go/internal/stream"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/net/websocket"
+)
+
+// Default connection settings used unless configured otherwise.
+const (
+	defaultBuffer           = 64
+	defaultMaxInFlight      = 16
+	defaultMaxSubscriptions = 50
+	defaultWriteTimeout     = 10 * time.Second
+	maxMessageSize          = 64 << 10
+)
+
+// Server accepts WebSocket connections and serves their commands with the wallet service.
+type Server struct {
+	walletService service.Wallet
+	broker        *stream.Broker
+	validator     *validator.Validate
+
+	buffer           int
+	maxInFlight      int
+	maxSubscriptions int
+	writeTimeout     time.Duration
+
+	limiter     ratelimit.Limiter
+	walletLimit ratelimit.Limit
+	signed      bool
+
+	mu     sync.Mutex
+	conns  map[*conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Option customizes the server created by New.
+type Option func(*Server)
+
+// WithBuffer sets how many messages may wait to be written to a connection. Commands wait for room
+// in the buffer while event pushes that do not fit end the connection.
+func WithBuffer(n int) Option {
+	return func(s *Server) {
+		s.buffer = n
+	}
+}
+
+// WithMaxInFlight sets how many operations of a connection may run at once. Once they are
+// all running the connection is not read until one completes.
+func WithMaxInFlight(n int) Option {
+	return func(s *Server) {
+		s.maxInFlight = n
+	}
+}
+
+// WithMaxSubscriptions sets how many wallets a connection may subscribe to.
+func WithMaxSubscriptions(n int) Option {
+	return func(s *Server) {
+		s.maxSubscriptions = n
+	}
+}
+
+// WithWriteTimeout sets how long writing a message may take before the connection is considered dead.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.writeTimeout = d
+	}
+}
+
+// WithRateLimit throttles operations on every wallet to limit, counting them in l.
+func WithRateLimit(l ratelimit.Limiter, limit ratelimit.Limit) Option {
+	return func(s *Server) {
+		s.limiter = l
+		s.walletLimit = limit
+	}
+}
+
+// WithSignedOperations refuses the operations of principals not restricted to an owner, which the REST API
+// requires to sign their transactions with a shared secret. Commands carry no signatures, so such partners
+// have to send their operations to POST /api/v1/wallet. They may still subscribe to wallets.
+func WithSignedOperations() Option {
+	return func(s *Server) {
+		s.signed = true
+	}
+}
+
+// New returns a server performing operations with ws and delivering the events of b.
+func New(ws service.Wallet, b *stream.Broker, opts ...Option) *Server {
+	s := &Server{
+		walletService:    ws,
+		broker:           b,
+		validator:        validator.New(validator.WithRequiredStructEnabled()),
+		buffer:           defaultBuffer,
+		maxInFlight:      defaultMaxInFlight,
+		maxSubscriptions: defaultMaxSubscriptions,
+		writeTimeout:     defaultWriteTimeout,
+		conns:            make(map[*conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and serves it until either side closes it.
+// The request is expected to be authenticated already: its principal, if any, is checked for every
+// command. Connections are accepted from any origin since credentials are sent in headers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	ws := websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler:   s.serve,
+	}
+	ws.ServeHTTP(w, r)
+}
+
+// Shutdown stops reading commands from the connections, waits for the operations in flight to be
+// answered and closes the connections. Connections still open when ctx is done are closed at once.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.conns {
+		c.stopReading()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for c := range s.conns {
+			c.abort()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) serve(ws *websocket.Conn) {
+	ws.MaxPayloadBytes = maxMessageSize
+	c := newConn(s, ws)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		s.wg.Done()
+	}()
+
+	c.run()
+}
@@ -0,0 +1,301 @@
+package socket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/dto"
+	"cmd/app/main.go/internal/model"
+	"cmd/app/main.go/internal/service"
+	mocks "cmd/app/main.go/internal/service/mock"
+	"cmd/app/main.go/internal/stream"
+	"cmd/app/main.go/pkg/money"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+// message is any message sent by the server.
+type message struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Success  bool         `json:"success"`
+	Code     string       `json:"code"`
+	Message  any          `json:"message"`
+	WalletID uuid.UUID    `json:"walletId"`
+	Event    *model.Event `json:"event"`
+	Reason   string       `json:"reason"`
+}
+
+// dial serves s in process for a client authenticated as p and connects to it.
+func dial(t *testing.T, s *Server, p *auth.Principal) *websocket.Conn {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p != nil {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), *p))
+		}
+		s.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	if err != nil {
+		t.Fatal("dial err: ", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func send(t *testing.T, ws *websocket.Conn, cmd any) {
+	err := websocket.JSON.Send(ws, cmd)
+	if err != nil {
+		t.Fatal("send err: ", err)
+	}
+}
+
+func receive(t *testing.T, ws *websocket.Conn) message {
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	var m message
+	err := websocket.JSON.Receive(ws, &m)
+	if err != nil {
+		t.Fatal("receive err: ", err)
+	}
+	return m
+}
+
+func TestSocket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeService := mocks.NewMockWallet(ctrl)
+
+	t.Run("TestSocket_Subscribe", func(t *testing.T) {
+		b := stream.NewBroker(nil)
+		ws := dial(t, New(fakeService, b), nil)
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		send(t, ws, Command{ID: "c1", Type: CommandSubscribe, WalletID: fakeUUID})
+		m := receive(t, ws)
+		if m.ID != "c1" || m.Type != MessageResult || !m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "successful result of c1", m)
+		}
+
+		e := model.Event{Seq: 3, ID: uuid.New(), Type: model.EventWalletCredited, UUID: fakeUUID}
+		b.Publish(e)
+		m = receive(t, ws)
+		if m.Type != MessageEvent || m.WalletID != fakeUUID || m.Event == nil || m.Event.ID != e.ID {
+			t.Errorf("Expected: %v, recieved: %v", e, m)
+		}
+
+		send(t, ws, Command{ID: "c2", Type: CommandUnsubscribe, WalletID: fakeUUID})
+		m = receive(t, ws)
+		if m.ID != "c2" || !m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "successful result of c2", m)
+		}
+
+		// Events of a wallet no longer subscribed to are not pushed.
+		b.Publish(e)
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{}, errors.New("db down"))
+		send(t, ws, Command{ID: "c3", Type: CommandSubscribe, WalletID: fakeUUID})
+		m = receive(t, ws)
+		if m.ID != "c3" || m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "failed result of c3", m)
+		}
+	})
+
+	t.Run("TestSocket_SubscriptionDropped", func(t *testing.T) {
+		b := stream.NewBroker(nil)
+		ws := dial(t, New(fakeService, b), nil)
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		send(t, ws, Command{ID: "c1", Type: CommandSubscribe, WalletID: fakeUUID})
+		receive(t, ws)
+
+		b.Close()
+		m := receive(t, ws)
+		if m.Type != MessageUnsubscribed || m.WalletID != fakeUUID || m.Reason != reasonDropped {
+			t.Errorf("Expected: %v, recieved: %v", reasonDropped, m)
+		}
+	})
+
+	t.Run("TestSocket_Deposit", func(t *testing.T) {
+		ws := dial(t, New(fakeService, stream.NewBroker(nil)), nil)
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID, Balance: money.MustParse("10")}, nil)
+
+		send(t, ws, Command{ID: "pos-1", Type: CommandDeposit, WalletID: fakeUUID, Amount: money.MustParse("10")})
+		m := receive(t, ws)
+		if m.ID != "pos-1" || !m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "successful result of pos-1", m)
+		}
+	})
+
+	t.Run("TestSocket_InsufficientFunds", func(t *testing.T) {
+		ws := dial(t, New(fakeService, stream.NewBroker(nil)), nil)
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "WITHDRAW", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{}, service.ErrInsufficientFunds)
+
+		send(t, ws, Command{ID: "pos-2", Type: CommandWithdraw, WalletID: fakeUUID, Amount: money.MustParse("10")})
+		m := receive(t, ws)
+		if m.ID != "pos-2" || m.Success || m.Code != service.ErrInsufficientFunds.Code {
+			t.Errorf("Expected: %v, recieved: %v", service.ErrInsufficientFunds.Code, m)
+		}
+	})
+
+	t.Run("TestSocket_Validation", func(t *testing.T) {
+		ws := dial(t, New(fakeService, stream.NewBroker(nil)), nil)
+
+		send(t, ws, Command{ID: "c1", Type: "transfer", WalletID: uuid.New()})
+		m := receive(t, ws)
+		if m.ID != "c1" || m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "failed result of c1", m)
+		}
+
+		send(t, ws, Command{ID: "c2", Type: CommandDeposit, WalletID: uuid.New()})
+		m = receive(t, ws)
+		if m.ID != "c2" || m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "failed result of c2", m)
+		}
+
+		err := websocket.Message.Send(ws, "{not json")
+		if err != nil {
+			t.Fatal("send err: ", err)
+		}
+		m = receive(t, ws)
+		if m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "failed result", m)
+		}
+	})
+
+	t.Run("TestSocket_Forbidden", func(t *testing.T) {
+		p := &auth.Principal{ID: "dashboard", Scopes: []string{auth.ScopeWalletsRead}}
+		ws := dial(t, New(fakeService, stream.NewBroker(nil)), p)
+
+		send(t, ws, Command{ID: "c1", Type: CommandDeposit, WalletID: uuid.New(), Amount: money.MustParse("10")})
+		m := receive(t, ws)
+		if m.ID != "c1" || m.Code != codeForbidden {
+			t.Errorf("Expected: %v, recieved: %v", codeForbidden, m)
+		}
+	})
+
+	t.Run("TestSocket_SignedOperations", func(t *testing.T) {
+		partner := &auth.Principal{ID: "acme", Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}}
+		ws := dial(t, New(fakeService, stream.NewBroker(nil), WithSignedOperations()), partner)
+
+		send(t, ws, Command{ID: "c1", Type: CommandDeposit, WalletID: uuid.New(), Amount: money.MustParse("10")})
+		m := receive(t, ws)
+		if m.ID != "c1" || m.Success || m.Code != codeInvalidSignature {
+			t.Errorf("Expected: %v, recieved: %v", codeInvalidSignature, m)
+		}
+	})
+
+	t.Run("TestSocket_SignedOperationsEndUser", func(t *testing.T) {
+		owner := "customer-1"
+		p := &auth.Principal{ID: owner, Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}, Owner: owner}
+		ws := dial(t, New(fakeService, stream.NewBroker(nil), WithSignedOperations()), p)
+		fakeUUID := uuid.New()
+		fakeReq := dto.WalletTransactionRequest{UUID: fakeUUID, Type: "DEPOSIT", Amount: money.MustParse("10")}
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+		fakeService.EXPECT().Transaction(gomock.Any(), fakeReq).Return(model.Wallet{UUID: fakeUUID}, nil)
+
+		send(t, ws, Command{ID: "c1", Type: CommandDeposit, WalletID: fakeUUID, Amount: money.MustParse("10")})
+		m := receive(t, ws)
+		if m.ID != "c1" || !m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "successful result of c1", m)
+		}
+	})
+
+	t.Run("TestSocket_OtherOwner", func(t *testing.T) {
+		owner := "customer-1"
+		p := &auth.Principal{ID: "customer-2", Scopes: []string{auth.ScopeWalletsRead, auth.ScopeWalletsWrite}, Owner: "customer-2"}
+		ws := dial(t, New(fakeService, stream.NewBroker(nil)), p)
+		fakeUUID := uuid.New()
+		fakeService.EXPECT().Balance(gomock.Any(), fakeUUID).Return(model.Wallet{UUID: fakeUUID, OwnerID: &owner}, nil)
+
+		send(t, ws, Command{ID: "c1", Type: CommandWithdraw, WalletID: fakeUUID, Amount: money.MustParse("10")})
+		m := receive(t, ws)
+		if m.ID != "c1" || m.Success || m.Message != "wallet not found" {
+			t.Errorf("Expected: %v, recieved: %v", "wallet not found", m)
+		}
+	})
+
+	t.Run("TestSocket_MaxInFlight", func(t *testing.T) {
+		ws := dial(t, New(fakeService, stream.NewBroker(nil), WithMaxInFlight(1)), nil)
+		fakeUUID := uuid.New()
+		release := make(chan struct{})
+		fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req dto.WalletTransactionRequest) (model.Wallet, error) {
+				<-release
+				return model.Wallet{UUID: fakeUUID}, nil
+			}).Times(2)
+
+		send(t, ws, Command{ID: "c1", Type: CommandDeposit, WalletID: fakeUUID, Amount: money.MustParse("1")})
+		send(t, ws, Command{ID: "c2", Type: CommandDeposit, WalletID: fakeUUID, Amount: money.MustParse("1")})
+		send(t, ws, Command{ID: "c3", Type: CommandUnsubscribe, WalletID: fakeUUID})
+
+		// The connection is not read while c1 runs, so c3 is not answered.
+		ws.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		var m message
+		err := websocket.JSON.Receive(ws, &m)
+		if err == nil {
+			t.Errorf("Expected: %v, recieved: %v", "no message", m)
+		}
+
+		close(release)
+		ids := map[string]bool{}
+		for range 3 {
+			ids[receive(t, ws).ID] = true
+		}
+		if !ids["c1"] || !ids["c2"] || !ids["c3"] {
+			t.Errorf("Expected: %v, recieved: %v", "results of c1, c2 and c3", ids)
+		}
+	})
+
+	t.Run("TestSocket_Shutdown", func(t *testing.T) {
+		s := New(fakeService, stream.NewBroker(nil))
+		ws := dial(t, s, nil)
+		fakeUUID := uuid.New()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		fakeService.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req dto.WalletTransactionRequest) (model.Wallet, error) {
+				close(started)
+				<-release
+				return model.Wallet{UUID: fakeUUID}, nil
+			})
+
+		send(t, ws, Command{ID: "c1", Type: CommandDeposit, WalletID: fakeUUID, Amount: money.MustParse("1")})
+		<-started
+
+		done := make(chan error)
+		go func() {
+			done <- s.Shutdown(t.Context())
+		}()
+		close(release)
+
+		// The operation in flight is answered before the connection is closed.
+		m := receive(t, ws)
+		if m.ID != "c1" || !m.Success {
+			t.Errorf("Expected: %v, recieved: %v", "successful result of c1", m)
+		}
+		err := websocket.JSON.Receive(ws, &m)
+		if !errors.Is(err, io.EOF) {
+			t.Errorf("Expected: %v, recieved: %v", io.EOF, err)
+		}
+		err = <-done
+		if err != nil {
+			t.Error("shutdown err: ", err)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+// Package stream fans the wallet events announced by the database out to live subscribers.
+package stream
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// Default broker settings used unless configured otherwise.
+const (
+	defaultBuffer            = 64
+	defaultMaxStreams        = 1000
+	defaultMaxClientStreams  = 5
+	defaultReconnectDelay    = time.Second
+	defaultMaxReconnectDelay = 30 * time.Second
+)
+
+var (
+	ErrTooManyStreams = errors.New("too many open streams")
+	ErrClosed         = errors.New("stream closed")
+)
+
+// Store announces the events written by every instance. It is implemented by db.Storage.
+type Store interface {
+	ListenEvents(ctx context.Context, fn func(e model.Event)) error
+}
+
+// Broker delivers the events of wallets to their subscribers. Subscribers that do not keep up,
+// or that may have missed events while the broker was reconnecting to the database, are dropped
+// so that they resume from the last event they received.
+type Broker struct {
+	store            Store
+	buffer           int
+	maxStreams       int
+	maxClientStreams int
+
+	mu      sync.Mutex
+	wallets map[uuid.UUID]map[*Subscription]struct{}
+	clients map[string]int
+	streams int
+	closed  bool
+}
+
+// Option customizes the broker created by NewBroker.
+type Option func(*Broker)
+
+// WithBuffer sets how many events may wait for a subscriber before it is dropped.
+func WithBuffer(n int) Option {
+	return func(b *Broker) {
+		b.buffer = n
+	}
+}
+
+// WithMaxStreams sets how many subscriptions may be open in total and per client.
+func WithMaxStreams(total int, perClient int) Option {
+	return func(b *Broker) {
+		b.maxStreams = total
+		b.maxClientStreams = perClient
+	}
+}
+
+// NewBroker returns a broker delivering the events announced by store.
+func NewBroker(store Store, opts ...Option) *Broker {
+	b := &Broker{
+		store:            store,
+		buffer:           defaultBuffer,
+		maxStreams:       defaultMaxStreams,
+		maxClientStreams: defaultMaxClientStreams,
+		wallets:          make(map[uuid.UUID]map[*Subscription]struct{}),
+		clients:          make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Run listens for events until ctx is done, listening again with a growing delay when the
+// connection breaks. Subscribers are dropped on every reconnection.
+func (b *Broker) Run(ctx context.Context) {
+	delay := defaultReconnectDelay
+	for {
+		err := b.store.ListenEvents(ctx, b.Publish)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Println("stream broker listen err: ", err)
+		b.dropAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, defaultMaxReconnectDelay)
+	}
+}
+
+// Publish delivers e to the subscribers of its wallet without blocking.
+func (b *Broker) Publish(e model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.wallets[e.UUID] {
+		select {
+		case s.events <- e:
+		default:
+			b.drop(s)
+		}
+	}
+}
+
+// Subscribe opens a subscription to the events of wallet on behalf of client. It fails with
+// ErrTooManyStreams when the client or the broker already has the maximum number of subscriptions
+// and with ErrClosed once the broker is closed.
+func (b *Broker) Subscribe(wallet uuid.UUID, client string) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+	if b.streams >= b.maxStreams || b.clients[client] >= b.maxClientStreams {
+		return nil, ErrTooManyStreams
+	}
+
+	s := &Subscription{
+		broker: b,
+		wallet: wallet,
+		client: client,
+		events: make(chan model.Event, b.buffer),
+		done:   make(chan struct{}),
+	}
+	if b.wallets[wallet] == nil {
+		b.wallets[wallet] = make(map[*Subscription]struct{})
+	}
+	b.wallets[wallet][s] = struct{}{}
+	b.clients[client]++
+	b.streams++
+	return s, nil
+}
+
+// Close drops every subscription and rejects new ones, letting the streams end.
+// It is meant to be registered with http.Server.RegisterOnShutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.dropAllLocked()
+}
+
+func (b *Broker) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.dropAllLocked()
+}
+
+func (b *Broker) dropAllLocked() {
+	for _, subs := range b.wallets {
+		for s := range subs {
+			b.drop(s)
+		}
+	}
+}
+
+// drop removes s and closes its done channel. It must be called with the lock held.
+func (b *Broker) drop(s *Subscription) {
+	subs, ok := b.wallets[s.wallet]
+	if !ok {
+		return
+	}
+	if _, ok := subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(b.wallets, s.wallet)
+	}
+	b.clients[s.client]--
+	if b.clients[s.client] == 0 {
+		delete(b.clients, s.client)
+	}
+	b.streams--
+	close(s.done)
+}
+
+// Subscription receives the events of a wallet.
+type Subscription struct {
+	broker *Broker
+	wallet uuid.UUID
+	client string
+	events chan model.Event
+	done   chan struct{}
+}
+
+// Events returns the channel the events of the wallet are delivered to.
+func (s *Subscription) Events() <-chan model.Event {
+	return s.events
+}
+
+// Done returns a channel closed when the subscription is dropped by the broker or closed.
+// Events still buffered may be read after it is closed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.drop(s)
+}
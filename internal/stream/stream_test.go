@@ -0,0 +1,167 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// fakeStore fails to listen as many times as set in failures, then listens until ctx is done.
+type fakeStore struct {
+	failures int
+}
+
+func (s *fakeStore) ListenEvents(ctx context.Context, fn func(e model.Event)) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("connection reset")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func isDone(s *Subscription) bool {
+	select {
+	case <-s.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestBroker(t *testing.T) {
+	t.Run("TestBroker_Publish", func(t *testing.T) {
+		b := NewBroker(&fakeStore{})
+		wallet := uuid.New()
+		sub, err := b.Subscribe(wallet, "client:acme")
+		if err != nil {
+			t.Fatal("subscribe err: ", err)
+		}
+		other, err := b.Subscribe(uuid.New(), "client:acme")
+		if err != nil {
+			t.Fatal("subscribe err: ", err)
+		}
+
+		e := model.Event{Seq: 1, ID: uuid.New(), Type: model.EventWalletCredited, UUID: wallet}
+		b.Publish(e)
+
+		select {
+		case got := <-sub.Events():
+			if !reflect.DeepEqual(e, got) {
+				t.Errorf("Expected: %v, recieved: %v", e, got)
+			}
+		default:
+			t.Error("event not delivered")
+		}
+		if len(other.Events()) != 0 {
+			t.Errorf("Expected: %v, recieved: %v", 0, len(other.Events()))
+		}
+	})
+
+	t.Run("TestBroker_SlowSubscriber", func(t *testing.T) {
+		b := NewBroker(&fakeStore{}, WithBuffer(1))
+		wallet := uuid.New()
+		sub, err := b.Subscribe(wallet, "client:acme")
+		if err != nil {
+			t.Fatal("subscribe err: ", err)
+		}
+
+		b.Publish(model.Event{Seq: 1, UUID: wallet})
+		if isDone(sub) {
+			t.Error("subscriber dropped before its buffer is full")
+		}
+		b.Publish(model.Event{Seq: 2, UUID: wallet})
+		if !isDone(sub) {
+			t.Error("slow subscriber not dropped")
+		}
+	})
+
+	t.Run("TestBroker_MaxClientStreams", func(t *testing.T) {
+		b := NewBroker(&fakeStore{}, WithMaxStreams(10, 2))
+		for range 2 {
+			_, err := b.Subscribe(uuid.New(), "client:acme")
+			if err != nil {
+				t.Fatal("subscribe err: ", err)
+			}
+		}
+
+		_, err := b.Subscribe(uuid.New(), "client:acme")
+		if !errors.Is(err, ErrTooManyStreams) {
+			t.Errorf("Expected: %v, recieved: %v", ErrTooManyStreams, err)
+		}
+		sub, err := b.Subscribe(uuid.New(), "client:other")
+		if err != nil {
+			t.Error("subscribe err: ", err)
+		}
+
+		sub.Close()
+		sub.Close()
+		_, err = b.Subscribe(uuid.New(), "client:other")
+		if err != nil {
+			t.Error("subscribe after close err: ", err)
+		}
+	})
+
+	t.Run("TestBroker_MaxStreams", func(t *testing.T) {
+		b := NewBroker(&fakeStore{}, WithMaxStreams(1, 5))
+		_, err := b.Subscribe(uuid.New(), "client:acme")
+		if err != nil {
+			t.Fatal("subscribe err: ", err)
+		}
+
+		_, err = b.Subscribe(uuid.New(), "client:other")
+		if !errors.Is(err, ErrTooManyStreams) {
+			t.Errorf("Expected: %v, recieved: %v", ErrTooManyStreams, err)
+		}
+	})
+
+	t.Run("TestBroker_Close", func(t *testing.T) {
+		b := NewBroker(&fakeStore{})
+		sub, err := b.Subscribe(uuid.New(), "client:acme")
+		if err != nil {
+			t.Fatal("subscribe err: ", err)
+		}
+
+		b.Close()
+		if !isDone(sub) {
+			t.Error("subscription not dropped on close")
+		}
+		_, err = b.Subscribe(uuid.New(), "client:acme")
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected: %v, recieved: %v", ErrClosed, err)
+		}
+	})
+
+	t.Run("TestBroker_ListenFails", func(t *testing.T) {
+		b := NewBroker(&fakeStore{failures: 1})
+		sub, err := b.Subscribe(uuid.New(), "client:acme")
+		if err != nil {
+			t.Fatal("subscribe err: ", err)
+		}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		done := make(chan struct{})
+		go func() {
+			b.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-sub.Done():
+		case <-time.After(time.Second):
+			t.Error("subscription not dropped after the listener failed")
+		}
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("broker still running after cancel")
+		}
+	})
+}
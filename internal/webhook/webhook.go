@@ -0,0 +1,308 @@
+// Package webhook delivers wallet events to the endpoints registered by integrators.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"cmd/app/main.go/internal/auth"
+	"cmd/app/main.go/internal/model"
+)
+
+// Headers of webhook requests. The signature is computed like the one of signed partner requests,
+// with the delivery id as the nonce: the hex encoded HMAC-SHA256 of "<timestamp>.<delivery id>.<body>"
+// keyed with the webhook secret.
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Default dispatcher settings used unless configured otherwise.
+const (
+	defaultWorkers     = 4
+	defaultInterval    = time.Second
+	defaultBatchSize   = 10
+	defaultTimeout     = 10 * time.Second
+	defaultMaxAttempts = 8
+	defaultBackoff     = 10 * time.Second
+	defaultMaxBackoff  = time.Hour
+)
+
+// maxErrorBody limits how much of a failed response is kept as the delivery error.
+const maxErrorBody = 512
+
+// Store keeps webhooks and their deliveries. It is implemented by db.Storage.
+type Store interface {
+	EnqueueDeliveries(ctx context.Context, e model.Event) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+	SaveDelivery(ctx context.Context, d model.WebhookDelivery) error
+}
+
+// Publisher schedules the deliveries of published events to the subscribed webhooks.
+// Used by the outbox relay, it schedules them in the transaction marking the event as published.
+type Publisher struct {
+	store Store
+}
+
+// NewPublisher returns a publisher scheduling deliveries in store.
+func NewPublisher(store Store) *Publisher {
+	return &Publisher{store: store}
+}
+
+// Publish schedules the delivery of e to every webhook subscribed to its type.
+func (p *Publisher) Publish(ctx context.Context, e model.Event) error {
+	return p.store.EnqueueDeliveries(ctx, e)
+}
+
+// Dispatcher posts scheduled deliveries to their webhooks from a pool of workers.
+// Failed deliveries are retried with exponential backoff and marked dead after the maximum
+// number of attempts, after which only a redelivery schedules them again.
+type Dispatcher struct {
+	store       Store
+	client      *http.Client
+	workers     int
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	now         func() time.Time
+
+	stop   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Option customizes the dispatcher created by NewDispatcher.
+type Option func(*Dispatcher)
+
+// WithWorkers sets how many deliveries are posted concurrently.
+func WithWorkers(n int) Option {
+	return func(d *Dispatcher) {
+		d.workers = n
+	}
+}
+
+// WithInterval sets how often idle workers look for due deliveries.
+func WithInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.interval = interval
+	}
+}
+
+// WithTimeout sets how long a webhook has to respond.
+func WithTimeout(timeout time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.client.Timeout = timeout
+	}
+}
+
+// WithMaxAttempts sets after how many failed attempts a delivery is dead.
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the delay before the first retry, which doubles with every further attempt up to max.
+func WithBackoff(initial time.Duration, max time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.backoff = initial
+		d.maxBackoff = max
+	}
+}
+
+// NewDispatcher returns a dispatcher delivering the deliveries of store.
+func NewDispatcher(store Store, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:       store,
+		client:      &http.Client{Timeout: defaultTimeout},
+		workers:     defaultWorkers,
+		interval:    defaultInterval,
+		batchSize:   defaultBatchSize,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		now:         time.Now,
+		stop:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Start runs the workers until Shutdown is called or ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.work(ctx)
+		}()
+	}
+}
+
+// Shutdown stops the workers from claiming deliveries and waits for the deliveries in flight to finish.
+// When ctx is done first, deliveries in flight are aborted and retried after their lease.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	close(d.stop)
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.abort()
+		return nil
+	case <-ctx.Done():
+		d.abort()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// abort cancels the deliveries in flight, if the workers were started.
+func (d *Dispatcher) abort() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+}
+
+// work delivers due deliveries batch after batch, waiting for the interval whenever none are due.
+func (d *Dispatcher) work(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := d.Deliver(ctx)
+		if err != nil {
+			log.Println("webhook dispatcher err: ", err)
+		}
+		if n > 0 && err == nil {
+			select {
+			case <-d.stop:
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-d.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deliver claims one batch of due deliveries, posts them and saves their outcome.
+// It returns how many deliveries were attempted.
+func (d *Dispatcher) Deliver(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimDeliveries(ctx, d.batchSize, d.lease())
+	if err != nil {
+		return 0, err
+	}
+	for i, del := range deliveries {
+		select {
+		case <-d.stop:
+			// the remaining deliveries are retried once their lease expires
+			return i, nil
+		default:
+		}
+
+		d.attempt(ctx, &del)
+		if ctx.Err() != nil {
+			// aborted attempts are retried once their lease expires
+			return i, nil
+		}
+		err = d.store.SaveDelivery(context.WithoutCancel(ctx), del)
+		if err != nil {
+			return i + 1, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// lease is how long a claimed batch is reserved for the worker posting it.
+func (d *Dispatcher) lease() time.Duration {
+	return time.Duration(d.batchSize+1) * d.client.Timeout
+}
+
+// attempt posts del to its webhook and records the outcome in del.
+func (d *Dispatcher) attempt(ctx context.Context, del *model.WebhookDelivery) {
+	status, err := d.post(ctx, *del)
+	now := d.now()
+	if err == nil {
+		del.Status = model.DeliveryDelivered
+		del.DeliveredAt = &now
+		del.LastStatus = &status
+		del.LastError = nil
+		return
+	}
+
+	msg := err.Error()
+	del.LastError = &msg
+	del.LastStatus = nil
+	if status != 0 {
+		del.LastStatus = &status
+	}
+	if del.Attempts >= d.maxAttempts {
+		del.Status = model.DeliveryDead
+		return
+	}
+	del.Status = model.DeliveryPending
+	del.NextAttemptAt = now.Add(d.retryDelay(del.Attempts))
+}
+
+// retryDelay is the delay before the attempt following the given number of attempts.
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.backoff
+	for i := 1; i < attempts && delay < d.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.maxBackoff)
+}
+
+// post sends the signed payload of del and returns the response status.
+// Responses other than 2xx are errors.
+func (d *Dispatcher) post(ctx context.Context, del model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.URL, bytes.NewReader(del.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, del.EventType)
+	req.Header.Set(DeliveryHeader, del.ID.String())
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, auth.Sign([]byte(del.Secret), timestamp, del.ID.String(), del.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("webhook responded %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+	return resp.StatusCode, nil
+}
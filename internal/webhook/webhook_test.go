@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cmd/app/main.go/internal/auth"
+	mocks "cmd/app/main.go/internal/db/mock"
+	"cmd/app/main.go/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func TestDispatcherDeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+
+	secret := "0123456789abcdef"
+	now := time.Date(2026, 2, 16, 12, 0, 0, 0, time.UTC)
+	d := NewDispatcher(fakeDB, WithMaxAttempts(3), WithBackoff(10*time.Second, time.Minute))
+	d.now = func() time.Time { return now }
+
+	fakeDelivery := func(url string, attempts int) model.WebhookDelivery {
+		id := uuid.New()
+		return model.WebhookDelivery{
+			ID:        id,
+			WebhookID: uuid.New(),
+			URL:       url,
+			Secret:    secret,
+			EventID:   uuid.New(),
+			EventType: model.EventWalletCredited,
+			Payload:   json.RawMessage(`{"type":"wallet.credited"}`),
+			Status:    model.DeliveryPending,
+			Attempts:  attempts,
+		}
+	}
+
+	t.Run("TestDispatcherDeliver_Signed", func(t *testing.T) {
+		var received atomic.Bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			ts := r.Header.Get(TimestampHeader)
+			expected := auth.Sign([]byte(secret), ts, r.Header.Get(DeliveryHeader), body)
+			if r.Header.Get(SignatureHeader) != expected {
+				t.Errorf("Expected: %v, recieved: %v", expected, r.Header.Get(SignatureHeader))
+			}
+			if r.Header.Get(EventHeader) != model.EventWalletCredited {
+				t.Errorf("Expected: %v, recieved: %v", model.EventWalletCredited, r.Header.Get(EventHeader))
+			}
+			received.Store(true)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		del := fakeDelivery(srv.URL, 1)
+		fakeDB.EXPECT().ClaimDeliveries(gomock.Any(), defaultBatchSize, gomock.Any()).Return([]model.WebhookDelivery{del}, nil)
+		fakeDB.EXPECT().SaveDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, saved model.WebhookDelivery) error {
+			if saved.Status != model.DeliveryDelivered || saved.DeliveredAt == nil || *saved.LastStatus != http.StatusNoContent {
+				t.Errorf("Expected: %v, recieved: %v", model.DeliveryDelivered, saved)
+			}
+			return nil
+		})
+
+		n, err := d.Deliver(t.Context())
+		if err != nil || n != 1 {
+			t.Errorf("Expected: %v, recieved: %v %v", 1, n, err)
+		}
+		if !received.Load() {
+			t.Error("webhook not received")
+		}
+	})
+
+	t.Run("TestDispatcherDeliver_Retry", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		del := fakeDelivery(srv.URL, 2)
+		fakeDB.EXPECT().ClaimDeliveries(gomock.Any(), defaultBatchSize, gomock.Any()).Return([]model.WebhookDelivery{del}, nil)
+		fakeDB.EXPECT().SaveDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, saved model.WebhookDelivery) error {
+			correctNext := now.Add(20 * time.Second)
+			if saved.Status != model.DeliveryPending || !saved.NextAttemptAt.Equal(correctNext) {
+				t.Errorf("Expected: %v at %v, recieved: %v at %v", model.DeliveryPending, correctNext, saved.Status, saved.NextAttemptAt)
+			}
+			if saved.LastStatus == nil || *saved.LastStatus != http.StatusServiceUnavailable || saved.LastError == nil {
+				t.Errorf("Expected: %v, recieved: %v", http.StatusServiceUnavailable, saved.LastStatus)
+			}
+			return nil
+		})
+
+		_, err := d.Deliver(t.Context())
+		if err != nil {
+			t.Error("deliver err: ", err)
+		}
+	})
+
+	t.Run("TestDispatcherDeliver_Dead", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		del := fakeDelivery(srv.URL, 3)
+		fakeDB.EXPECT().ClaimDeliveries(gomock.Any(), defaultBatchSize, gomock.Any()).Return([]model.WebhookDelivery{del}, nil)
+		fakeDB.EXPECT().SaveDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, saved model.WebhookDelivery) error {
+			if saved.Status != model.DeliveryDead {
+				t.Errorf("Expected: %v, recieved: %v", model.DeliveryDead, saved.Status)
+			}
+			return nil
+		})
+
+		_, err := d.Deliver(t.Context())
+		if err != nil {
+			t.Error("deliver err: ", err)
+		}
+	})
+
+	t.Run("TestDispatcherDeliver_Unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		del := fakeDelivery(srv.URL, 1)
+		fakeDB.EXPECT().ClaimDeliveries(gomock.Any(), defaultBatchSize, gomock.Any()).Return([]model.WebhookDelivery{del}, nil)
+		fakeDB.EXPECT().SaveDelivery(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, saved model.WebhookDelivery) error {
+			if saved.Status != model.DeliveryPending || saved.LastStatus != nil || saved.LastError == nil {
+				t.Errorf("Expected: %v without status, recieved: %v", model.DeliveryPending, saved)
+			}
+			return nil
+		})
+
+		_, err := d.Deliver(t.Context())
+		if err != nil {
+			t.Error("deliver err: ", err)
+		}
+	})
+}
+
+func TestDispatcherRetryDelay(t *testing.T) {
+	d := NewDispatcher(nil, WithBackoff(10*time.Second, time.Minute))
+	correct := map[int]time.Duration{
+		1: 10 * time.Second,
+		2: 20 * time.Second,
+		3: 40 * time.Second,
+		4: time.Minute,
+		9: time.Minute,
+	}
+	for attempts, delay := range correct {
+		got := d.retryDelay(attempts)
+		if got != delay {
+			t.Errorf("Expected: %v, recieved: %v", delay, got)
+		}
+	}
+}
+
+func TestDispatcherShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+	fakeDB.EXPECT().ClaimDeliveries(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	d := NewDispatcher(fakeDB, WithWorkers(2), WithInterval(10*time.Millisecond))
+	d.Start(context.Background())
+	time.Sleep(30 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := d.Shutdown(ctx)
+	if err != nil {
+		t.Error("shutdown err: ", err)
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	fakeDB := mocks.NewMockStorage(ctrl)
+
+	e := model.Event{ID: uuid.New(), Type: model.EventTransferCompleted}
+	fakeDB.EXPECT().EnqueueDeliveries(gomock.Any(), e).Return(nil)
+
+	err := NewPublisher(fakeDB).Publish(t.Context(), e)
+	if err != nil {
+		t.Error("publish err: ", err)
+	}
+}
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- wallets without an id or sharing one hold money that cannot be attributed safely,
+-- so they are left to an operator to resolve instead of being removed or merged here
+DO $$
+DECLARE
+    missing BIGINT;
+    duplicated BIGINT;
+BEGIN
+    SELECT count(*) INTO missing FROM wallets WHERE uuid IS NULL;
+    SELECT count(*) INTO duplicated FROM (SELECT uuid FROM wallets WHERE uuid IS NOT NULL GROUP BY uuid HAVING count(*) > 1) d;
+    IF missing > 0 OR duplicated > 0 THEN
+        RAISE EXCEPTION 'cannot add the wallets primary key: % wallets have no uuid and % uuids are shared by several wallets', missing, duplicated
+            USING HINT = 'Remove or reassign them, for example with SELECT uuid, count(*) FROM wallets GROUP BY uuid HAVING uuid IS NULL OR count(*) > 1, then run the migration again.';
+    END IF;
+END;
+$$;
+
+ALTER TABLE wallets ADD PRIMARY KEY (uuid);
+
+-- amount is the signed change applied to the wallet balance,
+-- balance is the wallet balance right after the change.
+CREATE TABLE wallet_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    wallet_uuid UUID NOT NULL REFERENCES wallets(uuid),
+    type VARCHAR(32) NOT NULL,
+    amount NUMERIC(16, 2) NOT NULL,
+    balance NUMERIC(16, 2) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX wallet_transactions_wallet_uuid_idx ON public.wallet_transactions(wallet_uuid, id);
+
+-- opening entries keep existing balances reconstructible from the ledger
+INSERT INTO
+    wallet_transactions (wallet_uuid, type, amount, balance)
+SELECT
+    uuid, 'OPENING', balance, balance
+FROM
+    wallets
+WHERE
+    balance <> 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS wallet_transactions;
+
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_pkey;
+-- +goose StatementEnd
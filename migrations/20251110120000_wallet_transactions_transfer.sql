@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- entries written by the same operation (e.g. both legs of a transfer) share operation_id
+ALTER TABLE wallet_transactions
+    ADD COLUMN operation_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN counterparty_uuid UUID REFERENCES wallets(uuid);
+
+CREATE INDEX wallet_transactions_operation_id_idx ON public.wallet_transactions(operation_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallet_transactions
+    DROP COLUMN IF EXISTS counterparty_uuid,
+    DROP COLUMN IF EXISTS operation_id;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- NOT VALID skips balances that already went negative before the check existed,
+-- every new or updated row is still checked.
+ALTER TABLE wallets
+    ADD CONSTRAINT wallets_balance_non_negative CHECK (balance >= 0) NOT VALID;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_balance_non_negative;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wallets
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+
+-- four decimal places cover the minor units of every ISO 4217 currency
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE NUMERIC(18, 4);
+
+ALTER TABLE wallet_transactions
+    ALTER COLUMN amount TYPE NUMERIC(18, 4),
+    ALTER COLUMN balance TYPE NUMERIC(18, 4);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallet_transactions
+    ALTER COLUMN amount TYPE NUMERIC(16, 2),
+    ALTER COLUMN balance TYPE NUMERIC(16, 2);
+
+ALTER TABLE wallets
+    ALTER COLUMN balance TYPE NUMERIC(16, 2);
+
+ALTER TABLE wallets
+    DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
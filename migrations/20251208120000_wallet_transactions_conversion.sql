@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- for transfers, counter_amount and counter_currency describe the other leg of the operation,
+-- rate is the exchange rate applied between the source and the destination currency.
+ALTER TABLE wallet_transactions
+    ADD COLUMN counter_amount NUMERIC(18, 4),
+    ADD COLUMN counter_currency CHAR(3),
+    ADD COLUMN rate NUMERIC(28, 10);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallet_transactions
+    DROP COLUMN IF EXISTS rate,
+    DROP COLUMN IF EXISTS counter_currency,
+    DROP COLUMN IF EXISTS counter_amount;
+-- +goose StatementEnd
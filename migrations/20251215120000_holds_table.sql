@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- held is the sum of active holds of the wallet, the available balance is balance - held.
+ALTER TABLE wallets
+    ADD COLUMN held NUMERIC(18, 4) NOT NULL DEFAULT 0,
+    ADD CONSTRAINT wallets_held_within_balance CHECK (held >= 0 AND held <= balance) NOT VALID;
+
+-- amount is the reserved amount, captured is the part of it taken from the wallet on capture
+-- by the ledger operation operation_id. Active holds past expires_at are released by the sweeper.
+CREATE TABLE holds (
+    id UUID PRIMARY KEY,
+    wallet_uuid UUID NOT NULL REFERENCES wallets(uuid),
+    amount NUMERIC(18, 4) NOT NULL CHECK (amount > 0),
+    captured NUMERIC(18, 4) NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL DEFAULT 'ACTIVE',
+    operation_id UUID,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX holds_wallet_uuid_idx ON public.holds(wallet_uuid);
+CREATE INDEX holds_active_expires_at_idx ON public.holds(expires_at) WHERE status = 'ACTIVE';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS holds;
+
+ALTER TABLE wallets
+    DROP CONSTRAINT IF EXISTS wallets_held_within_balance,
+    DROP COLUMN IF EXISTS held;
+-- +goose StatementEnd
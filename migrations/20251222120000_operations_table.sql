@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- one row per money-moving operation, its ledger entries share operation_id.
+-- wallet_uuid is the debited wallet of transfers, amount is the operation amount in its currency
+-- and counter_amount the amount credited to counterparty_uuid. reversed and counter_reversed
+-- sum up the reversals of the operation, which point back to it through reversal_of.
+CREATE TABLE operations (
+    id UUID PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    wallet_uuid UUID NOT NULL REFERENCES wallets(uuid),
+    counterparty_uuid UUID REFERENCES wallets(uuid),
+    amount NUMERIC(18, 4) NOT NULL CHECK (amount >= 0),
+    counter_amount NUMERIC(18, 4),
+    reversed NUMERIC(18, 4) NOT NULL DEFAULT 0 CHECK (reversed <= amount),
+    counter_reversed NUMERIC(18, 4) NOT NULL DEFAULT 0,
+    reversal_of UUID REFERENCES operations(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX operations_reversal_of_idx ON public.operations(reversal_of);
+
+-- the debit leg of a transfer sorts first and describes the operation
+INSERT INTO
+    operations (id, type, wallet_uuid, counterparty_uuid, amount, counter_amount, created_at)
+SELECT DISTINCT ON (operation_id)
+    operation_id,
+    type,
+    wallet_uuid,
+    counterparty_uuid,
+    abs(amount),
+    CASE WHEN type = 'TRANSFER' THEN COALESCE(counter_amount, -amount) END,
+    created_at
+FROM
+    wallet_transactions
+ORDER BY
+    operation_id, amount;
+
+ALTER TABLE wallet_transactions
+    ALTER COLUMN operation_id DROP DEFAULT,
+    ADD CONSTRAINT wallet_transactions_operation_id_fkey FOREIGN KEY (operation_id) REFERENCES operations(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallet_transactions
+    DROP CONSTRAINT IF EXISTS wallet_transactions_operation_id_fkey,
+    ALTER COLUMN operation_id SET DEFAULT gen_random_uuid();
+
+DROP TABLE IF EXISTS operations;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE wallets
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'ACTIVE'
+        CONSTRAINT wallets_status_check CHECK (status IN ('ACTIVE', 'FROZEN', 'CLOSED'));
+
+-- every status change with the reason given for it
+CREATE TABLE wallet_status_changes (
+    id BIGSERIAL PRIMARY KEY,
+    wallet_uuid UUID NOT NULL REFERENCES wallets(uuid),
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX wallet_status_changes_wallet_uuid_idx ON public.wallet_status_changes(wallet_uuid, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS wallet_status_changes;
+
+ALTER TABLE wallets DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- owner_id is the external reference of the wallet owner, wallets created without one stay anonymous
+ALTER TABLE wallets
+    ADD COLUMN owner_id VARCHAR(255),
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX wallets_owner_id_idx ON public.wallets(owner_id, currency) WHERE owner_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE wallets
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS owner_id;
+-- +goose StatementEnd
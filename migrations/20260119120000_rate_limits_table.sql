@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- token buckets shared by all replicas, stored as the theoretical arrival time of the next request (GCRA)
+CREATE UNLOGGED TABLE rate_limits (
+    key TEXT PRIMARY KEY,
+    tat TIMESTAMPTZ NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rate_limits;
+-- +goose StatementEnd
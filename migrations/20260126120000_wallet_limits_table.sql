@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- spending limits of a wallet in its currency, NULL meaning no limit
+CREATE TABLE wallet_limits (
+    wallet_uuid UUID PRIMARY KEY REFERENCES wallets(uuid),
+    max_withdrawal NUMERIC(18, 4) CHECK (max_withdrawal > 0),
+    daily_debit NUMERIC(18, 4) CHECK (daily_debit > 0),
+    monthly_debit NUMERIC(18, 4) CHECK (monthly_debit > 0),
+    max_balance NUMERIC(18, 4) CHECK (max_balance > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- debit totals are summed over the recent ledger entries of a wallet
+CREATE INDEX wallet_transactions_wallet_uuid_created_at_idx ON public.wallet_transactions(wallet_uuid, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS wallet_transactions_wallet_uuid_created_at_idx;
+
+DROP TABLE IF EXISTS wallet_limits;
+-- +goose StatementEnd
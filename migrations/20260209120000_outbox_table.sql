@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- domain events written together with the changes they describe and relayed to publishers
+CREATE TABLE outbox (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    wallet_uuid UUID NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ
+);
+
+-- the relay only looks for events not published yet
+CREATE INDEX outbox_pending_idx ON public.outbox(seq) WHERE published_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
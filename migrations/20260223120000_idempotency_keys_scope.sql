@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys are stored prefixed with the principal that sent them, which may exceed the 255 characters of the key.
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM idempotency_keys WHERE length(key) > 255;
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(255);
+-- +goose StatementEnd
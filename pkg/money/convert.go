@@ -0,0 +1,86 @@
+package money
+
+import (
+	"errors"
+	"math/big"
+)
+
+// RateScale is the number of decimal places exchange rates are kept with.
+const RateScale = 10
+
+// RoundingMode tells how values are rounded to a limited number of decimal places.
+type RoundingMode int
+
+const (
+	// HalfEven rounds to the nearest value and ties to the even neighbour (banker's rounding).
+	HalfEven RoundingMode = iota
+	// HalfUp rounds to the nearest value and ties away from zero.
+	HalfUp
+	// Down rounds towards zero.
+	Down
+	// Up rounds away from zero.
+	Up
+)
+
+var ErrRoundingMode = errors.New("unknown rounding mode")
+
+// ParseRoundingMode converts a name such as "HALF_EVEN" into a RoundingMode.
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch s {
+	case "HALF_EVEN":
+		return HalfEven, nil
+	case "HALF_UP":
+		return HalfUp, nil
+	case "DOWN":
+		return Down, nil
+	case "UP":
+		return Up, nil
+	}
+	return 0, ErrRoundingMode
+}
+
+// Round returns x rounded to the given number of decimal places.
+func Round(x *big.Rat, decimals int, mode RoundingMode) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	num := new(big.Int).Mul(x.Num(), scale)
+	den := x.Denom()
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() != 0 {
+		// twice the remainder compared to the denominator tells where x lies between q and q±1
+		half := new(big.Int).Abs(r)
+		half.Lsh(half, 1)
+		cmp := half.Cmp(den)
+
+		away := false
+		switch mode {
+		case Up:
+			away = true
+		case HalfUp:
+			away = cmp >= 0
+		case HalfEven:
+			away = cmp > 0 || (cmp == 0 && q.Bit(0) == 1)
+		}
+		if away {
+			q.Add(q, big.NewInt(int64(num.Sign())))
+		}
+	}
+	return new(big.Rat).SetFrac(q, scale)
+}
+
+// Convert multiplies the amount by rate and rounds the result to the given number of decimal places.
+func Convert(a Amount, rate *big.Rat, decimals int, mode RoundingMode) (Amount, error) {
+	if decimals > Scale {
+		decimals = Scale
+	}
+	x := Round(new(big.Rat).Mul(a.Rat(), rate), decimals, mode)
+	units := new(big.Rat).Mul(x, big.NewRat(unit, 1))
+	if !units.IsInt() || !units.Num().IsInt64() {
+		return 0, ErrOverflow
+	}
+	res := Amount(units.Num().Int64())
+	if res > Max || res < -Max {
+		return 0, ErrOverflow
+	}
+	return res, nil
+}
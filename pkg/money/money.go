@@ -0,0 +1,208 @@
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scale is the number of fractional digits kept by Amount.
+const Scale = 4
+
+// unit is the number of Amount units in one whole currency unit.
+const unit = 10000
+
+// Max is the largest amount that fits into the NUMERIC columns used for money.
+const Max = Amount(1e14*unit - 1)
+
+var (
+	ErrInvalid   = errors.New("invalid amount")
+	ErrPrecision = errors.New("amount has too many decimal places")
+	ErrOverflow  = errors.New("amount is out of range")
+)
+
+// Amount is an exact decimal amount of money, stored as an integer number of 10^-Scale units.
+// The zero value is zero.
+type Amount int64
+
+// Parse converts a decimal string such as "-12.34" into an Amount.
+// Exponents are not accepted, and neither are more than Scale decimal places.
+func Parse(s string) (Amount, error) {
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || !digits(whole) || !digits(frac) {
+		return 0, ErrInvalid
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > Scale {
+		return 0, ErrPrecision
+	}
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > int64(Max/unit) {
+		return 0, ErrOverflow
+	}
+	var f int64
+	if frac != "" {
+		f, _ = strconv.ParseInt(frac+strings.Repeat("0", Scale-len(frac)), 10, 64)
+	}
+
+	a := Amount(w*unit + f)
+	if a > Max {
+		return 0, ErrOverflow
+	}
+	if neg {
+		a = -a
+	}
+	return a, nil
+}
+
+// MustParse is like Parse but panics on malformed input. It is intended for constants and tests.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("money: parse %q: %v", s, err))
+	}
+	return a
+}
+
+// digits reports whether s consists of ASCII digits only.
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the amount in decimal notation without trailing fractional zeros, e.g. "100" or "0.1".
+func (a Amount) String() string {
+	return a.Format(a.Decimals())
+}
+
+// Format returns the amount in decimal notation with exactly the given number of decimal places.
+// Places beyond Scale are padded with zeros, and digits that do not fit are truncated.
+func (a Amount) Format(decimals int) string {
+	sign := ""
+	u := uint64(a)
+	if a < 0 {
+		sign = "-"
+		u = uint64(-a)
+	}
+	whole := strconv.FormatUint(u/unit, 10)
+	if decimals <= 0 {
+		return sign + whole
+	}
+	frac := fmt.Sprintf("%0*d", Scale, u%unit)
+	if decimals <= Scale {
+		frac = frac[:decimals]
+	} else {
+		frac += strings.Repeat("0", decimals-Scale)
+	}
+	return sign + whole + "." + frac
+}
+
+// Decimals returns the number of significant decimal places of the amount.
+func (a Amount) Decimals() int {
+	frac := int64(a) % unit
+	if frac == 0 {
+		return 0
+	}
+	n := Scale
+	for frac%10 == 0 {
+		frac /= 10
+		n--
+	}
+	return n
+}
+
+// Add returns a + b or ErrOverflow if the result does not fit.
+func (a Amount) Add(b Amount) (Amount, error) {
+	c := a + b
+	if c > Max || c < -Max {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+// Sub returns a - b or ErrOverflow if the result does not fit.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	return a.Add(-b)
+}
+
+// IsPositive reports whether the amount is greater than zero.
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// Rat returns the amount as an exact rational number.
+func (a Amount) Rat() *big.Rat {
+	return big.NewRat(int64(a), unit)
+}
+
+// MarshalJSON encodes the amount as a JSON number.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON decodes the amount from a JSON number or a JSON string holding a number.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	data = bytes.Trim(data, `"`)
+	v, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner, so Amount can be read from NUMERIC columns.
+// It fails instead of rounding when the value has more than Scale decimal places.
+func (a *Amount) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		*a = 0
+		return nil
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return ErrInvalid
+	}
+
+	n := new(big.Int).Set(v.Int)
+	exp := int64(v.Exp) + Scale
+	if exp >= 0 {
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	} else {
+		var rem big.Int
+		n.QuoRem(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(-exp), nil), &rem)
+		if rem.Sign() != 0 {
+			return ErrPrecision
+		}
+	}
+	if !n.IsInt64() {
+		return ErrOverflow
+	}
+	*a = Amount(n.Int64())
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer, so Amount can be passed as a NUMERIC query argument.
+func (a Amount) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(a)), Exp: -Scale, Valid: true}, nil
+}
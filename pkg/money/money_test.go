@@ -0,0 +1,180 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in  string
+		out Amount
+		err error
+	}{
+		{in: "0", out: 0},
+		{in: "100", out: 1000000},
+		{in: "0.1", out: 1000},
+		{in: "-12.34", out: -123400},
+		{in: "1.23450", out: 12345},
+		{in: "1.23456", err: ErrPrecision},
+		{in: "1e3", err: ErrInvalid},
+		{in: ".5", err: ErrInvalid},
+		{in: "5.", err: ErrInvalid},
+		{in: "", err: ErrInvalid},
+		{in: "100000000000000", err: ErrOverflow},
+	}
+	for _, tt := range tests {
+		out, err := Parse(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) err. Expected: %v, recieved: %v", tt.in, tt.err, err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("Parse(%q). Expected: %d, recieved: %d", tt.in, tt.out, out)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := map[Amount]string{
+		0:        "0",
+		1000000:  "100",
+		1000:     "0.1",
+		-123400:  "-12.34",
+		12345:    "1.2345",
+		-1:       "-0.0001",
+		10000001: "1000.0001",
+	}
+	for in, out := range tests {
+		if in.String() != out {
+			t.Errorf("Expected: %s, recieved: %s", out, in.String())
+		}
+	}
+	if MustParse("1.5").Format(2) != "1.50" {
+		t.Errorf("Expected: 1.50, recieved: %s", MustParse("1.5").Format(2))
+	}
+}
+
+func TestDecimals(t *testing.T) {
+	tests := map[string]int{
+		"USD": 2,
+		"EUR": 2,
+		"JPY": 0,
+		"BHD": 3,
+		"CLF": 4,
+	}
+	for currency, decimals := range tests {
+		if Decimals(currency) != decimals {
+			t.Errorf("Decimals(%s). Expected: %d, recieved: %d", currency, decimals, Decimals(currency))
+		}
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	sum, err := MustParse("0.1").Add(MustParse("0.2"))
+	if err != nil || sum != MustParse("0.3") {
+		t.Errorf("Expected: 0.3, recieved: %v, err: %v", sum, err)
+	}
+	_, err = Max.Add(MustParse("0.0001"))
+	if !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected: %v, recieved: %v", ErrOverflow, err)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		Amount Amount `json:"amount"`
+	}
+	for _, in := range []string{`{"amount":10.25}`, `{"amount":"10.25"}`} {
+		err := json.Unmarshal([]byte(in), &v)
+		if err != nil || v.Amount != MustParse("10.25") {
+			t.Errorf("unmarshal %s. Expected: 10.25, recieved: %v, err: %v", in, v.Amount, err)
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) != `{"amount":10.25}` {
+		t.Errorf("marshal. Expected: %s, recieved: %s, err: %v", `{"amount":10.25}`, data, err)
+	}
+	err = json.Unmarshal([]byte(`{"amount":0.00001}`), &v)
+	if !errors.Is(err, ErrPrecision) {
+		t.Errorf("Expected: %v, recieved: %v", ErrPrecision, err)
+	}
+}
+
+func TestAmountNumeric(t *testing.T) {
+	m := pgtype.NewMap()
+	for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+		in := MustParse("-1234.56")
+		buf, err := m.Encode(pgtype.NumericOID, format, in, nil)
+		if err != nil {
+			t.Fatal("encode err: ", err)
+		}
+		var out Amount
+		err = m.Scan(pgtype.NumericOID, format, buf, &out)
+		if err != nil {
+			t.Fatal("scan err: ", err)
+		}
+		if in != out {
+			t.Errorf("Expected: %v, recieved: %v", in, out)
+		}
+	}
+
+	var out Amount
+	err := m.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte("1.23456"), &out)
+	if err == nil {
+		t.Error("Expected excess precision to fail")
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode RoundingMode
+		out  string
+	}{
+		{in: "2.345", mode: HalfEven, out: "2.34"},
+		{in: "2.355", mode: HalfEven, out: "2.36"},
+		{in: "2.345", mode: HalfUp, out: "2.35"},
+		{in: "-2.345", mode: HalfUp, out: "-2.35"},
+		{in: "2.349", mode: Down, out: "2.34"},
+		{in: "-2.349", mode: Down, out: "-2.34"},
+		{in: "2.341", mode: Up, out: "2.35"},
+		{in: "2.34", mode: Up, out: "2.34"},
+	}
+	for _, tt := range tests {
+		in, _ := new(big.Rat).SetString(tt.in)
+		out := Round(in, 2, tt.mode).FloatString(2)
+		if out != tt.out {
+			t.Errorf("Round(%s, %d). Expected: %s, recieved: %s", tt.in, tt.mode, tt.out, out)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	rate, _ := new(big.Rat).SetString("150.255")
+	res, err := Convert(MustParse("10.01"), rate, Decimals("JPY"), HalfEven)
+	if err != nil || res != MustParse("1504") {
+		t.Errorf("Expected: 1504, recieved: %v, err: %v", res, err)
+	}
+
+	rate, _ = new(big.Rat).SetString("0.376")
+	res, err = Convert(MustParse("100"), rate, Decimals("BHD"), HalfEven)
+	if err != nil || res != MustParse("37.6") {
+		t.Errorf("Expected: 37.6, recieved: %v, err: %v", res, err)
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	mode, err := ParseRoundingMode("HALF_UP")
+	if err != nil || mode != HalfUp {
+		t.Errorf("Expected: %v, recieved: %v, err: %v", HalfUp, mode, err)
+	}
+	_, err = ParseRoundingMode("CEILING")
+	if !errors.Is(err, ErrRoundingMode) {
+		t.Errorf("Expected: %v, recieved: %v", ErrRoundingMode, err)
+	}
+}
@@ -0,0 +1,686 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: api/wallet/v1/wallet.proto
+
+package walletpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OperationType is the kind of operation behind a ledger entry.
+type OperationType int32
+
+const (
+	OperationType_OPERATION_TYPE_UNSPECIFIED OperationType = 0
+	OperationType_OPERATION_TYPE_OPENING     OperationType = 1
+	OperationType_OPERATION_TYPE_DEPOSIT     OperationType = 2
+	OperationType_OPERATION_TYPE_WITHDRAW    OperationType = 3
+	OperationType_OPERATION_TYPE_TRANSFER    OperationType = 4
+	OperationType_OPERATION_TYPE_CAPTURE     OperationType = 5
+	OperationType_OPERATION_TYPE_REVERSAL    OperationType = 6
+)
+
+// Enum value maps for OperationType.
+var (
+	OperationType_name = map[int32]string{
+		0: "OPERATION_TYPE_UNSPECIFIED",
+		1: "OPERATION_TYPE_OPENING",
+		2: "OPERATION_TYPE_DEPOSIT",
+		3: "OPERATION_TYPE_WITHDRAW",
+		4: "OPERATION_TYPE_TRANSFER",
+		5: "OPERATION_TYPE_CAPTURE",
+		6: "OPERATION_TYPE_REVERSAL",
+	}
+	OperationType_value = map[string]int32{
+		"OPERATION_TYPE_UNSPECIFIED": 0,
+		"OPERATION_TYPE_OPENING":     1,
+		"OPERATION_TYPE_DEPOSIT":     2,
+		"OPERATION_TYPE_WITHDRAW":    3,
+		"OPERATION_TYPE_TRANSFER":    4,
+		"OPERATION_TYPE_CAPTURE":     5,
+		"OPERATION_TYPE_REVERSAL":    6,
+	}
+)
+
+func (x OperationType) Enum() *OperationType {
+	p := new(OperationType)
+	*p = x
+	return p
+}
+
+func (x OperationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OperationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_wallet_v1_wallet_proto_enumTypes[0].Descriptor()
+}
+
+func (OperationType) Type() protoreflect.EnumType {
+	return &file_api_wallet_v1_wallet_proto_enumTypes[0]
+}
+
+func (x OperationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OperationType.Descriptor instead.
+func (OperationType) EnumDescriptor() ([]byte, []int) {
+	return file_api_wallet_v1_wallet_proto_rawDescGZIP(), []int{0}
+}
+
+// Wallet is a wallet state. Amounts are decimal strings such as "12.34".
+type Wallet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	Balance       string                 `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Available     string                 `protobuf:"bytes,3,opt,name=available,proto3" json:"available,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	OwnerId       *string                `protobuf:"bytes,6,opt,name=owner_id,json=ownerId,proto3,oneof" json:"owner_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Wallet) Reset() {
+	*x = Wallet{}
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Wallet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Wallet) ProtoMessage() {}
+
+func (x *Wallet) ProtoReflect() protoreflect.Message {
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Wallet.ProtoReflect.Descriptor instead.
+func (*Wallet) Descriptor() ([]byte, []int) {
+	return file_api_wallet_v1_wallet_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Wallet) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *Wallet) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *Wallet) GetAvailable() string {
+	if x != nil {
+		return x.Available
+	}
+	return ""
+}
+
+func (x *Wallet) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Wallet) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Wallet) GetOwnerId() string {
+	if x != nil && x.OwnerId != nil {
+		return *x.OwnerId
+	}
+	return ""
+}
+
+type CreateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO 4217 currency of the wallet; the default currency if empty.
+	Currency      string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	OwnerId       string `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_api_wallet_v1_wallet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+type BalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BalanceRequest) Reset() {
+	*x = BalanceRequest{}
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceRequest) ProtoMessage() {}
+
+func (x *BalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceRequest.ProtoReflect.Descriptor instead.
+func (*BalanceRequest) Descriptor() ([]byte, []int) {
+	return file_api_wallet_v1_wallet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BalanceRequest) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+type TransactionRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WalletId string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	// One of OPERATION_TYPE_DEPOSIT, OPERATION_TYPE_WITHDRAW and OPERATION_TYPE_TRANSFER.
+	OperationType OperationType `protobuf:"varint,2,opt,name=operation_type,json=operationType,proto3,enum=wallet.v1.OperationType" json:"operation_type,omitempty"`
+	Amount        string        `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Destination of transfers.
+	ToWalletId string `protobuf:"bytes,4,opt,name=to_wallet_id,json=toWalletId,proto3" json:"to_wallet_id,omitempty"`
+	// Currency of the amount; the currency of the wallet if empty.
+	Currency      string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionRequest) Reset() {
+	*x = TransactionRequest{}
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionRequest) ProtoMessage() {}
+
+func (x *TransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionRequest.ProtoReflect.Descriptor instead.
+func (*TransactionRequest) Descriptor() ([]byte, []int) {
+	return file_api_wallet_v1_wallet_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransactionRequest) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetOperationType() OperationType {
+	if x != nil {
+		return x.OperationType
+	}
+	return OperationType_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *TransactionRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetToWalletId() string {
+	if x != nil {
+		return x.ToWalletId
+	}
+	return ""
+}
+
+func (x *TransactionRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type HistoryRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WalletId string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	// Only entries of this type if set.
+	OperationType OperationType `protobuf:"varint,2,opt,name=operation_type,json=operationType,proto3,enum=wallet.v1.OperationType" json:"operation_type,omitempty"`
+	// Only entries created at or after from, and before to, if set.
+	From *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	// Maximum number of entries streamed; all of them if zero.
+	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_wallet_v1_wallet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *HistoryRequest) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetOperationType() OperationType {
+	if x != nil {
+		return x.OperationType
+	}
+	return OperationType_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *HistoryRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *HistoryRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *HistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// LedgerEntry is a change of a wallet balance.
+type LedgerEntry struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OperationId          string                 `protobuf:"bytes,2,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	WalletId             string                 `protobuf:"bytes,3,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	OperationType        OperationType          `protobuf:"varint,4,opt,name=operation_type,json=operationType,proto3,enum=wallet.v1.OperationType" json:"operation_type,omitempty"`
+	Amount               string                 `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Balance              string                 `protobuf:"bytes,6,opt,name=balance,proto3" json:"balance,omitempty"`
+	CounterpartyWalletId *string                `protobuf:"bytes,7,opt,name=counterparty_wallet_id,json=counterpartyWalletId,proto3,oneof" json:"counterparty_wallet_id,omitempty"`
+	CounterAmount        *string                `protobuf:"bytes,8,opt,name=counter_amount,json=counterAmount,proto3,oneof" json:"counter_amount,omitempty"`
+	CounterCurrency      *string                `protobuf:"bytes,9,opt,name=counter_currency,json=counterCurrency,proto3,oneof" json:"counter_currency,omitempty"`
+	Rate                 *string                `protobuf:"bytes,10,opt,name=rate,proto3,oneof" json:"rate,omitempty"`
+	CreatedAt            *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *LedgerEntry) Reset() {
+	*x = LedgerEntry{}
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerEntry) ProtoMessage() {}
+
+func (x *LedgerEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_wallet_v1_wallet_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerEntry.ProtoReflect.Descriptor instead.
+func (*LedgerEntry) Descriptor() ([]byte, []int) {
+	return file_api_wallet_v1_wallet_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LedgerEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LedgerEntry) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetOperationType() OperationType {
+	if x != nil {
+		return x.OperationType
+	}
+	return OperationType_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *LedgerEntry) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCounterpartyWalletId() string {
+	if x != nil && x.CounterpartyWalletId != nil {
+		return *x.CounterpartyWalletId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCounterAmount() string {
+	if x != nil && x.CounterAmount != nil {
+		return *x.CounterAmount
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCounterCurrency() string {
+	if x != nil && x.CounterCurrency != nil {
+		return *x.CounterCurrency
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetRate() string {
+	if x != nil && x.Rate != nil {
+		return *x.Rate
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+var File_api_wallet_v1_wallet_proto protoreflect.FileDescriptor
+
+const file_api_wallet_v1_wallet_proto_rawDesc = "" +
+	"\n" +
+	"\x1aapi/wallet/v1/wallet.proto\x12\twallet.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbe\x01\n" +
+	"\x06Wallet\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\tR\abalance\x12\x1c\n" +
+	"\tavailable\x18\x03 \x01(\tR\tavailable\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1e\n" +
+	"\bowner_id\x18\x06 \x01(\tH\x00R\aownerId\x88\x01\x01B\v\n" +
+	"\t_owner_id\"F\n" +
+	"\rCreateRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\"-\n" +
+	"\x0eBalanceRequest\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId\"\xc8\x01\n" +
+	"\x12TransactionRequest\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId\x12?\n" +
+	"\x0eoperation_type\x18\x02 \x01(\x0e2\x18.wallet.v1.OperationTypeR\roperationType\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12 \n" +
+	"\fto_wallet_id\x18\x04 \x01(\tR\n" +
+	"toWalletId\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"\xe0\x01\n" +
+	"\x0eHistoryRequest\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId\x12?\n" +
+	"\x0eoperation_type\x18\x02 \x01(\x0e2\x18.wallet.v1.OperationTypeR\roperationType\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\x87\x04\n" +
+	"\vLedgerEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12!\n" +
+	"\foperation_id\x18\x02 \x01(\tR\voperationId\x12\x1b\n" +
+	"\twallet_id\x18\x03 \x01(\tR\bwalletId\x12?\n" +
+	"\x0eoperation_type\x18\x04 \x01(\x0e2\x18.wallet.v1.OperationTypeR\roperationType\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\tR\x06amount\x12\x18\n" +
+	"\abalance\x18\x06 \x01(\tR\abalance\x129\n" +
+	"\x16counterparty_wallet_id\x18\a \x01(\tH\x00R\x14counterpartyWalletId\x88\x01\x01\x12*\n" +
+	"\x0ecounter_amount\x18\b \x01(\tH\x01R\rcounterAmount\x88\x01\x01\x12.\n" +
+	"\x10counter_currency\x18\t \x01(\tH\x02R\x0fcounterCurrency\x88\x01\x01\x12\x17\n" +
+	"\x04rate\x18\n" +
+	" \x01(\tH\x03R\x04rate\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\x19\n" +
+	"\x17_counterparty_wallet_idB\x11\n" +
+	"\x0f_counter_amountB\x13\n" +
+	"\x11_counter_currencyB\a\n" +
+	"\x05_rate*\xda\x01\n" +
+	"\rOperationType\x12\x1e\n" +
+	"\x1aOPERATION_TYPE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16OPERATION_TYPE_OPENING\x10\x01\x12\x1a\n" +
+	"\x16OPERATION_TYPE_DEPOSIT\x10\x02\x12\x1b\n" +
+	"\x17OPERATION_TYPE_WITHDRAW\x10\x03\x12\x1b\n" +
+	"\x17OPERATION_TYPE_TRANSFER\x10\x04\x12\x1a\n" +
+	"\x16OPERATION_TYPE_CAPTURE\x10\x05\x12\x1b\n" +
+	"\x17OPERATION_TYPE_REVERSAL\x10\x062\x80\x02\n" +
+	"\rWalletService\x125\n" +
+	"\x06Create\x12\x18.wallet.v1.CreateRequest\x1a\x11.wallet.v1.Wallet\x127\n" +
+	"\aBalance\x12\x19.wallet.v1.BalanceRequest\x1a\x11.wallet.v1.Wallet\x12?\n" +
+	"\vTransaction\x12\x1d.wallet.v1.TransactionRequest\x1a\x11.wallet.v1.Wallet\x12>\n" +
+	"\aHistory\x12\x19.wallet.v1.HistoryRequest\x1a\x16.wallet.v1.LedgerEntry0\x01B'Z%cmd/app/main.go/pkg/walletpb;walletpbb\x06proto3"
+
+var (
+	file_api_wallet_v1_wallet_proto_rawDescOnce sync.Once
+	file_api_wallet_v1_wallet_proto_rawDescData []byte
+)
+
+func file_api_wallet_v1_wallet_proto_rawDescGZIP() []byte {
+	file_api_wallet_v1_wallet_proto_rawDescOnce.Do(func() {
+		file_api_wallet_v1_wallet_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_wallet_v1_wallet_proto_rawDesc), len(file_api_wallet_v1_wallet_proto_rawDesc)))
+	})
+	return file_api_wallet_v1_wallet_proto_rawDescData
+}
+
+var file_api_wallet_v1_wallet_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_wallet_v1_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_wallet_v1_wallet_proto_goTypes = []any{
+	(OperationType)(0),            // 0: wallet.v1.OperationType
+	(*Wallet)(nil),                // 1: wallet.v1.Wallet
+	(*CreateRequest)(nil),         // 2: wallet.v1.CreateRequest
+	(*BalanceRequest)(nil),        // 3: wallet.v1.BalanceRequest
+	(*TransactionRequest)(nil),    // 4: wallet.v1.TransactionRequest
+	(*HistoryRequest)(nil),        // 5: wallet.v1.HistoryRequest
+	(*LedgerEntry)(nil),           // 6: wallet.v1.LedgerEntry
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_api_wallet_v1_wallet_proto_depIdxs = []int32{
+	0,  // 0: wallet.v1.TransactionRequest.operation_type:type_name -> wallet.v1.OperationType
+	0,  // 1: wallet.v1.HistoryRequest.operation_type:type_name -> wallet.v1.OperationType
+	7,  // 2: wallet.v1.HistoryRequest.from:type_name -> google.protobuf.Timestamp
+	7,  // 3: wallet.v1.HistoryRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 4: wallet.v1.LedgerEntry.operation_type:type_name -> wallet.v1.OperationType
+	7,  // 5: wallet.v1.LedgerEntry.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 6: wallet.v1.WalletService.Create:input_type -> wallet.v1.CreateRequest
+	3,  // 7: wallet.v1.WalletService.Balance:input_type -> wallet.v1.BalanceRequest
+	4,  // 8: wallet.v1.WalletService.Transaction:input_type -> wallet.v1.TransactionRequest
+	5,  // 9: wallet.v1.WalletService.History:input_type -> wallet.v1.HistoryRequest
+	1,  // 10: wallet.v1.WalletService.Create:output_type -> wallet.v1.Wallet
+	1,  // 11: wallet.v1.WalletService.Balance:output_type -> wallet.v1.Wallet
+	1,  // 12: wallet.v1.WalletService.Transaction:output_type -> wallet.v1.Wallet
+	6,  // 13: wallet.v1.WalletService.History:output_type -> wallet.v1.LedgerEntry
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_wallet_v1_wallet_proto_init() }
+func file_api_wallet_v1_wallet_proto_init() {
+	if File_api_wallet_v1_wallet_proto != nil {
+		return
+	}
+	file_api_wallet_v1_wallet_proto_msgTypes[0].OneofWrappers = []any{}
+	file_api_wallet_v1_wallet_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_wallet_v1_wallet_proto_rawDesc), len(file_api_wallet_v1_wallet_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_wallet_v1_wallet_proto_goTypes,
+		DependencyIndexes: file_api_wallet_v1_wallet_proto_depIdxs,
+		EnumInfos:         file_api_wallet_v1_wallet_proto_enumTypes,
+		MessageInfos:      file_api_wallet_v1_wallet_proto_msgTypes,
+	}.Build()
+	File_api_wallet_v1_wallet_proto = out.File
+	file_api_wallet_v1_wallet_proto_goTypes = nil
+	file_api_wallet_v1_wallet_proto_depIdxs = nil
+}